@@ -0,0 +1,145 @@
+package aetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Diff returns a field-by-field description of how actual differs from expected (primary code,
+// error codes, HTTP status, message, retryability and data keys), one line per difference, or an
+// empty string when they match. Volatile fields such as the error ID and stack are ignored and
+// data is compared by its JSON representation.
+func Diff(expected, actual *ae.AppError) string {
+	if expected == nil || actual == nil {
+		if expected == actual {
+			return ""
+		}
+		return fmt.Sprintf("error: expected %s, got %s", describe(expected), describe(actual))
+	}
+
+	d := &differ{}
+	d.compare("code", code(expected), code(actual))
+	d.compare("error codes", expected.GetErrCodes(), actual.GetErrCodes())
+	d.compare("http code", expected.GetHTTPCode(), actual.GetHTTPCode())
+	d.compare("message", message(expected), message(actual))
+	d.compare("retryable", retryable(expected), retryable(actual))
+	d.data("data", normalize(expected.GetData()), normalize(actual.GetData()))
+	return strings.Join(d.lines, "\n")
+}
+
+// differ collects difference lines
+type differ struct {
+	lines []string
+}
+
+// compare records a difference when the values are not equal; nil and empty slices are equal
+func (d *differ) compare(field string, expected, actual interface{}) {
+	if reflect.DeepEqual(expected, actual) {
+		return
+	}
+	ev, av := reflect.ValueOf(expected), reflect.ValueOf(actual)
+	if ev.Kind() == reflect.Slice && av.Kind() == reflect.Slice && ev.Len() == 0 && av.Len() == 0 {
+		return
+	}
+	d.lines = append(d.lines, fmt.Sprintf("%s: expected %s, got %s", field, format(expected), format(actual)))
+}
+
+// data compares generic JSON values key by key, descending into objects and arrays
+func (d *differ) data(path string, expected, actual interface{}) {
+	em, eok := expected.(map[string]interface{})
+	am, aok := actual.(map[string]interface{})
+	if eok && aok {
+		keys := make([]string, 0, len(em)+len(am))
+		for key := range em {
+			keys = append(keys, key)
+		}
+		for key := range am {
+			if _, ok := em[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			ev, inExpected := em[key]
+			av, inActual := am[key]
+			switch {
+			case !inActual:
+				d.lines = append(d.lines, fmt.Sprintf("%s.%s: missing, expected %s", path, key, format(ev)))
+			case !inExpected:
+				d.lines = append(d.lines, fmt.Sprintf("%s.%s: unexpected %s", path, key, format(av)))
+			default:
+				d.data(path+"."+key, ev, av)
+			}
+		}
+		return
+	}
+
+	el, eok := expected.([]interface{})
+	al, aok := actual.([]interface{})
+	if eok && aok && len(el) == len(al) {
+		for i := range el {
+			d.data(fmt.Sprintf("%s[%d]", path, i), el[i], al[i])
+		}
+		return
+	}
+	d.compare(path, expected, actual)
+}
+
+// normalize round-trips data through JSON into generic values, keeping it as is when unencodable
+func normalize(v interface{}) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// format renders a value for a difference line
+func format(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	if raw, err := json.Marshal(v); err == nil {
+		return string(raw)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// describe renders an AppError briefly for nil mismatches
+func describe(appErr *ae.AppError) string {
+	if appErr == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%s (%d)", code(appErr), appErr.GetHTTPCode())
+}
+
+// code returns the primary code, empty when the AppError has no CustomErr
+func code(appErr *ae.AppError) string {
+	if appErr.CustomErr == nil {
+		return ""
+	}
+	return appErr.CustomErr.Code
+}
+
+// message returns the client-facing message, empty when the AppError has no CustomErr
+func message(appErr *ae.AppError) string {
+	if appErr.CustomErr == nil {
+		return ""
+	}
+	return appErr.CustomErr.Message
+}
+
+// retryable reports the retryability, false when the AppError has no CustomErr
+func retryable(appErr *ae.AppError) bool {
+	return appErr.CustomErr != nil && appErr.CustomErr.Retryable
+}
@@ -0,0 +1,49 @@
+package aetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	notFound := ae.GetCustomErr("ERR_DIFF_1", "not found", false)
+	conflict := ae.GetCustomErr("ERR_DIFF_2", "conflict", true)
+	build := func(customErr *ae.CustomErr, httpCode int, data ...interface{}) *ae.AppError {
+		return ae.GetAppErr(ctx, errors.New("cause"), customErr, httpCode, data...)
+	}
+
+	tests := []struct {
+		name     string
+		expected *ae.AppError
+		actual   *ae.AppError
+		want     string
+	}{
+		{"both nil", nil, nil, ""},
+		{"nil actual", build(notFound, 404), nil, `error: expected ERR_DIFF_1 (404), got nil`},
+		{"equal", build(notFound, 404, map[string]int{"id": 1}), build(notFound, 404, map[string]interface{}{"id": 1}), ""},
+		{"status", build(notFound, 404), build(notFound, 410), `http code: expected 404, got 410`},
+		{"code, message and retryable", build(notFound, 404), build(conflict, 404),
+			`code: expected "ERR_DIFF_1", got "ERR_DIFF_2"` + "\n" +
+				`error codes: expected ["ERR_DIFF_1"], got ["ERR_DIFF_2"]` + "\n" +
+				`message: expected "not found", got "conflict"` + "\n" +
+				`retryable: expected false, got true`},
+		{"data keys", build(notFound, 404, map[string]interface{}{"id": 1, "gone": true, "nested": map[string]interface{}{"a": []int{1, 2}}}),
+			build(notFound, 404, map[string]interface{}{"id": 2, "extra": "x", "nested": map[string]interface{}{"a": []int{1, 3}}}),
+			`data.extra: unexpected "x"` + "\n" +
+				`data.gone: missing, expected true` + "\n" +
+				`data.id: expected 1, got 2` + "\n" +
+				`data.nested.a[1]: expected 2, got 3`},
+		{"data lengths", build(notFound, 404, []int{1}), build(notFound, 404, []int{1, 2}), `data: expected [1], got [1,2]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.expected, tt.actual); got != tt.want {
+				t.Errorf("Diff =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// defaultAlertClient is used by the alerting sinks unless another client is configured
+var defaultAlertClient = &http.Client{Timeout: 10 * time.Second}
+
+// CodeFamilies maps code prefixes (code families such as "ERR_PAYMENT_") to per-family settings;
+// the longest matching prefix wins and the empty prefix acts as the default
+type CodeFamilies map[string]string
+
+// Lookup returns the setting of the longest prefix matching code
+func (f CodeFamilies) Lookup(code string) (string, bool) {
+	best, value, found := -1, "", false
+	for prefix, v := range f {
+		if strings.HasPrefix(code, prefix) && len(prefix) > best {
+			best, value, found = len(prefix), v, true
+		}
+	}
+	return value, found
+}
+
+// postJSON POSTs v as JSON and fails on non-2xx responses; errors name the host only, since
+// webhook URLs embed credentials
+func postJSON(ctx context.Context, client *http.Client, endpoint string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	host := sinkHost(endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid alert endpoint for %s", host)
+	}
+	req.Header.Set(c.HeaderContentType, c.ContentTypeJSON)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return sinkError(endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s responded with status %d: %s", host, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// sinkError reports a failed request to an alert endpoint by host, dropping the full URL that
+// net/http puts in its errors
+func sinkError(endpoint string, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return fmt.Errorf("%s %s: %w", http.MethodPost, sinkHost(endpoint), err)
+}
+
+// sinkHost returns the host of an alert endpoint, or a fixed name when it does not parse
+func sinkHost(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "alert endpoint"
+}
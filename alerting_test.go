@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// capturePagerDuty returns a server recording the dedup key of each event it receives
+func capturePagerDuty(t *testing.T, keys *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			DedupKey string `json:"dedup_key"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decoding event: %v", err)
+		}
+		*keys = append(*keys, event.DedupKey)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPagerDutyDedupKey(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("db down"),
+		GetCustomErr("ERR_PD_1", "unavailable", true), http.StatusServiceUnavailable)
+
+	tests := []struct {
+		name     string
+		grouping GroupingStrategy
+		want     string
+	}{
+		{"fingerprint by default", nil, appErr.Fingerprint()},
+		{"grouping override", GroupByCode, GroupByCode.GroupKey(appErr)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			srv := capturePagerDuty(t, &keys)
+			var reporter Reporter = NewPagerDutyReporter("routing", WithPagerDutyEndpoint(srv.URL))
+			if tt.grouping != nil {
+				reporter = WithGrouping(reporter, tt.grouping)
+			}
+			if err := reporter.Report(context.Background(), appErr); err != nil {
+				t.Fatalf("Report: %v", err)
+			}
+			if len(keys) != 1 || keys[0] != tt.want {
+				t.Errorf("dedup keys = %v, want [%s]", keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostJSONErrorsHideEndpoint(t *testing.T) {
+	const secret = "/services/T000/B000/s3cr3t"
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer failing.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+	}{
+		{"error status", failing.URL + secret, strings.TrimPrefix(failing.URL, "http://")},
+		{"unreachable", closed.URL + secret, strings.TrimPrefix(closed.URL, "http://")},
+		{"invalid url", "http://[::1" + secret, "alert endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := postJSON(context.Background(), http.DefaultClient, tt.endpoint, map[string]string{})
+			if err == nil {
+				t.Fatal("postJSON succeeded, want an error")
+			}
+			if strings.Contains(err.Error(), "s3cr3t") || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("postJSON error = %q, want %q without the URL path", err, tt.want)
+			}
+		})
+	}
+}
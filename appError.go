@@ -2,23 +2,108 @@ package errors
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
 )
 
 // AppError represents a structured error with additional metadata
 type AppError struct {
-	ActualErr  error       // The actual underlying error
-	CustomErr  *CustomErr  // Custom error details (code, message, etc.)
-	ErrorCodes []string    // All error codes encountered during execution
-	httpCode   int         // Corresponding HTTP error code
-	data       interface{} // Additional data to include in the error response
+	ActualErr   error                  // The actual underlying error
+	CustomErr   *CustomErr             // Custom error details (code, message, etc.)
+	ErrorCodes  []string               // All error codes encountered during execution
+	httpCode    int                    // Corresponding HTTP error code
+	data        interface{}            // Additional data to include in the error response
+	id          string                 // Unique identifier, generated on first use
+	headers     http.Header            // Extra headers to send with the HTTP response
+	retryAfter  time.Duration          // How long clients should wait before retrying
+	maxAttempts int                    // How many attempts in total clients should make, 0 when unset
+	backoff     BackoffStrategy        // How clients should space out their retries, empty when unset
+	internalMsg string                 // Operator-facing detail, logged but never serialized
+	traceID     string                 // ID of the distributed trace the error was recorded in
+	spanID      string                 // ID of the span the error was recorded on
+	fingerprint string                 // Fingerprint override, empty to compute it
+	stack       []uintptr              // Program counters captured where the error was created
+	contexts    []string               // Contextual annotations added by WrapMsg, oldest first
+	wrapSites   []uintptr              // Program counters of the places that wrapped this error chain, oldest first
+	labels      map[string]string      // Dimensions such as the tenant, used as metric labels and reporter tags
+	debug       map[string]interface{} // Diagnostics that never reach clients, e.g. goroutine dumps
+	identifiers map[string]interface{} // Trace identifiers of the context the error was created with
+
+	replayStack     []Frame // Stack restored from a recording, used when no program counters were captured
+	replayWrapSites []Frame // Wrap sites restored from a recording, preceding the wrapSites
+
+	// Inline storage backing CustomErr, ErrorCodes and stack, so an error created without a
+	// TraceMeta costs a single allocation
+	customErrBuf CustomErr
+	codeBuf      [1]string
+	stackBuf     [defaultStackDepth]uintptr
 }
 
-// Error implements the error interface, returning the error message
+// Error implements the error interface, returning the error message prefixed with the contextual
+// annotations, most recent first
 func (e *AppError) Error() string {
-	if e.ActualErr == nil {
-		return ""
+	msg := ""
+	if e.ActualErr != nil {
+		msg = e.ActualErr.Error()
+	}
+	for _, annotation := range e.contexts {
+		if msg == "" {
+			msg = annotation
+			continue
+		}
+		msg = annotation + ": " + msg
 	}
-	return e.ActualErr.Error()
+	return msg
+}
+
+// WrapMsg annotates the error with context (e.g. "while refreshing cache") visible in Error() and
+// logs, leaving the client-facing message and code untouched, and returns the AppError
+func (e *AppError) WrapMsg(msg string) *AppError {
+	if msg != "" {
+		e.contexts = append(e.contexts, msg)
+	}
+	return e
+}
+
+// GetContexts retrieves the contextual annotations added by WrapMsg, oldest first
+func (e *AppError) GetContexts() []string {
+	return e.contexts
+}
+
+// Unwrap returns the underlying error, letting errors.Is and errors.As traverse into the cause
+func (e *AppError) Unwrap() error {
+	return e.ActualErr
+}
+
+// Is reports whether target is an AppError or CustomErr with the same primary code, so
+// errors.Is(err, OnDBPingFailure) matches any AppError created from that custom error
+func (e *AppError) Is(target error) bool {
+	code := primaryCode(e)
+	if code == "" {
+		return false
+	}
+
+	switch t := target.(type) {
+	case *AppError:
+		return t != nil && primaryCode(t) == code
+	case *CustomErr:
+		return t != nil && t.Code == code
+	}
+	return false
+}
+
+// As lets errors.As extract the custom error details of the first AppError in a chain:
+// var ce *ae.CustomErr; errors.As(err, &ce)
+func (e *AppError) As(target interface{}) bool {
+	if t, ok := target.(**CustomErr); ok && e.CustomErr != nil {
+		*t = e.CustomErr
+		return true
+	}
+	return false
 }
 
 // GetErr retrieves the underlying error
@@ -43,6 +128,18 @@ func (e *AppError) SetMsg(msg string) *AppError {
 	return e
 }
 
+// GetInternalMsg retrieves the operator-facing message
+func (e *AppError) GetInternalMsg() string {
+	return e.internalMsg
+}
+
+// SetInternalMsg sets an operator-facing message with debugging detail; unlike the custom error
+// message it only appears in logs and never in serialized output, and it returns the AppError
+func (e *AppError) SetInternalMsg(msg string) *AppError {
+	e.internalMsg = msg
+	return e
+}
+
 // GetHTTPCode retrieves the HTTP status code
 func (e *AppError) GetHTTPCode() int {
 	return e.httpCode
@@ -90,17 +187,127 @@ func (e *AppError) SetData(data interface{}) *AppError {
 	return e
 }
 
-// GetAppErr creates a new instance of AppError
+// GetHeaders retrieves the extra headers sent with the HTTP response
+func (e *AppError) GetHeaders() http.Header {
+	return e.headers
+}
+
+// SetHeader sets an extra header sent with the HTTP response and returns the AppError
+func (e *AppError) SetHeader(key, value string) *AppError {
+	if e.headers == nil {
+		e.headers = http.Header{}
+	}
+	e.headers.Set(key, value)
+	return e
+}
+
+// GetRetryAfter retrieves how long clients should wait before retrying
+func (e *AppError) GetRetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// SetRetryAfter updates how long clients should wait before retrying and returns the AppError
+func (e *AppError) SetRetryAfter(d time.Duration) *AppError {
+	e.retryAfter = d
+	return e
+}
+
+// GetLabels retrieves the labels of the error
+func (e *AppError) GetLabels() map[string]string {
+	return e.labels
+}
+
+// GetLabel retrieves a single label of the error
+func (e *AppError) GetLabel(key string) string {
+	return e.labels[key]
+}
+
+// SetLabel sets a label used as metric dimension and reporter tag and returns the AppError
+func (e *AppError) SetLabel(key, value string) *AppError {
+	if e.labels == nil {
+		e.labels = map[string]string{}
+	}
+	e.labels[key] = value
+	return e
+}
+
+// GetTenant retrieves the tenant the error occurred for
+func (e *AppError) GetTenant() string {
+	return e.labels[c.TenantLabel]
+}
+
+// GetDebug retrieves the debug data, which is only included in internal and debug output
+func (e *AppError) GetDebug() map[string]interface{} {
+	return e.debug
+}
+
+// GetIdentifiers returns a copy of the trace identifiers of the context the AppError was created
+// with, nil when there were none
+func (e *AppError) GetIdentifiers() map[string]interface{} {
+	return copyIdentifiers(e.identifiers)
+}
+
+// SetDebug adds debug data that never reaches clients and returns the AppError
+func (e *AppError) SetDebug(key string, value interface{}) *AppError {
+	if e.debug == nil {
+		e.debug = map[string]interface{}{}
+	}
+	e.debug[key] = value
+	return e
+}
+
+// GetID retrieves the unique identifier of the error, generating it on first use
+func (e *AppError) GetID() string {
+	if e.id == "" {
+		e.id = newErrorID()
+	}
+	return e.id
+}
+
+// newErrorID generates a random hex identifier
+func newErrorID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GetAppErr creates a new instance of AppError; a zero httpCode takes the default HTTP status of
+// customErr (see CustomErr.DefaultHTTPCode)
 func GetAppErr(ctx context.Context, err error, customErr *CustomErr, httpCode int, meta ...interface{}) *AppError {
-	// Log the error trace for debugging
-	AddTraceLog(ctx, err.Error())
+	return newAppErr(ctx, err, customErr, httpCode, 2, meta...)
+}
+
+// newAppErr creates an AppError whose stack or wrap site starts skip frames above newAppErr
+func newAppErr(ctx context.Context, err error, customErr *CustomErr, httpCode int, skip int, meta ...interface{}) *AppError {
+	appErr := buildAppErr(ctx, err, customErr, httpCode, skip+1, meta...)
+	finishAppErr(ctx, appErr)
+	return appErr
+}
+
+// buildAppErr creates an AppError like newAppErr without finishing it; constructors setting more
+// fields build the error, apply them and then call finishAppErr, so hooks, span recorders and
+// metrics observe the complete error
+func buildAppErr(ctx context.Context, err error, customErr *CustomErr, httpCode int, skip int, meta ...interface{}) *AppError {
+	// Log the error trace for debugging and convert further errors into Internal errors once the
+	// request exceeded its error limit
+	traceMeta, _ := TraceFromContext(ctx)
+	if traceError(traceMeta, err, skip) {
+		customErr, httpCode = ErrorLimitExceeded, http.StatusInternalServerError
+	}
 
 	// Initialize the AppError structure
 	appErr := &AppError{
-		ActualErr:  err,
-		CustomErr:  &CustomErr{},
-		httpCode:   httpCode,
-		ErrorCodes: []string{},
+		ActualErr: err,
+		httpCode:  httpCode,
+	}
+
+	// Capture the stack only once per chain; wrapping an AppError records just the wrap site
+	if wrapped, ok := asAppError(err); ok && (len(wrapped.stack) > 0 || len(wrapped.replayStack) > 0) {
+		appErr.stack = wrapped.stack
+		appErr.wrapSites = append(append([]uintptr{}, wrapped.wrapSites...), caller(skip))
+		appErr.replayStack, appErr.replayWrapSites = wrapped.replayStack, wrapped.replayWrapSites
+	} else {
+		appErr.stack = callers(appErr.stackBuf[:0], skip)
 	}
 
 	// Assign metadata if provided
@@ -108,12 +315,185 @@ func GetAppErr(ctx context.Context, err error, customErr *CustomErr, httpCode in
 		appErr.data = meta[0]
 	}
 
-	// Populate custom error details if provided
+	// Populate custom error details if provided, from a private copy so SetMsg and SetErrCode never
+	// change the shared definition
+	appErr.CustomErr = &appErr.customErrBuf
+	appErr.ErrorCodes = appErr.codeBuf[:0]
 	if customErr != nil {
-		appErr.CustomErr.Code = customErr.Code
-		appErr.CustomErr.Message = customErr.Message
-		appErr.ErrorCodes = append(appErr.ErrorCodes, customErr.Code)
+		appErr.customErrBuf = *customErr
+		appErr.ErrorCodes = appendCode(appErr.ErrorCodes, customErr.Code)
+		if appErr.httpCode == 0 {
+			appErr.httpCode = customErr.DefaultHTTPCode()
+		}
 	}
+	keepIdentifiers(appErr, traceMeta)
+	return appErr
+}
+
+// appendCode appends code to the error codes of a chain, skipping an empty code
+func appendCode(codes []string, code string) []string {
+	if code == "" {
+		return codes
+	}
+	return append(codes, code)
+}
 
+// Wrap converts err into an AppError like GetAppErr, but when err already is (or wraps) an
+// AppError it extends that chain instead of nesting it: the error codes are merged with the new
+// code appended, the original underlying error, data, labels and stack are kept, and the wrap site
+// is recorded. A zero httpCode keeps the existing status and data is only replaced when given
+func Wrap(ctx context.Context, err error, customErr *CustomErr, httpCode int, meta ...interface{}) *AppError {
+	return wrapAppErr(ctx, err, customErr, httpCode, 2, meta...)
+}
+
+// wrapAppErr implements Wrap, recording the wrap site skip frames above wrapAppErr
+func wrapAppErr(ctx context.Context, err error, customErr *CustomErr, httpCode int, skip int, meta ...interface{}) *AppError {
+	existing, ok := asAppError(err)
+	if !ok {
+		return newAppErr(ctx, err, customErr, httpCode, skip+1, meta...)
+	}
+
+	traceMeta, _ := TraceFromContext(ctx)
+	if traceError(traceMeta, err, skip) {
+		customErr, httpCode = ErrorLimitExceeded, http.StatusInternalServerError
+	}
+
+	appErr := existing.clone()
+	appErr.wrapSites = append(appErr.wrapSites, caller(skip))
+	if customErr != nil {
+		appErr.CustomErr = customErr.Clone()
+		appErr.ErrorCodes = appendCode(appErr.ErrorCodes, customErr.Code)
+	}
+	if httpCode != 0 {
+		appErr.httpCode = httpCode
+	}
+	if len(meta) > 0 {
+		appErr.data = meta[0]
+	}
+	keepIdentifiers(appErr, traceMeta)
+
+	finishAppErr(ctx, appErr)
 	return appErr
 }
+
+// traceError adds err to the trace of traceMeta and counts it against the request's error limit,
+// reporting whether the limit is exceeded; the entry is attributed to the frame skip levels above
+// the caller of traceError. A nil traceMeta does nothing, so the error text is never formatted for
+// untraced errors, and a nil err is counted without a trace entry
+func traceError(traceMeta *TraceMeta, err error, skip int) bool {
+	if traceMeta == nil {
+		return false
+	}
+
+	if err != nil {
+		addTraceLog(traceMeta, err.Error(), caller(skip+1))
+	}
+	count := traceMeta.countError()
+	limit := currentConfig().MaxErrorsPerRequest
+	return limit > 0 && count > limit
+}
+
+// keepIdentifiers copies the identifiers of traceMeta, which may be nil, onto the AppError so it
+// carries them into logs and serializations made without the request context
+func keepIdentifiers(appErr *AppError, traceMeta *TraceMeta) {
+	if traceMeta == nil {
+		return
+	}
+	identifiers := traceMeta.identifiers()
+	if appErr.identifiers == nil {
+		appErr.identifiers = identifiers
+		return
+	}
+	for k, v := range identifiers {
+		appErr.identifiers[k] = v
+	}
+}
+
+// finishAppErr applies the context dependent bookkeeping to a newly created AppError; it passes the
+// error to the span recorder, metrics, statistics and hooks, so it must run after every field the
+// constructor sets
+func finishAppErr(ctx context.Context, appErr *AppError) {
+	// Label the error with the tenant of multi-tenant services
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		appErr.SetLabel(c.TenantLabel, tenantID)
+	}
+
+	// Capture the distributed trace the error belongs to, so responses carry a correlation ID
+	if traceID, spanID, ok := TraceContextFromContext(ctx); ok {
+		appErr.SetTraceIDs(traceID, spanID)
+	}
+
+	// Record the error on the active tracing span
+	recordSpan(ctx, appErr)
+
+	// Emit an audit record for security-relevant errors
+	auditIfRelevant(ctx, appErr)
+
+	// Count the error for error-rate metrics
+	recordMetrics(ctx, appErr)
+
+	// Collect statistics for the admin and smoke test views
+	if currentConfig().StatsEnabled {
+		stats.record(appErr)
+	}
+
+	// Pass the error through the registered hooks
+	runHooks(ctx, appErr)
+}
+
+// clone returns a copy of the AppError that shares no mutable state with it
+func (e *AppError) clone() *AppError {
+	cp := *e
+	cp.CustomErr = e.CustomErr.Clone()
+	cp.ErrorCodes = append([]string{}, e.ErrorCodes...)
+	cp.contexts = append([]string(nil), e.contexts...)
+	cp.wrapSites = append([]uintptr(nil), e.wrapSites...)
+	if e.headers != nil {
+		cp.headers = e.headers.Clone()
+	}
+	if e.labels != nil {
+		cp.labels = make(map[string]string, len(e.labels))
+		for k, v := range e.labels {
+			cp.labels[k] = v
+		}
+	}
+	if e.debug != nil {
+		cp.debug = make(map[string]interface{}, len(e.debug))
+		for k, v := range e.debug {
+			cp.debug[k] = v
+		}
+	}
+	cp.identifiers = e.GetIdentifiers()
+	return &cp
+}
+
+// asAppError extracts the first *AppError from the chain of err
+func asAppError(err error) (*AppError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *AppError:
+			return e, e != nil
+		case interface{ As(interface{}) bool }:
+			// Only errors customizing As need a target, which escapes to the heap
+			var appErr *AppError
+			if e.As(&appErr) {
+				return appErr, appErr != nil
+			}
+		}
+
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				if appErr, ok := asAppError(inner); ok {
+					return appErr, true
+				}
+			}
+			return nil, false
+		default:
+			return nil, false
+		}
+	}
+	return nil, false
+}
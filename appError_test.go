@@ -0,0 +1,370 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapMsg(t *testing.T) {
+	tests := []struct {
+		name        string
+		cause       error
+		contexts    []string
+		wantError   string
+		wantVerbose string
+	}{
+		{"no context", errors.New("connection refused"), nil, "connection refused", ""},
+		{"one context", errors.New("connection refused"), []string{"loading order"}, "loading order: connection refused", "\ncontexts:\n\tloading order\n"},
+		{
+			name:        "stacked contexts",
+			cause:       errors.New("connection refused"),
+			contexts:    []string{"ping", "", "loading order"},
+			wantError:   "loading order: ping: connection refused",
+			wantVerbose: "\ncontexts:\n\tping\n\tloading order\n",
+		},
+		{"no cause", nil, []string{"loading order"}, "loading order", "\ncontexts:\n\tloading order\n"},
+	}
+	customErr := GetCustomErr("ERR_WRAP_1", "order unavailable", true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := GetAppErr(context.Background(), tt.cause, customErr, http.StatusServiceUnavailable)
+			for _, msg := range tt.contexts {
+				appErr.WrapMsg(msg)
+			}
+
+			if got := appErr.Error(); got != tt.wantError {
+				t.Errorf("Error() = %q, want %q", got, tt.wantError)
+			}
+			if appErr.GetErrCode() != customErr.Code || appErr.GetMsg() != customErr.Message {
+				t.Errorf("code and message changed to %s %q", appErr.GetErrCode(), appErr.GetMsg())
+			}
+			verbose := fmt.Sprintf("%+v", appErr)
+			if tt.wantVerbose == "" && strings.Contains(verbose, "contexts:") {
+				t.Errorf("%%+v report lists contexts:\n%s", verbose)
+			}
+			if !strings.Contains(verbose, tt.wantVerbose) {
+				t.Errorf("%%+v report misses %q:\n%s", tt.wantVerbose, verbose)
+			}
+			var wantContexts []string
+			for _, msg := range tt.contexts {
+				if msg != "" {
+					wantContexts = append(wantContexts, msg)
+				}
+			}
+			if got := appErr.GetContexts(); !reflect.DeepEqual(got, wantContexts) {
+				t.Errorf("GetContexts() = %q, want %q", got, wantContexts)
+			}
+		})
+	}
+}
+
+// pathError is a typed cause found through errors.As
+type pathError struct {
+	path string
+}
+
+func (e *pathError) Error() string {
+	return "open " + e.path
+}
+
+func TestUnwrap(t *testing.T) {
+	ctx := context.Background()
+	sentinel := errors.New("sentinel")
+	customErr := GetCustomErr("ERR_UNW_1", "failed", false)
+
+	tests := []struct {
+		name      string
+		err       error
+		wantCause error
+		wantPath  string
+	}{
+		{"direct cause", GetAppErr(ctx, sentinel, customErr, 500), sentinel, ""},
+		{"wrapped cause", GetAppErr(ctx, fmt.Errorf("loading: %w", sentinel), customErr, 500), sentinel, ""},
+		{"typed cause", GetAppErr(ctx, &pathError{path: "/etc/app"}, customErr, 500), nil, "/etc/app"},
+		{"app error wrapped by fmt", fmt.Errorf("handler: %w", GetAppErr(ctx, sentinel, customErr, 500)), sentinel, ""},
+		{"nil cause", GetAppErr(ctx, nil, customErr, 500), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantCause != nil && !errors.Is(tt.err, tt.wantCause) {
+				t.Errorf("errors.Is(%v, %v) = false", tt.err, tt.wantCause)
+			}
+			var pathErr *pathError
+			if found := errors.As(tt.err, &pathErr); found != (tt.wantPath != "") || (found && pathErr.path != tt.wantPath) {
+				t.Errorf("errors.As found %v, want path %q", found, tt.wantPath)
+			}
+		})
+	}
+
+	appErr := GetAppErr(ctx, sentinel, customErr, 500)
+	if errors.Unwrap(appErr) != sentinel {
+		t.Errorf("errors.Unwrap = %v, want %v", errors.Unwrap(appErr), sentinel)
+	}
+}
+
+func TestIsByCode(t *testing.T) {
+	ctx := context.Background()
+	notFound := GetCustomErr("ERR_IS_1", "not found", false)
+	conflict := GetCustomErr("ERR_IS_2", "conflict", false)
+	appErr := GetAppErr(ctx, errors.New("no rows"), notFound, 404)
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"same custom error", appErr, notFound, true},
+		{"copy of custom error", appErr, GetCustomErr("ERR_IS_1", "other message", true), true},
+		{"other custom error", appErr, conflict, false},
+		{"app error with same code", appErr, GetAppErr(ctx, errors.New("gone"), notFound, 410), true},
+		{"app error with other code", appErr, GetAppErr(ctx, errors.New("dup"), conflict, 409), false},
+		{"wrapped by fmt", fmt.Errorf("handler: %w", appErr), notFound, true},
+		{"latest code of a chain", Wrap(ctx, appErr, conflict, 409), conflict, true},
+		{"nil custom error target", appErr, (*CustomErr)(nil), false},
+		{"without code", GetAppErr(ctx, errors.New("x"), GetCustomErr("", "no code", false), 500), GetCustomErr("", "no code", false), false},
+		{"plain error target", appErr, errors.New("not found"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsCustomErr(t *testing.T) {
+	ctx := context.Background()
+	notFound := GetCustomErr("ERR_AS_1", "not found", false)
+	conflict := GetCustomErr("ERR_AS_2", "conflict", false)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{"app error", GetAppErr(ctx, errors.New("no rows"), notFound, 404), "ERR_AS_1"},
+		{"wrapped by fmt", fmt.Errorf("handler: %w", GetAppErr(ctx, errors.New("no rows"), notFound, 404)), "ERR_AS_1"},
+		{"outermost app error", GetAppErr(ctx, GetAppErr(ctx, errors.New("no rows"), notFound, 404), conflict, 409), "ERR_AS_2"},
+		{"custom error as cause", fmt.Errorf("lookup: %w", conflict), "ERR_AS_2"},
+		{"app error without custom error", &AppError{ActualErr: errors.New("bare")}, ""},
+		{"plain error", errors.New("boom"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ce *CustomErr
+			found := errors.As(tt.err, &ce)
+			if found != (tt.wantCode != "") || (found && ce.Code != tt.wantCode) {
+				t.Errorf("errors.As = %v (%v), want code %q", found, ce, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWrapChain(t *testing.T) {
+	ctx := context.Background()
+	root := errors.New("no rows")
+	notFound := GetCustomErr("ERR_WRP_1", "not found", false)
+	upstream := GetCustomErr("ERR_WRP_2", "upstream failed", true)
+	original := func() *AppError {
+		return GetAppErr(ctx, root, notFound, 404, map[string]interface{}{"id": 1})
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		customErr *CustomErr
+		httpCode  int
+		data      []interface{}
+		wantCodes []string
+		wantHTTP  int
+		wantData  interface{}
+		wantSites int
+	}{
+		{"plain error", root, upstream, 502, nil, []string{"ERR_WRP_2"}, 502, nil, 0},
+		{"extends chain", original(), upstream, 502, nil, []string{"ERR_WRP_1", "ERR_WRP_2"}, 502, map[string]interface{}{"id": 1}, 1},
+		{"keeps status", original(), upstream, 0, nil, []string{"ERR_WRP_1", "ERR_WRP_2"}, 404, map[string]interface{}{"id": 1}, 1},
+		{"replaces data", original(), upstream, 0, []interface{}{"new"}, []string{"ERR_WRP_1", "ERR_WRP_2"}, 404, "new", 1},
+		{"keeps custom error", original(), nil, 500, nil, []string{"ERR_WRP_1"}, 500, map[string]interface{}{"id": 1}, 1},
+		{"through fmt wrapping", fmt.Errorf("calling: %w", original()), upstream, 502, nil, []string{"ERR_WRP_1", "ERR_WRP_2"}, 502, map[string]interface{}{"id": 1}, 1},
+		{"twice", Wrap(ctx, original(), upstream, 502), GetCustomErr("ERR_WRP_3", "gateway", true), 503, nil,
+			[]string{"ERR_WRP_1", "ERR_WRP_2", "ERR_WRP_3"}, 503, map[string]interface{}{"id": 1}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := fmt.Sprint(tt.err)
+			got := Wrap(ctx, tt.err, tt.customErr, tt.httpCode, tt.data...)
+
+			if !reflect.DeepEqual(got.GetErrCodes(), tt.wantCodes) || got.GetHTTPCode() != tt.wantHTTP || !reflect.DeepEqual(got.GetData(), tt.wantData) {
+				t.Errorf("Wrap = codes %v, status %d, data %v, want %v, %d, %v",
+					got.GetErrCodes(), got.GetHTTPCode(), got.GetData(), tt.wantCodes, tt.wantHTTP, tt.wantData)
+			}
+			if got.GetErr() != root && !errors.Is(got.GetErr(), root) {
+				t.Errorf("Wrap lost the original cause, got %v", got.GetErr())
+			}
+			if n := len(got.GetWrapSites()); n != tt.wantSites {
+				t.Errorf("wrap sites = %d, want %d", n, tt.wantSites)
+			}
+			if fmt.Sprint(tt.err) != before {
+				t.Errorf("Wrap modified the wrapped error: %v, was %v", tt.err, before)
+			}
+		})
+	}
+}
+
+func TestWrapAppendsTrace(t *testing.T) {
+	ctx := ContextWithTrace(context.Background())
+	appErr := GetAppErr(ctx, errors.New("no rows"), GetCustomErr("ERR_WRP_10", "not found", false), 404)
+	Wrap(ctx, appErr, GetCustomErr("ERR_WRP_11", "upstream failed", true), 502)
+
+	traceMeta, _ := TraceFromContext(ctx)
+	if got, want := traceMeta.ErrorLines(), []string{"no rows (x2)"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("trace = %q, want %q", got, want)
+	}
+	if ErrorCount(ctx) != 2 {
+		t.Errorf("ErrorCount = %d, want 2", ErrorCount(ctx))
+	}
+}
+
+func TestCopyOnWrite(t *testing.T) {
+	shared := GetCustomErr("ERR_COW_1", "order not found", true, WithSeverity(SeverityWarn), WithCategory(CategoryNotFound))
+	want := *shared
+
+	tests := []struct {
+		name   string
+		mutate func(appErr *AppError)
+		check  func(appErr *AppError) bool
+	}{
+		{"SetMsg", func(e *AppError) { e.SetMsg("gone") }, func(e *AppError) bool { return e.GetMsg() == "gone" }},
+		{"SetErrCode", func(e *AppError) { e.SetErrCode("ERR_COW_2") }, func(e *AppError) bool { return e.GetErrCode() == "ERR_COW_2" }},
+		{"SetSeverity", func(e *AppError) { e.SetSeverity(SeverityCritical) }, func(e *AppError) bool { return e.GetSeverity() == SeverityCritical }},
+		{"SetCategory", func(e *AppError) { e.SetCategory(CategoryConflict) }, func(e *AppError) bool { return e.GetCategory() == CategoryConflict }},
+		{"field", func(e *AppError) { e.CustomErr.Retryable = false }, func(e *AppError) bool { return !e.IsRetryable() }},
+		{"clone", func(e *AppError) { e.clone().SetMsg("cloned") }, func(e *AppError) bool { return e.GetMsg() == want.Message }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := GetAppErr(context.Background(), errors.New("no rows"), shared, 404)
+			sibling := GetAppErr(context.Background(), errors.New("no rows"), shared, 404)
+			tt.mutate(appErr)
+
+			if !tt.check(appErr) {
+				t.Errorf("%s did not change the AppError: %+v", tt.name, *appErr.CustomErr)
+			}
+			if *shared != want {
+				t.Errorf("%s changed the shared definition to %+v", tt.name, *shared)
+			}
+			if *sibling.CustomErr != want {
+				t.Errorf("%s changed another AppError to %+v", tt.name, *sibling.CustomErr)
+			}
+		})
+	}
+}
+
+func TestCustomErrClone(t *testing.T) {
+	tests := []struct {
+		name      string
+		customErr *CustomErr
+	}{
+		{"nil", nil},
+		{"all fields", &CustomErr{Code: "ERR_COW_3", Message: "busy", Retryable: true, Severity: SeverityCritical,
+			Category: CategoryUnavailable, HTTPCode: 503}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := tt.customErr.Clone()
+			if tt.customErr == nil {
+				if cp != nil {
+					t.Errorf("Clone of nil = %+v, want nil", cp)
+				}
+				return
+			}
+			if cp == tt.customErr || *cp != *tt.customErr {
+				t.Errorf("Clone = %p %+v, want a distinct copy of %p %+v", cp, *cp, tt.customErr, *tt.customErr)
+			}
+		})
+	}
+}
+
+func TestCustomErrFieldsCarried(t *testing.T) {
+	ctx := context.Background()
+	def := GetCustomErr("ERR_CAR_1", "try again", true, WithSeverity(SeverityCritical),
+		WithCategory(CategoryUnavailable), WithDefaultHTTPCode(http.StatusServiceUnavailable))
+
+	// The wire format carries the resolved status rather than the definition's default
+	wired := *def
+	wired.HTTPCode = 0
+
+	tests := []struct {
+		name string
+		err  *AppError
+		want CustomErr
+	}{
+		{"GetAppErr", GetAppErr(ctx, errors.New("busy"), def, 0), *def},
+		{"New", New(ctx, errors.New("busy"), WithCustomErr(def)), *def},
+		{"Wrap", Wrap(ctx, errors.New("busy"), def, 0), *def},
+		{"wire round trip", GetAppErr(ctx, errors.New("busy"), def, 0).ToWire().AppError(), wired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.CustomErr; got == nil || got == def || *got != tt.want {
+				t.Fatalf("CustomErr = %+v, want a copy of %+v", got, tt.want)
+			}
+			if !tt.err.IsRetryable() || tt.err.GetHTTPCode() != http.StatusServiceUnavailable {
+				t.Errorf("IsRetryable = %v, status %d, want true, 503", tt.err.IsRetryable(), tt.err.GetHTTPCode())
+			}
+		})
+	}
+}
+
+func TestInternalMsgNeverSerialized(t *testing.T) {
+	const internal = "cache shard 7 unreachable"
+	appErr := GetAppErr(context.Background(), errors.New("cache miss"), GetCustomErr("ERR_INT_1", "temporarily unavailable", true), 503).
+		SetInternalMsg(internal)
+
+	var logged bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&logged, nil)))
+	defer SetLogger(nil)
+	logError(context.Background(), "request failed", appErr)
+
+	tests := []struct {
+		name         string
+		output       func() string
+		wantInternal bool
+	}{
+		{"log", logged.String, true},
+		{"verbose format", func() string { return fmt.Sprintf("%+v", appErr) }, true},
+		{"error string", appErr.Error, false},
+		{"JSON", func() string { b, _ := json.Marshal(appErr); return string(b) }, false},
+		{"HTTP response", func() string {
+			rec := httptest.NewRecorder()
+			appErr.WriteHTTP(rec, nil)
+			return rec.Body.String()
+		}, false},
+		{"problem details", func() string { b, _ := json.Marshal(appErr.ToProblemDetails()); return string(b) }, false},
+		{"wire", func() string { b, _ := json.Marshal(appErr.ToWire()); return string(b) }, false},
+		{"SSE", func() string { return string(appErr.FormatSSE()) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := tt.output()
+			if got := strings.Contains(out, internal); got != tt.wantInternal {
+				t.Errorf("output contains the internal message = %v, want %v: %s", got, tt.wantInternal, out)
+			}
+		})
+	}
+
+	if appErr.GetInternalMsg() != internal || appErr.GetMsg() != "temporarily unavailable" {
+		t.Errorf("GetInternalMsg = %q, GetMsg = %q", appErr.GetInternalMsg(), appErr.GetMsg())
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// auditActorKeys are the trace identifiers checked, in order, to find who triggered an error
+var auditActorKeys = []string{"actor", "user_id", "subject", "client_id"}
+
+// AuditRecord is the structured audit event emitted for a security-relevant error
+type AuditRecord struct {
+	Time        time.Time              `json:"time"`
+	Actor       string                 `json:"actor,omitempty"` // Who: first of actor, user_id, subject, client_id
+	Action      string                 `json:"action"`          // What: the client-facing message
+	Code        string                 `json:"code"`
+	HTTPCode    int                    `json:"http_code"`
+	ErrorID     string                 `json:"error_id"`
+	Tenant      string                 `json:"tenant,omitempty"`
+	Err         string                 `json:"err,omitempty"`
+	Identifiers map[string]interface{} `json:"identifiers,omitempty"`
+}
+
+// AuditSink receives audit records, separate from normal logging
+type AuditSink interface {
+	Audit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc adapts a function to the AuditSink interface
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+// Audit implements AuditSink
+func (f AuditSinkFunc) Audit(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// audit holds the installed sink and the codes tagged as security-relevant
+var audit = struct {
+	sync.RWMutex
+	sink  AuditSink
+	codes map[string]struct{}
+}{codes: map[string]struct{}{}}
+
+// SetAuditSink installs the sink fired for security-relevant errors; nil disables auditing
+func SetAuditSink(sink AuditSink) {
+	audit.Lock()
+	audit.sink = sink
+	audit.Unlock()
+}
+
+// MarkSecurityRelevant tags codes (token failures, authz denials) whose errors emit audit records
+func MarkSecurityRelevant(codes ...string) {
+	audit.Lock()
+	defer audit.Unlock()
+	for _, code := range codes {
+		audit.codes[code] = struct{}{}
+	}
+}
+
+// IsSecurityRelevant reports whether the error is tagged security-relevant: its primary code was
+// marked with MarkSecurityRelevant or its HTTP code is 401 or 403
+func IsSecurityRelevant(appErr *AppError) bool {
+	if appErr == nil {
+		return false
+	}
+	if appErr.httpCode == http.StatusUnauthorized || appErr.httpCode == http.StatusForbidden {
+		return true
+	}
+
+	audit.RLock()
+	defer audit.RUnlock()
+	_, ok := audit.codes[primaryCode(appErr)]
+	return ok
+}
+
+// Audit emits an audit record for err through the installed sink, whether or not it is tagged
+// security-relevant; it is a no-op when err is not an AppError or no sink is installed
+func Audit(ctx context.Context, err error) error {
+	appErr, ok := asAppError(err)
+	if !ok {
+		return nil
+	}
+
+	audit.RLock()
+	sink := audit.sink
+	audit.RUnlock()
+	if sink == nil {
+		return nil
+	}
+	return sink.Audit(ctx, newAuditRecord(ctx, appErr))
+}
+
+// auditIfRelevant emits an audit record when the error is security-relevant, ignoring sink failures
+func auditIfRelevant(ctx context.Context, appErr *AppError) {
+	audit.RLock()
+	installed := audit.sink != nil
+	audit.RUnlock()
+
+	if installed && IsSecurityRelevant(appErr) {
+		_ = Audit(ctx, appErr)
+	}
+}
+
+// newAuditRecord builds the audit record of an AppError, taking identifiers from the trace in ctx
+func newAuditRecord(ctx context.Context, appErr *AppError) AuditRecord {
+	record := AuditRecord{
+		Time:     time.Now().UTC(),
+		Action:   scrub(appErr.GetMsg()),
+		Code:     primaryCode(appErr),
+		HTTPCode: appErr.httpCode,
+		ErrorID:  appErr.GetID(),
+		Tenant:   appErr.GetTenant(),
+		Err:      scrub(appErr.Error()),
+	}
+
+	traceMeta, ok := traceSnapshot(ctx)
+	if !ok || len(traceMeta.IdentifierMappings) == 0 {
+		return record
+	}
+	record.Identifiers = make(map[string]interface{}, len(traceMeta.IdentifierMappings))
+	for k, v := range traceMeta.IdentifierMappings {
+		record.Identifiers[k] = v
+	}
+	for _, key := range auditActorKeys {
+		if actor, ok := traceMeta.IdentifierMappings[key]; ok {
+			record.Actor = fmt.Sprint(actor)
+			break
+		}
+	}
+	return record
+}
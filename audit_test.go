@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAuditSecurityRelevant(t *testing.T) {
+	var records []AuditRecord
+	SetAuditSink(AuditSinkFunc(func(_ context.Context, record AuditRecord) error {
+		records = append(records, record)
+		return nil
+	}))
+	defer SetAuditSink(nil)
+	MarkSecurityRelevant("ERR_AUD_3")
+
+	tests := []struct {
+		name      string
+		code      string
+		httpCode  int
+		actorKey  string
+		wantAudit bool
+		wantActor string
+	}{
+		{"unauthorized", "ERR_AUD_1", 401, "user_id", true, "42"},
+		{"forbidden", "ERR_AUD_2", 403, "client_id", true, "42"},
+		{"marked code", "ERR_AUD_3", 500, "request_id", true, ""},
+		{"not security relevant", "ERR_AUD_4", 500, "user_id", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records = nil
+			ctx := ContextWithTrace(context.Background())
+			AddIdentifier(ctx, tt.actorKey, 42)
+
+			appErr := GetAppErr(ctx, errors.New("token expired"), GetCustomErr(tt.code, "access denied", false), tt.httpCode)
+			if IsSecurityRelevant(appErr) != tt.wantAudit {
+				t.Errorf("IsSecurityRelevant = %v, want %v", !tt.wantAudit, tt.wantAudit)
+			}
+			if audited := len(records) == 1; audited != tt.wantAudit || len(records) > 1 {
+				t.Fatalf("got %d audit records, want audited %v", len(records), tt.wantAudit)
+			}
+			if !tt.wantAudit {
+				return
+			}
+			record := records[0]
+			if record.Code != tt.code || record.HTTPCode != tt.httpCode || record.Action != "access denied" ||
+				record.ErrorID != appErr.GetID() || record.Actor != tt.wantActor || record.Identifiers[tt.actorKey] != 42 {
+				t.Errorf("record = %+v", record)
+			}
+		})
+	}
+}
+
+func TestAudit(t *testing.T) {
+	sinkErr := errors.New("sink down")
+	appErr := GetAppErr(context.Background(), errors.New("boom"), GetCustomErr("ERR_AUD_10", "failed", false), 500)
+
+	tests := []struct {
+		name string
+		sink AuditSink
+		err  error
+		want error
+	}{
+		{"no sink", nil, appErr, nil},
+		{"plain error", AuditSinkFunc(func(context.Context, AuditRecord) error { return sinkErr }), errors.New("boom"), nil},
+		{"sink failure", AuditSinkFunc(func(context.Context, AuditRecord) error { return sinkErr }), appErr, sinkErr},
+		{"sink success", AuditSinkFunc(func(context.Context, AuditRecord) error { return nil }), appErr, nil},
+	}
+	defer SetAuditSink(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAuditSink(tt.sink)
+			if err := Audit(context.Background(), tt.err); !errors.Is(err, tt.want) {
+				t.Errorf("Audit = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package awsae
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Custom errors AWS API failures map to
+var (
+	ErrThrottled = ae.GetCustomErr("ERR_AWS_THROTTLED", "aws request was throttled", true,
+		ae.WithCategory(ae.CategoryRateLimited))
+	ErrUnauthorized = ae.GetCustomErr("ERR_AWS_UNAUTHORIZED", "aws credentials are invalid", false,
+		ae.WithCategory(ae.CategoryAuth))
+	ErrAccessDenied = ae.GetCustomErr("ERR_AWS_ACCESS_DENIED", "aws access denied", false,
+		ae.WithCategory(ae.CategoryForbidden))
+	ErrNotFound = ae.GetCustomErr("ERR_AWS_NOT_FOUND", "aws resource not found", false,
+		ae.WithCategory(ae.CategoryNotFound))
+	ErrConflict = ae.GetCustomErr("ERR_AWS_CONFLICT", "aws resource is in a conflicting state", false,
+		ae.WithCategory(ae.CategoryConflict))
+	ErrInvalidRequest = ae.GetCustomErr("ERR_AWS_INVALID_REQUEST", "aws rejected the request", false,
+		ae.WithCategory(ae.CategoryValidation))
+	ErrTimeout = ae.GetCustomErr("ERR_AWS_TIMEOUT", "aws request timed out", true,
+		ae.WithCategory(ae.CategoryTimeout))
+	ErrUnavailable = ae.GetCustomErr("ERR_AWS_UNAVAILABLE", "aws service is unavailable", true,
+		ae.WithCategory(ae.CategoryUnavailable))
+	ErrUnknown = ae.GetCustomErr("ERR_AWS_UNKNOWN", "aws request failed", false,
+		ae.WithCategory(ae.CategoryUpstream))
+)
+
+// errorCodes maps AWS API error codes, shared across services, to custom errors
+var errorCodes = map[string]*ae.CustomErr{
+	"Throttling":                             ErrThrottled,
+	"ThrottlingException":                    ErrThrottled,
+	"ThrottledException":                     ErrThrottled,
+	"RequestThrottled":                       ErrThrottled,
+	"RequestThrottledException":              ErrThrottled,
+	"TooManyRequestsException":               ErrThrottled,
+	"ProvisionedThroughputExceededException": ErrThrottled,
+	"RequestLimitExceeded":                   ErrThrottled,
+	"BandwidthLimitExceeded":                 ErrThrottled,
+	"SlowDown":                               ErrThrottled,
+	"PriorRequestNotComplete":                ErrThrottled,
+	"EC2ThrottledException":                  ErrThrottled,
+
+	"UnrecognizedClientException": ErrUnauthorized,
+	"InvalidClientTokenId":        ErrUnauthorized,
+	"InvalidAccessKeyId":          ErrUnauthorized,
+	"ExpiredToken":                ErrUnauthorized,
+	"ExpiredTokenException":       ErrUnauthorized,
+	"InvalidSignatureException":   ErrUnauthorized,
+	"SignatureDoesNotMatch":       ErrUnauthorized,
+	"MissingAuthenticationToken":  ErrUnauthorized,
+	"AuthFailure":                 ErrUnauthorized,
+
+	"AccessDenied":                ErrAccessDenied,
+	"AccessDeniedException":       ErrAccessDenied,
+	"UnauthorizedOperation":       ErrAccessDenied,
+	"AuthorizationError":          ErrAccessDenied,
+	"AuthorizationErrorException": ErrAccessDenied,
+	"Forbidden":                   ErrAccessDenied,
+	"NotAuthorized":               ErrAccessDenied,
+
+	"ResourceNotFound":          ErrNotFound,
+	"ResourceNotFoundException": ErrNotFound,
+	"NotFound":                  ErrNotFound,
+	"NotFoundException":         ErrNotFound,
+	"NoSuchKey":                 ErrNotFound,
+	"NoSuchBucket":              ErrNotFound,
+	"NoSuchEntity":              ErrNotFound,
+	"NoSuchUpload":              ErrNotFound,
+	"ParameterNotFound":         ErrNotFound,
+	"QueueDoesNotExist":         ErrNotFound,
+
+	"ConditionalCheckFailedException": ErrConflict,
+	"TransactionConflictException":    ErrConflict,
+	"ConflictException":               ErrConflict,
+	"ResourceConflictException":       ErrConflict,
+	"ResourceInUseException":          ErrConflict,
+	"AlreadyExistsException":          ErrConflict,
+	"ResourceAlreadyExistsException":  ErrConflict,
+	"EntityAlreadyExists":             ErrConflict,
+	"BucketAlreadyExists":             ErrConflict,
+	"BucketAlreadyOwnedByYou":         ErrConflict,
+
+	"ValidationException":            ErrInvalidRequest,
+	"ValidationError":                ErrInvalidRequest,
+	"InvalidParameterException":      ErrInvalidRequest,
+	"InvalidParameterValue":          ErrInvalidRequest,
+	"InvalidParameterValueException": ErrInvalidRequest,
+	"InvalidRequestException":        ErrInvalidRequest,
+	"InvalidArgument":                ErrInvalidRequest,
+	"MissingParameter":               ErrInvalidRequest,
+	"MalformedQueryString":           ErrInvalidRequest,
+	"SerializationException":         ErrInvalidRequest,
+
+	"RequestTimeout":              ErrTimeout,
+	"RequestTimeoutException":     ErrTimeout,
+	"ServiceUnavailable":          ErrUnavailable,
+	"ServiceUnavailableException": ErrUnavailable,
+	"InternalError":               ErrUnavailable,
+	"InternalFailure":             ErrUnavailable,
+	"InternalServerError":         ErrUnavailable,
+	"InternalServiceError":        ErrUnavailable,
+	"InternalServerException":     ErrUnavailable,
+}
+
+// statusErrs maps the HTTP status of unknown API error codes to custom errors
+var statusErrs = map[int]*ae.CustomErr{
+	http.StatusBadRequest:         ErrInvalidRequest,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrAccessDenied,
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusRequestTimeout:     ErrTimeout,
+	http.StatusConflict:           ErrConflict,
+	http.StatusPreconditionFailed: ErrConflict,
+	http.StatusTooManyRequests:    ErrThrottled,
+	http.StatusGatewayTimeout:     ErrTimeout,
+}
+
+// FromAWSError converts an error returned by an AWS SDK (smithy) client into an AppError: throttling
+// becomes a retryable 429, access denied a 403, missing resources a 404, request timeouts a retryable
+// 504 and server faults a retryable 503, see Classify. The service and operation are recorded in the
+// data along with the AWS error code and request ID. nil stays nil and AppErrors are returned unchanged
+func FromAWSError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	opts := []ae.Option{ae.WithCustomErr(Classify(err)), ae.WithStackSkip(1)}
+	if data := Data(err); len(data) > 0 {
+		opts = append(opts, ae.WithData(data))
+	}
+	return ae.New(ctx, err, opts...)
+}
+
+// Classify returns the custom error an AWS error maps to, by API error code first and by HTTP
+// status otherwise
+func Classify(err error) *ae.CustomErr {
+	var canceled interface{ CanceledError() bool }
+	if errors.Is(err, context.Canceled) || (errors.As(err, &canceled) && canceled.CanceledError()) {
+		return ae.ClientClosedRequest
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if customErr, ok := errorCodes[apiErr.ErrorCode()]; ok {
+			return customErr
+		}
+	}
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		if customErr, ok := statusErrs[respErr.HTTPStatusCode()]; ok {
+			return customErr
+		}
+		if respErr.HTTPStatusCode() >= http.StatusInternalServerError {
+			return ErrUnavailable
+		}
+	}
+	if apiErr != nil && apiErr.ErrorFault() == smithy.FaultServer {
+		return ErrUnavailable
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrTimeout
+	case netErr != nil:
+		return ErrUnavailable
+	}
+	return ErrUnknown
+}
+
+// Data returns the details of an AWS error recorded in the AppError data: "service", "operation",
+// "aws_code" and "request_id", each only when known
+func Data(err error) map[string]interface{} {
+	data := map[string]interface{}{}
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		data["service"] = opErr.Service()
+		data["operation"] = opErr.Operation()
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() != "" {
+		data["aws_code"] = apiErr.ErrorCode()
+	}
+	var reqErr interface{ ServiceRequestID() string }
+	if errors.As(err, &reqErr) && reqErr.ServiceRequestID() != "" {
+		data["request_id"] = reqErr.ServiceRequestID()
+	}
+	return data
+}
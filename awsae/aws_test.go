@@ -0,0 +1,117 @@
+package awsae
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// opErr wraps err like an SDK client call of DynamoDB GetItem
+func opErr(err error) error {
+	return &smithy.OperationError{ServiceID: "DynamoDB", OperationName: "GetItem", Err: err}
+}
+
+// apiErr returns an API error with the given code and fault
+func apiErr(code string, fault smithy.ErrorFault) error {
+	return &smithy.GenericAPIError{Code: code, Message: code + " message", Fault: fault}
+}
+
+// respErr returns a response error with the given HTTP status wrapping err
+func respErr(status int, err error) error {
+	return &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}}, Err: err}
+}
+
+// requestIDErr carries the request ID of the failed call, like the SDK's response errors
+type requestIDErr struct{ error }
+
+func (requestIDErr) ServiceRequestID() string { return "req-123" }
+func (e requestIDErr) Unwrap() error          { return e.error }
+
+// canceledErr is an SDK error reporting a canceled request
+type canceledErr struct{}
+
+func (canceledErr) Error() string       { return "request canceled" }
+func (canceledErr) CanceledError() bool { return true }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *ae.CustomErr
+	}{
+		{"throttling", opErr(apiErr("ThrottlingException", smithy.FaultClient)), ErrThrottled},
+		{"expired token", apiErr("ExpiredToken", smithy.FaultClient), ErrUnauthorized},
+		{"access denied", opErr(apiErr("AccessDeniedException", smithy.FaultClient)), ErrAccessDenied},
+		{"not found", opErr(apiErr("ResourceNotFoundException", smithy.FaultClient)), ErrNotFound},
+		{"conditional check", apiErr("ConditionalCheckFailedException", smithy.FaultClient), ErrConflict},
+		{"validation", apiErr("ValidationException", smithy.FaultClient), ErrInvalidRequest},
+		{"request timeout", apiErr("RequestTimeout", smithy.FaultClient), ErrTimeout},
+		{"internal error", apiErr("InternalError", smithy.FaultServer), ErrUnavailable},
+		{"unknown code by status", respErr(http.StatusTooManyRequests, apiErr("SomethingNew", smithy.FaultUnknown)), ErrThrottled},
+		{"unknown code with 5xx", respErr(http.StatusBadGateway, apiErr("SomethingNew", smithy.FaultUnknown)), ErrUnavailable},
+		{"unknown server fault", apiErr("SomethingNew", smithy.FaultServer), ErrUnavailable},
+		{"unknown client fault", apiErr("SomethingNew", smithy.FaultClient), ErrUnknown},
+		{"context canceled", opErr(context.Canceled), ae.ClientClosedRequest},
+		{"SDK canceled", opErr(canceledErr{}), ae.ClientClosedRequest},
+		{"deadline", opErr(context.DeadlineExceeded), ErrTimeout},
+		{"network error", opErr(&net.OpError{Op: "dial", Err: errors.New("connection refused")}), ErrUnavailable},
+		{"other error", errors.New("boom"), ErrUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromAWSError(t *testing.T) {
+	existing := ae.GetAppErr(context.Background(), errors.New("gone"), ErrNotFound, 0)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+		wantData      interface{}
+	}{
+		{"throttling", opErr(requestIDErr{apiErr("ThrottlingException", smithy.FaultClient)}), "ERR_AWS_THROTTLED", http.StatusTooManyRequests, true,
+			map[string]interface{}{"service": "DynamoDB", "operation": "GetItem", "aws_code": "ThrottlingException", "request_id": "req-123"}},
+		{"access denied", apiErr("AccessDenied", smithy.FaultClient), "ERR_AWS_ACCESS_DENIED", http.StatusForbidden, false,
+			map[string]interface{}{"aws_code": "AccessDenied"}},
+		{"request timeout", opErr(apiErr("RequestTimeoutException", smithy.FaultClient)), "ERR_AWS_TIMEOUT", http.StatusGatewayTimeout, true,
+			map[string]interface{}{"service": "DynamoDB", "operation": "GetItem", "aws_code": "RequestTimeoutException"}},
+		{"no details", errors.New("boom"), "ERR_AWS_UNKNOWN", http.StatusBadGateway, false, nil},
+		{"AppError", existing, "ERR_AWS_NOT_FOUND", http.StatusNotFound, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromAWSError(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("FromAWSError = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromAWSError = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+			if !reflect.DeepEqual(appErr.GetData(), tt.wantData) {
+				t.Errorf("GetData = %#v, want %#v", appErr.GetData(), tt.wantData)
+			}
+		})
+	}
+
+	if err := FromAWSError(context.Background(), nil); err != nil {
+		t.Errorf("FromAWSError(nil) = %v, want nil", err)
+	}
+}
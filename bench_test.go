@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var errBench = errors.New("record not found")
+
+func BenchmarkGetAppErrUntraced(b *testing.B) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = GetAppErr(ctx, errBench, InternalError, 0)
+	}
+}
+
+func BenchmarkGetAppErrTraced(b *testing.B) {
+	ctx := ContextWithTrace(context.Background())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = GetAppErr(ctx, errBench, InternalError, 0)
+	}
+}
+
+func TestGetAppErrUntracedAllocs(t *testing.T) {
+	tests := []struct {
+		name      string
+		customErr *CustomErr
+	}{
+		{"custom error", InternalError},
+		{"no custom error", nil},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, func() {
+				_ = GetAppErr(ctx, errBench, tt.customErr, 0)
+			})
+			// Only the AppError itself is allocated: no trace work, stack captured inline
+			if allocs != 1 {
+				t.Errorf("GetAppErr without TraceMeta made %v allocations, want 1", allocs)
+			}
+		})
+	}
+}
+
+func TestGetAppErrUntracedKeepsStack(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errBench, InternalError, 0)
+	frames := appErr.GetStackTrace()
+	if len(frames) == 0 || frames[0].Function != "github.com/piyushkumar96/app-error.TestGetAppErrUntracedKeepsStack" {
+		t.Fatalf("stack starts at %v, want the caller of GetAppErr", frames)
+	}
+	if codes := appErr.AddErrCode("ERR_NEXT").GetErrCodes(); len(codes) != 2 || codes[0] != InternalError.Code {
+		t.Errorf("codes after AddErrCode = %v", codes)
+	}
+	if InternalError.Code == "ERR_NEXT" {
+		t.Error("AddErrCode changed the shared custom error")
+	}
+}
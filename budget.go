@@ -0,0 +1,182 @@
+package errors
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sloBuckets is the number of buckets a tracking window is divided into
+const sloBuckets = 60
+
+// ErrSLOPublished is returned when publishing a tracker under an expvar name already in use
+var ErrSLOPublished = errors.New("slo already published")
+
+// publishMu serializes the check and publication of expvar names
+var publishMu sync.Mutex
+
+// BudgetClassifier decides whether an error burns the error budget
+type BudgetClassifier func(err error) bool
+
+// DefaultBudgetClassifier treats server side failures as budget burning. AppErrors with a severity
+// below SeverityError never burn budget; otherwise the category decides (internal, upstream,
+// unavailable and timeout burn, client categories do not), falling back to a 5xx (or unset) HTTP
+// code for AppErrors without a category. Any error that is not an AppError burns budget
+func DefaultBudgetClassifier(err error) bool {
+	if err == nil {
+		return false
+	}
+	appErr, ok := asAppError(err)
+	if !ok {
+		return true
+	}
+	if appErr.GetSeverity() < SeverityError {
+		return false
+	}
+	switch appErr.GetCategory() {
+	case CategoryInternal, CategoryUpstream, CategoryUnavailable, CategoryTimeout:
+		return true
+	case "":
+		return appErr.httpCode == 0 || appErr.httpCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// SLO describes a service level objective tracked over a rolling window
+type SLO struct {
+	Name   string        // Name used for metrics publication
+	Target float64       // Fraction of good events to achieve, e.g. 0.999
+	Window time.Duration // Rolling window the rates are computed over
+}
+
+// SLOStatus is a point-in-time view of a tracked SLO
+type SLOStatus struct {
+	Name      string  `json:"name"`
+	Target    float64 `json:"target"`
+	Total     uint64  `json:"total"`
+	Burning   uint64  `json:"burning"`
+	ErrorRate float64 `json:"error_rate"`
+	BurnRate  float64 `json:"burn_rate"`
+}
+
+// SLOOption configures an SLOTracker
+type SLOOption func(*SLOTracker)
+
+// WithBudgetClassifier replaces the classifier deciding which errors burn budget
+func WithBudgetClassifier(classify BudgetClassifier) SLOOption {
+	return func(t *SLOTracker) {
+		t.classify = classify
+	}
+}
+
+// sloBucket counts the events of one slice of the window
+type sloBucket struct {
+	start   time.Time
+	total   uint64
+	burning uint64
+}
+
+// SLOTracker tracks rolling error rates against an SLO
+type SLOTracker struct {
+	slo      SLO
+	classify BudgetClassifier
+	width    time.Duration
+
+	mu      sync.Mutex
+	buckets [sloBuckets]sloBucket
+}
+
+// NewSLOTracker creates a tracker for the given SLO; a zero window defaults to one hour
+func NewSLOTracker(slo SLO, opts ...SLOOption) *SLOTracker {
+	if slo.Window <= 0 {
+		slo.Window = time.Hour
+	}
+	t := &SLOTracker{
+		slo:      slo,
+		classify: DefaultBudgetClassifier,
+		width:    slo.Window / sloBuckets,
+	}
+	if t.width <= 0 {
+		t.width = time.Nanosecond
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Record counts one event; a nil err is a good event
+func (t *SLOTracker) Record(err error) {
+	burning := err != nil && t.classify(err)
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	start := now.Truncate(t.width)
+	bucket := &t.buckets[(start.UnixNano()/int64(t.width))%sloBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = sloBucket{start: start}
+	}
+	bucket.total++
+	if burning {
+		bucket.burning++
+	}
+}
+
+// Status returns the counts and rates over the rolling window
+func (t *SLOTracker) Status() SLOStatus {
+	cutoff := time.Now().Add(-t.slo.Window)
+	status := SLOStatus{Name: t.slo.Name, Target: t.slo.Target}
+
+	t.mu.Lock()
+	for _, bucket := range t.buckets {
+		if bucket.start.After(cutoff) {
+			status.Total += bucket.total
+			status.Burning += bucket.burning
+		}
+	}
+	t.mu.Unlock()
+
+	if status.Total > 0 {
+		status.ErrorRate = float64(status.Burning) / float64(status.Total)
+	}
+	if budget := 1 - t.slo.Target; budget > 0 {
+		status.BurnRate = status.ErrorRate / budget
+	}
+	return status
+}
+
+// ErrorRate returns the fraction of budget burning events over the rolling window
+func (t *SLOTracker) ErrorRate() float64 {
+	return t.Status().ErrorRate
+}
+
+// BurnRate returns how fast the error budget is consumed; 1 means exactly on budget
+func (t *SLOTracker) BurnRate() float64 {
+	return t.Status().BurnRate
+}
+
+// ShouldShed reports whether the burn rate exceeds the given threshold, for load-shedding decisions
+func (t *SLOTracker) ShouldShed(maxBurnRate float64) bool {
+	return t.BurnRate() > maxBurnRate
+}
+
+// Publish exposes the tracker status through expvar under "slo.<name>". expvar names can only be
+// published once per process, so it returns ErrSLOPublished when the name is already taken
+func (t *SLOTracker) Publish() error {
+	name := "slo." + t.slo.Name
+	publishMu.Lock()
+	defer publishMu.Unlock()
+
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("%w: %s", ErrSLOPublished, name)
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return t.Status()
+	}))
+	return nil
+}
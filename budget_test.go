@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDefaultBudgetClassifier(t *testing.T) {
+	newErr := func(status int, opts ...CustomErrOption) error {
+		return GetAppErr(context.Background(), errors.New("failed"), GetCustomErr("ERR_SLO_1", "failed", false, opts...), status)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), true},
+		{"5xx without category", newErr(http.StatusBadGateway), true},
+		{"4xx without category", newErr(http.StatusNotFound), false},
+		{"unset status", GetAppErr(context.Background(), errors.New("boom"), nil, 0), true},
+		{"internal category", newErr(0, WithCategory(CategoryInternal)), true},
+		{"timeout category", newErr(0, WithCategory(CategoryTimeout)), true},
+		{"client category with 5xx", newErr(http.StatusInternalServerError, WithCategory(CategoryValidation)), false},
+		{"warning severity", newErr(http.StatusServiceUnavailable, WithSeverity(SeverityWarn)), false},
+		{"critical severity", newErr(http.StatusServiceUnavailable, WithSeverity(SeverityCritical)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultBudgetClassifier(tt.err); got != tt.want {
+				t.Errorf("DefaultBudgetClassifier = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSLOTrackerRates(t *testing.T) {
+	burning := errors.New("boom")
+	tests := []struct {
+		name         string
+		good, bad    int
+		classifier   BudgetClassifier
+		wantRate     float64
+		wantBurnRate float64
+	}{
+		{"no events", 0, 0, nil, 0, 0},
+		{"on budget", 999, 1, nil, 0.001, 1},
+		{"burning fast", 90, 10, nil, 0.1, 100},
+		{"custom classifier", 90, 10, func(error) bool { return false }, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []SLOOption
+			if tt.classifier != nil {
+				opts = append(opts, WithBudgetClassifier(tt.classifier))
+			}
+			tracker := NewSLOTracker(SLO{Name: "checkout", Target: 0.999, Window: time.Minute}, opts...)
+			for i := 0; i < tt.good; i++ {
+				tracker.Record(nil)
+			}
+			for i := 0; i < tt.bad; i++ {
+				tracker.Record(burning)
+			}
+
+			status := tracker.Status()
+			if status.Total != uint64(tt.good+tt.bad) {
+				t.Errorf("Total = %d, want %d", status.Total, tt.good+tt.bad)
+			}
+			if math.Abs(status.ErrorRate-tt.wantRate) > 1e-9 || math.Abs(status.BurnRate-tt.wantBurnRate) > 1e-6 {
+				t.Errorf("rates %v/%v, want %v/%v", status.ErrorRate, status.BurnRate, tt.wantRate, tt.wantBurnRate)
+			}
+			if shed := tracker.ShouldShed(10); shed != (tt.wantBurnRate > 10) {
+				t.Errorf("ShouldShed(10) = %v at burn rate %v", shed, status.BurnRate)
+			}
+		})
+	}
+}
+
+func TestSLOTrackerPublishOnce(t *testing.T) {
+	tracker := NewSLOTracker(SLO{Name: "budget-test-publish", Target: 0.99})
+	if err := tracker.Publish(); err != nil {
+		t.Fatalf("first Publish = %v", err)
+	}
+	if err := NewSLOTracker(SLO{Name: "budget-test-publish"}).Publish(); !errors.Is(err, ErrSLOPublished) {
+		t.Errorf("second Publish = %v, want %v", err, ErrSLOPublished)
+	}
+}
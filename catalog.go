@@ -0,0 +1,31 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// Catalog returns every custom error registered in the default registry, ordered by code, ready to
+// be exposed on an /errors endpoint or dumped as JSON for client SDK generation
+func Catalog() []RegistryEntry {
+	return defaultRegistry.Entries()
+}
+
+// CatalogHandler returns an http.Handler serving Catalog as JSON. Unlike DebugHandler it exposes
+// only the public error definitions and needs no authorization
+func CatalogHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set(c.HeaderAllow, "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set(c.HeaderContentType, c.ContentTypeJSON)
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(map[string]interface{}{"errors": Catalog()})
+	})
+}
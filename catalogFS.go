@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ErrInvalidCatalogEntry is returned when a catalog file entry has an unknown severity or category
+// or an HTTP code outside 400-599
+var ErrInvalidCatalogEntry = errors.New("invalid catalog entry")
+
+// CatalogFileEntry is the JSON representation of a custom error in a catalog file; a catalog
+// file holds an array of entries
+type CatalogFileEntry struct {
+	Code      string   `json:"code"`
+	Message   string   `json:"message"`
+	Retryable bool     `json:"retryable"`
+	Owner     string   `json:"owner,omitempty"`
+	Severity  Severity `json:"severity,omitempty"`  // Severity name such as "warn", SeverityError when unset
+	Category  Category `json:"category,omitempty"`  // One of the Category constants
+	HTTPCode  int      `json:"http_code,omitempty"` // Default HTTP status, overrides the category's
+}
+
+// customErr validates the entry and converts it into a CustomErr
+func (entry CatalogFileEntry) customErr() (*CustomErr, error) {
+	if _, ok := severityNames[entry.Severity]; entry.Severity != 0 && !ok {
+		return nil, fmt.Errorf("%w %s: unknown severity %d", ErrInvalidCatalogEntry, entry.Code, int(entry.Severity))
+	}
+	if entry.Category != "" && entry.Category.HTTPCode() == 0 {
+		return nil, fmt.Errorf("%w %s: unknown category %q", ErrInvalidCatalogEntry, entry.Code, entry.Category)
+	}
+	if entry.HTTPCode != 0 && (entry.HTTPCode < http.StatusBadRequest || entry.HTTPCode > 599) {
+		return nil, fmt.Errorf("%w %s: http_code %d is not an error status", ErrInvalidCatalogEntry, entry.Code, entry.HTTPCode)
+	}
+	return &CustomErr{
+		Code:      entry.Code,
+		Message:   entry.Message,
+		Retryable: entry.Retryable,
+		Severity:  entry.Severity,
+		Category:  entry.Category,
+		HTTPCode:  entry.HTTPCode,
+	}, nil
+}
+
+// LoadCatalogFS registers the custom errors of every catalog file matching pattern in fsys,
+// typically an embed.FS, so binaries ship with their error definitions compiled in
+func (r *Registry) LoadCatalogFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, file := range files {
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Entries are decoded one by one so an invalid entry does not discard the rest of the file
+		var entries []json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		for i, rawEntry := range entries {
+			var entry CatalogFileEntry
+			if err := json.Unmarshal(rawEntry, &entry); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w #%d: %w", file, ErrInvalidCatalogEntry, i, err))
+				continue
+			}
+			customErr, err := entry.customErr()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", file, err))
+				continue
+			}
+			// RegisterFor checks the code policy, reporting violations instead of panicking
+			if err := r.RegisterFor(entry.Owner, customErr); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadCatalogFS registers catalog files from fsys into the default registry
+func LoadCatalogFS(fsys fs.FS, pattern string) error {
+	return defaultRegistry.LoadCatalogFS(fsys, pattern)
+}
+
+// LoadLocaleFS registers the message bundle of every file matching pattern in fsys; each file is a
+// JSON object mapping codes to messages and is named after its locale, e.g. "locales/de.json"
+func LoadLocaleFS(fsys fs.FS, pattern string) error {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, file := range files {
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		locale := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		RegisterLocaleBundle(locale, messages)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadCatalogFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"errors/orders.json": {Data: []byte(`[
+			{"code": "ERR_CAT_1001", "message": "order not found", "owner": "orders",
+			 "severity": "warn", "category": "not_found"},
+			{"code": "ERR_CAT_1002", "message": "order locked", "retryable": true, "http_code": 423},
+			{"code": "ERR_CAT_1003", "message": "bad severity", "severity": "loud"},
+			{"code": "ERR_CAT_1004", "message": "bad severity level", "severity": 9},
+			{"code": "ERR_CAT_1005", "message": "bad category", "category": "weird"},
+			{"code": "ERR_CAT_1006", "message": "bad status", "http_code": 200},
+			{"code": "ERR_CAT_1007", "message": "bad status", "http_code": 600}
+		]`)},
+	}
+
+	r := NewRegistry()
+	err := r.LoadCatalogFS(fsys, "errors/*.json")
+	if !errors.Is(err, ErrInvalidCatalogEntry) {
+		t.Fatalf("LoadCatalogFS = %v, want %v", err, ErrInvalidCatalogEntry)
+	}
+
+	tests := []struct {
+		code string
+		want *CustomErr
+	}{
+		{"ERR_CAT_1001", &CustomErr{Code: "ERR_CAT_1001", Message: "order not found", Severity: SeverityWarn, Category: CategoryNotFound}},
+		{"ERR_CAT_1002", &CustomErr{Code: "ERR_CAT_1002", Message: "order locked", Retryable: true, HTTPCode: 423}},
+		{"ERR_CAT_1003", nil},
+		{"ERR_CAT_1004", nil},
+		{"ERR_CAT_1005", nil},
+		{"ERR_CAT_1006", nil},
+		{"ERR_CAT_1007", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, ok := r.Lookup(tt.code)
+			if ok != (tt.want != nil) {
+				t.Fatalf("Lookup(%s) found = %v, want %v", tt.code, ok, tt.want != nil)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lookup(%s) = %+v, want %+v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLocaleFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/de.json":    {Data: []byte(`{"ERR_LOC_FS_1": "Bestellung nicht gefunden"}`)},
+		"locales/pt-BR.json": {Data: []byte(`{"ERR_LOC_FS_1": "pedido não encontrado"}`)},
+		"locales/fr.json":    {Data: []byte(`not json`)},
+	}
+	if err := LoadLocaleFS(fsys, "locales/*.json"); err == nil {
+		t.Fatal("LoadLocaleFS = nil, want the error of the malformed bundle")
+	}
+
+	tests := []struct {
+		locale string
+		want   string
+		found  bool
+	}{
+		{"de", "Bestellung nicht gefunden", true},
+		{"pt-BR", "pedido não encontrado", true},
+		{"fr", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			got, ok := LocaleMessage(tt.locale, "ERR_LOC_FS_1")
+			if got != tt.want || ok != tt.found {
+				t.Errorf("LocaleMessage(%s) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.found)
+			}
+		})
+	}
+}
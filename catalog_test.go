@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestRegistryEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		owner     string
+		customErr *CustomErr
+		want      RegistryEntry
+	}{
+		{"plain", "", GetCustomErr("ERR_CAT_1", "plain", false),
+			RegistryEntry{Code: "ERR_CAT_1", Message: "plain"}},
+		{"category", "", GetCustomErr("ERR_CAT_2", "missing", false, WithCategory(CategoryNotFound)),
+			RegistryEntry{Code: "ERR_CAT_2", Message: "missing", Category: CategoryNotFound, HTTPCode: http.StatusNotFound}},
+		{"default HTTP code", "search", GetCustomErr("ERR_CAT_3", "busy", true, WithDefaultHTTPCode(http.StatusServiceUnavailable)),
+			RegistryEntry{Code: "ERR_CAT_3", Message: "busy", Retryable: true, HTTPCode: http.StatusServiceUnavailable, Owner: "search"}},
+	}
+
+	r := NewRegistry()
+	var want []RegistryEntry
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.RegisterFor(tt.owner, tt.customErr); err != nil {
+				t.Fatalf("RegisterFor: %v", err)
+			}
+			want = append(want, tt.want)
+			if got := r.Entries(); !reflect.DeepEqual(got, want) {
+				t.Errorf("Entries = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCatalogHandler(t *testing.T) {
+	MustRegister(GetCustomErr("ERR_CAT_4", "catalogued", true, WithCategory(CategoryTimeout)))
+	want := RegistryEntry{Code: "ERR_CAT_4", Message: "catalogued", Retryable: true, Category: CategoryTimeout, HTTPCode: http.StatusGatewayTimeout}
+
+	tests := []struct {
+		name       string
+		method     string
+		wantStatus int
+		wantEntry  bool
+	}{
+		{"GET", http.MethodGet, http.StatusOK, true},
+		{"HEAD", http.MethodHead, http.StatusOK, false},
+		{"POST", http.MethodPost, http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			CatalogHandler().ServeHTTP(rec, httptest.NewRequest(tt.method, "/errors", nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed {
+				if allow := rec.Header().Get(c.HeaderAllow); allow != "GET, HEAD" {
+					t.Errorf("Allow = %q, want GET, HEAD", allow)
+				}
+				return
+			}
+			if ct := rec.Header().Get(c.HeaderContentType); ct != c.ContentTypeJSON {
+				t.Errorf("Content-Type = %q, want %q", ct, c.ContentTypeJSON)
+			}
+			if !tt.wantEntry {
+				return
+			}
+
+			var body struct {
+				Errors []RegistryEntry `json:"errors"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding catalog: %v", err)
+			}
+			found := false
+			for _, entry := range body.Errors {
+				found = found || entry == want
+			}
+			if !found {
+				t.Errorf("catalog %+v does not list %+v", body.Errors, want)
+			}
+		})
+	}
+}
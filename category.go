@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Category classifies errors so handlers can branch on the class of an error rather than on
+// individual codes; every category implies a default HTTP status
+type Category string
+
+// Error categories
+const (
+	CategoryValidation  Category = "validation"   // Invalid input, 400
+	CategoryAuth        Category = "auth"         // Missing or invalid credentials, 401
+	CategoryForbidden   Category = "forbidden"    // Authenticated but not allowed, 403
+	CategoryNotFound    Category = "not_found"    // Missing resource, 404
+	CategoryConflict    Category = "conflict"     // Conflicting state, e.g. duplicates, 409
+	CategoryRateLimited Category = "rate_limited" // Too many requests, 429
+	CategoryInternal    Category = "internal"     // Bug or unexpected failure, 500
+	CategoryUpstream    Category = "upstream"     // Failing dependency, 502
+	CategoryUnavailable Category = "unavailable"  // Temporarily unavailable, 503
+	CategoryTimeout     Category = "timeout"      // Dependency timed out, 504
+)
+
+// categoryHTTPCodes holds the default HTTP status of each category
+var categoryHTTPCodes = map[Category]int{
+	CategoryValidation:  http.StatusBadRequest,
+	CategoryAuth:        http.StatusUnauthorized,
+	CategoryForbidden:   http.StatusForbidden,
+	CategoryNotFound:    http.StatusNotFound,
+	CategoryConflict:    http.StatusConflict,
+	CategoryRateLimited: http.StatusTooManyRequests,
+	CategoryInternal:    http.StatusInternalServerError,
+	CategoryUpstream:    http.StatusBadGateway,
+	CategoryUnavailable: http.StatusServiceUnavailable,
+	CategoryTimeout:     http.StatusGatewayTimeout,
+}
+
+// HTTPCode returns the default HTTP status of the category, 0 for unknown categories
+func (c Category) HTTPCode() int {
+	return categoryHTTPCodes[c]
+}
+
+// categoryForStatus returns the category matching an HTTP status, empty when there is none
+func categoryForStatus(status int) Category {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CategoryValidation
+	case http.StatusUnauthorized:
+		return CategoryAuth
+	case http.StatusForbidden:
+		return CategoryForbidden
+	case http.StatusNotFound:
+		return CategoryNotFound
+	case http.StatusConflict:
+		return CategoryConflict
+	case http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case http.StatusBadGateway:
+		return CategoryUpstream
+	case http.StatusServiceUnavailable:
+		return CategoryUnavailable
+	case http.StatusGatewayTimeout:
+		return CategoryTimeout
+	}
+	if status >= http.StatusInternalServerError {
+		return CategoryInternal
+	}
+	return ""
+}
+
+// WithCategory sets the category of the custom error; AppErrors created with it default to the
+// category's HTTP status when none is given
+func WithCategory(category Category) CustomErrOption {
+	return func(ce *CustomErr) {
+		ce.Category = category
+	}
+}
+
+// GetCategory retrieves the category of the error, empty when unset
+func (e *AppError) GetCategory() Category {
+	if e.CustomErr == nil {
+		return ""
+	}
+	return e.CustomErr.Category
+}
+
+// SetCategory updates the category of the error and returns the AppError
+func (e *AppError) SetCategory(category Category) *AppError {
+	if e.CustomErr == nil {
+		e.CustomErr = &CustomErr{}
+	}
+	e.CustomErr.Category = category
+	return e
+}
+
+// IsCategory reports whether err is an AppError, or wraps a CustomErr, of the given category
+func IsCategory(err error, category Category) bool {
+	if appErr, ok := asAppError(err); ok {
+		return appErr.GetCategory() == category
+	}
+	var customErr *CustomErr
+	return errors.As(err, &customErr) && customErr.Category == category
+}
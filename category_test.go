@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCategoryForStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		want     Category
+		wantCode int // HTTP status of the category
+	}{
+		{http.StatusBadRequest, CategoryValidation, http.StatusBadRequest},
+		{http.StatusUnprocessableEntity, CategoryValidation, http.StatusBadRequest},
+		{http.StatusUnauthorized, CategoryAuth, http.StatusUnauthorized},
+		{http.StatusForbidden, CategoryForbidden, http.StatusForbidden},
+		{http.StatusNotFound, CategoryNotFound, http.StatusNotFound},
+		{http.StatusConflict, CategoryConflict, http.StatusConflict},
+		{http.StatusTooManyRequests, CategoryRateLimited, http.StatusTooManyRequests},
+		{http.StatusInternalServerError, CategoryInternal, http.StatusInternalServerError},
+		{http.StatusNotImplemented, CategoryInternal, http.StatusInternalServerError},
+		{http.StatusBadGateway, CategoryUpstream, http.StatusBadGateway},
+		{http.StatusServiceUnavailable, CategoryUnavailable, http.StatusServiceUnavailable},
+		{http.StatusGatewayTimeout, CategoryTimeout, http.StatusGatewayTimeout},
+		{http.StatusTeapot, "", 0},
+		{http.StatusOK, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			got := categoryForStatus(tt.status)
+			if got != tt.want || got.HTTPCode() != tt.wantCode {
+				t.Errorf("categoryForStatus(%d) = %q (%d), want %q (%d)", tt.status, got, got.HTTPCode(), tt.want, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestIsCategory(t *testing.T) {
+	ctx := context.Background()
+	notFound := GetCustomErr("ERR_CTG_1", "order not found", false, WithCategory(CategoryNotFound))
+
+	tests := []struct {
+		name     string
+		err      error
+		category Category
+		want     bool
+	}{
+		{"AppError", GetAppErr(ctx, errors.New("no rows"), notFound, 0), CategoryNotFound, true},
+		{"other category", GetAppErr(ctx, errors.New("no rows"), notFound, 0), CategoryConflict, false},
+		{"wrapped AppError", fmt.Errorf("handler: %w", GetAppErr(ctx, errors.New("no rows"), notFound, 0)), CategoryNotFound, true},
+		{"overridden", GetAppErr(ctx, errors.New("dup"), notFound, 0).SetCategory(CategoryConflict), CategoryConflict, true},
+		{"CustomErr", fmt.Errorf("lookup: %w", notFound), CategoryNotFound, true},
+		{"plain error", errors.New("boom"), CategoryInternal, false},
+		{"nil", nil, CategoryInternal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCategory(tt.err, tt.category); got != tt.want {
+				t.Errorf("IsCategory(%v, %s) = %v, want %v", tt.err, tt.category, got, tt.want)
+			}
+		})
+	}
+
+	if notFound.Category != CategoryNotFound {
+		t.Errorf("SetCategory changed the shared definition to %q", notFound.Category)
+	}
+}
@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Classification is the sensitivity class of a data field
+type Classification int
+
+const (
+	ClassPublic     Classification = iota // Safe for clients
+	ClassInternal                         // Kept inside the organization: logs and recordings, never clients
+	ClassRestricted                       // Regulated data: never clients, stripped everywhere in compliance mode
+)
+
+// String returns the name of the classification
+func (c Classification) String() string {
+	switch c {
+	case ClassInternal:
+		return "internal"
+	case ClassRestricted:
+		return "restricted"
+	default:
+		return "public"
+	}
+}
+
+// parseClassification parses a classification name, defaulting to public
+func parseClassification(name string) Classification {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "internal":
+		return ClassInternal
+	case "restricted":
+		return ClassRestricted
+	default:
+		return ClassPublic
+	}
+}
+
+// ClassifiedValue annotates a data value with its classification
+type ClassifiedValue struct {
+	Class Classification
+	Value interface{}
+}
+
+// MarshalJSON encodes a placeholder for internal and restricted values marshalled directly, which
+// bypasses the classification rules; the data encoder unwraps them where their class is allowed
+func (v ClassifiedValue) MarshalJSON() ([]byte, error) {
+	if v.Class == ClassPublic {
+		return json.Marshal(v.Value)
+	}
+	return json.Marshal(redacted)
+}
+
+// Classified annotates a data value with its classification, e.g.
+// SetData(map[string]interface{}{"ssn": ae.Classified(ae.ClassRestricted, ssn)})
+func Classified(class Classification, value interface{}) ClassifiedValue {
+	return ClassifiedValue{Class: class, Value: value}
+}
+
+// keyClasses holds the classifications registered per data key, lower-cased
+var keyClasses = struct {
+	sync.RWMutex
+	classes map[string]Classification
+}{classes: map[string]Classification{}}
+
+// ClassifyKey classifies every data field with the given key (case-insensitive), at any depth
+func ClassifyKey(class Classification, keys ...string) {
+	keyClasses.Lock()
+	defer keyClasses.Unlock()
+	for _, key := range keys {
+		keyClasses.classes[strings.ToLower(key)] = class
+	}
+}
+
+// keyClass returns the classification registered for a data key
+func keyClass(key string) Classification {
+	keyClasses.RLock()
+	defer keyClasses.RUnlock()
+	return keyClasses.classes[strings.ToLower(key)]
+}
+
+// classifiedValueType is the reflected type of ClassifiedValue
+var classifiedValueType = reflect.TypeOf(ClassifiedValue{})
+
+// asClassifiedValue returns the ClassifiedValue held by v, looking through interfaces
+func asClassifiedValue(v reflect.Value) (ClassifiedValue, bool) {
+	for v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Type() != classifiedValueType {
+		return ClassifiedValue{}, false
+	}
+	return v.Interface().(ClassifiedValue), true
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestClassifiedValueEncoding(t *testing.T) {
+	tests := []struct {
+		name         string
+		class        Classification
+		clientFacing bool
+		compliance   bool
+		want         string
+	}{
+		{"public to client", ClassPublic, true, false, `{"v":"x"}`},
+		{"internal to client", ClassInternal, true, false, `{}`},
+		{"restricted to client", ClassRestricted, true, false, `{}`},
+		{"internal to logs", ClassInternal, false, false, `{"v":"x"}`},
+		{"restricted to logs", ClassRestricted, false, false, `{"v":"x"}`},
+		{"restricted in compliance mode", ClassRestricted, false, true, `{}`},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ComplianceMode = tt.compliance
+			SetConfig(cfg)
+
+			data := map[string]interface{}{"v": Classified(tt.class, "x")}
+			raw, _ := json.Marshal(encodeData(context.Background(), data, tt.clientFacing))
+			if string(raw) != tt.want {
+				t.Errorf("encodeData = %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifiedValueMarshalJSON(t *testing.T) {
+	tests := []struct {
+		class Classification
+		want  string
+	}{
+		{ClassPublic, `{"v":"x"}`},
+		{ClassInternal, `{"v":"[REDACTED]"}`},
+		{ClassRestricted, `{"v":"[REDACTED]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.class.String(), func(t *testing.T) {
+			raw, err := json.Marshal(map[string]interface{}{"v": Classified(tt.class, "x")})
+			if err != nil || string(raw) != tt.want {
+				t.Errorf("json.Marshal = %s, %v, want %s", raw, err, tt.want)
+			}
+		})
+	}
+}
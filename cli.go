@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Conventional process exit codes, following sysexits.h
+const (
+	ExitOK          = 0  // Successful termination
+	ExitFailure     = 1  // Generic failure, used for errors that are not AppErrors
+	ExitUsage       = 64 // Command used incorrectly
+	ExitDataErr     = 65 // Input data was incorrect
+	ExitNoInput     = 66 // Input did not exist
+	ExitUnavailable = 69 // A required service is unavailable
+	ExitSoftware    = 70 // Internal software error
+	ExitTempFail    = 75 // Temporary failure, retrying may succeed
+	ExitNoPerm      = 77 // Insufficient permission
+)
+
+// ExitCode maps an error to a conventional exit code so command-line tools can reuse the same
+// catalog as HTTP services: nil is 0, errors that are not AppErrors are 1 and AppErrors are mapped
+// from their retryability and category, or from their HTTP code when they have no category
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	appErr, ok := asAppError(err)
+	if !ok {
+		return ExitFailure
+	}
+	if appErr.CustomErr != nil && appErr.CustomErr.Retryable {
+		return ExitTempFail
+	}
+
+	switch appErr.GetCategory() {
+	case CategoryAuth, CategoryForbidden:
+		return ExitNoPerm
+	case CategoryNotFound:
+		return ExitNoInput
+	case CategoryValidation, CategoryConflict:
+		return ExitDataErr
+	case CategoryRateLimited, CategoryTimeout, CategoryUnavailable:
+		return ExitTempFail
+	case CategoryUpstream:
+		return ExitUnavailable
+	case CategoryInternal:
+		return ExitSoftware
+	}
+	return exitCodeForStatus(appErr.httpCode)
+}
+
+// exitCodeForStatus maps the HTTP code of an AppError without a known category to an exit code
+func exitCodeForStatus(status int) int {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ExitNoPerm
+	case status == http.StatusNotFound, status == http.StatusGone:
+		return ExitNoInput
+	case status == http.StatusBadRequest, status == http.StatusConflict, status == http.StatusUnprocessableEntity:
+		return ExitDataErr
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests,
+		status == http.StatusServiceUnavailable, status == http.StatusGatewayTimeout:
+		return ExitTempFail
+	case status == http.StatusBadGateway:
+		return ExitUnavailable
+	case status >= 400 && status < 500:
+		return ExitUsage
+	case status >= 500:
+		return ExitSoftware
+	default:
+		return ExitFailure
+	}
+}
+
+// RenderText renders an error as a compact single line for stderr, e.g.
+// "ERR_SVC_1001: database is not reachable (sql: connection is already closed)"
+func RenderText(err error) string {
+	if err == nil {
+		return ""
+	}
+	appErr, ok := asAppError(err)
+	if !ok || appErr.CustomErr == nil || appErr.CustomErr.Code == "" {
+		return singleLine(scrub(err.Error()))
+	}
+
+	var b strings.Builder
+	b.WriteString(appErr.CustomErr.Code)
+	if msg := appErr.CustomErr.Message; msg != "" {
+		b.WriteString(": " + msg)
+	}
+	if cause := appErr.Error(); cause != "" && cause != appErr.CustomErr.Message {
+		b.WriteString(" (" + cause + ")")
+	}
+	if appErr.CustomErr.Retryable {
+		b.WriteString(" [retryable")
+		if appErr.retryAfter > 0 {
+			b.WriteString(", retry after " + appErr.retryAfter.String())
+		}
+		b.WriteString("]")
+	}
+	return singleLine(scrub(b.String()))
+}
+
+// WriteText writes the single line rendering of err followed by a newline
+func WriteText(w io.Writer, err error) error {
+	_, werr := fmt.Fprintln(w, RenderText(err))
+	return werr
+}
+
+// singleLine collapses line breaks so the rendering always fits on one line
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\r", " ")), " ")
+}
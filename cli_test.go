@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExitCode(t *testing.T) {
+	ctx := context.Background()
+	cause := errors.New("cause")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain error", cause, ExitFailure},
+		{"retryable", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_1", "busy", true, WithCategory(CategoryNotFound)), 404), ExitTempFail},
+		{"auth category", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_2", "denied", false, WithCategory(CategoryAuth)), 500), ExitNoPerm},
+		{"not found category", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_3", "missing", false, WithCategory(CategoryNotFound)), 500), ExitNoInput},
+		{"validation category", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_4", "invalid", false, WithCategory(CategoryValidation)), 500), ExitDataErr},
+		{"upstream category", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_5", "bad gateway", false, WithCategory(CategoryUpstream)), 500), ExitUnavailable},
+		{"internal category", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_6", "bug", false, WithCategory(CategoryInternal)), 400), ExitSoftware},
+		{"status 403", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_7", "forbidden", false), 403), ExitNoPerm},
+		{"status 422", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_8", "unprocessable", false), 422), ExitDataErr},
+		{"status 429", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_9", "slow down", false), 429), ExitTempFail},
+		{"status 418", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_10", "teapot", false), 418), ExitUsage},
+		{"status 501", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_11", "not implemented", false), 501), ExitSoftware},
+		{"wrapped", fmt.Errorf("running: %w", GetAppErr(ctx, cause, GetCustomErr("ERR_CLI_12", "missing", false), 404)), ExitNoInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"plain error", errors.New("line one\nline two"), "line one line two"},
+		{"without cause", GetAppErr(ctx, errors.New("not found"), GetCustomErr("ERR_CLI_20", "not found", false), 404), "ERR_CLI_20: not found"},
+		{"with cause", GetAppErr(ctx, errors.New("sql: connection is already closed"), GetCustomErr("ERR_CLI_21", "database is not reachable", false), 503),
+			"ERR_CLI_21: database is not reachable (sql: connection is already closed)"},
+		{"retryable", GetAppErr(ctx, errors.New("timeout"), GetCustomErr("ERR_CLI_22", "upstream timed out", true), 504).SetRetryAfter(2 * time.Second),
+			"ERR_CLI_22: upstream timed out (timeout) [retryable, retry after 2s]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderText(tt.err); got != tt.want {
+				t.Errorf("RenderText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, errors.New("boom")); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	if buf.String() != "boom\n" {
+		t.Errorf("WriteText wrote %q, want %q", buf.String(), "boom\n")
+	}
+}
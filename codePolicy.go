@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidCode is returned when a code violates the configured code policy
+var ErrInvalidCode = errors.New("error code violates the code policy")
+
+// CodePolicy describes the format error codes must follow, e.g. a namespace prefix per team
+type CodePolicy struct {
+	Pattern  *regexp.Regexp // Expression codes must match, ignored when nil
+	Prefixes []string       // Prefixes of which codes must start with one, e.g. "ERR_SVC_", ignored when empty
+	Strict   bool           // Reject malformed codes: Register fails and GetCustomErr panics, otherwise they are only logged
+}
+
+// Check returns an error wrapping ErrInvalidCode when code violates the policy
+func (p *CodePolicy) Check(code string) error {
+	if p.Pattern != nil && !p.Pattern.MatchString(code) {
+		return fmt.Errorf("%w: %s does not match %s", ErrInvalidCode, code, p.Pattern)
+	}
+	if len(p.Prefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range p.Prefixes {
+		if strings.HasPrefix(code, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s does not start with any of %s", ErrInvalidCode, code, strings.Join(p.Prefixes, ", "))
+}
+
+// checkCode validates code against Config.CodePolicy; violations are logged and only returned in
+// strict mode
+func checkCode(code string) error {
+	// The package's own definitions are created before the configuration is initialized
+	cfg := currentConfig()
+	if cfg == nil || cfg.CodePolicy == nil {
+		return nil
+	}
+	policy := cfg.CodePolicy
+	err := policy.Check(code)
+	if err == nil {
+		return nil
+	}
+	if policy.Strict {
+		return err
+	}
+	getLogger().Warn("error code violates the code policy", "code", code, "error", err)
+	return nil
+}
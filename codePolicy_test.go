@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"testing"
+)
+
+func TestCodePolicyCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy CodePolicy
+		code   string
+		want   error
+	}{
+		{"empty policy", CodePolicy{}, "anything", nil},
+		{"matching pattern", CodePolicy{Pattern: regexp.MustCompile(`^ERR_[A-Z]+_\d+$`)}, "ERR_SVC_1", nil},
+		{"mismatching pattern", CodePolicy{Pattern: regexp.MustCompile(`^ERR_[A-Z]+_\d+$`)}, "err-svc-1", ErrInvalidCode},
+		{"known prefix", CodePolicy{Prefixes: []string{"ERR_SVC_", "ERR_AUTH_"}}, "ERR_AUTH_7", nil},
+		{"unknown prefix", CodePolicy{Prefixes: []string{"ERR_SVC_", "ERR_AUTH_"}}, "ERR_PAY_7", ErrInvalidCode},
+		{"pattern and prefix", CodePolicy{Pattern: regexp.MustCompile(`_\d+$`), Prefixes: []string{"ERR_SVC_"}}, "ERR_SVC_X", ErrInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Check(tt.code); !errors.Is(err, tt.want) {
+				t.Errorf("Check(%q) = %v, want %v", tt.code, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodePolicyEnforcement(t *testing.T) {
+	defer SetConfig(DefaultConfig())
+	SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer SetLogger(nil)
+
+	tests := []struct {
+		name         string
+		strict       bool
+		code         string
+		wantRegister error
+		wantPanic    bool
+	}{
+		{"valid code", true, "ERR_POL_1", nil, false},
+		{"lenient mode", false, "BAD_POL_2", nil, false},
+		{"strict mode", true, "BAD_POL_3", ErrInvalidCode, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.CodePolicy = &CodePolicy{Prefixes: []string{"ERR_"}, Strict: tt.strict}
+			SetConfig(cfg)
+
+			if err := NewRegistry().Register(&CustomErr{Code: tt.code}); !errors.Is(err, tt.wantRegister) {
+				t.Errorf("Register = %v, want %v", err, tt.wantRegister)
+			}
+
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("GetCustomErr panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			GetCustomErr(tt.code, "message", false)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+// Custom errors used by the helpers of this package
+var (
+	RateLimitExceeded = GetCustomErr(
+		"ERR_RATE_LIMITED",
+		"rate limit exceeded",
+		true,
+		WithCategory(CategoryRateLimited))
+	InternalError = GetCustomErr(
+		"ERR_INTERNAL",
+		"internal server error",
+		false,
+		WithCategory(CategoryInternal))
+	PanicRecovered = GetCustomErr(
+		"ERR_PANIC",
+		"internal server error",
+		false,
+		WithCategory(CategoryInternal))
+	ErrorLimitExceeded = GetCustomErr(
+		"ERR_ERROR_LIMIT_EXCEEDED",
+		"internal server error",
+		false,
+		WithCategory(CategoryInternal))
+	BadGateway = GetCustomErr(
+		"ERR_BAD_GATEWAY",
+		"upstream service is unavailable",
+		true,
+		WithCategory(CategoryUpstream))
+	GatewayTimeout = GetCustomErr(
+		"ERR_GATEWAY_TIMEOUT",
+		"upstream service timed out",
+		true,
+		WithCategory(CategoryTimeout))
+	ValidationFailed = GetCustomErr(
+		"ERR_VALIDATION",
+		"request validation failed",
+		false,
+		WithCategory(CategoryValidation))
+	ClientClosedRequest = GetCustomErr(
+		"ERR_CLIENT_CLOSED_REQUEST",
+		"client closed request",
+		false,
+		WithDefaultHTTPCode(StatusClientClosedRequest))
+	DeadlineExceeded = GetCustomErr(
+		"ERR_DEADLINE_EXCEEDED",
+		"request deadline exceeded",
+		true,
+		WithCategory(CategoryTimeout))
+)
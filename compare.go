@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// CompareOption configures Equal
+type CompareOption func(*compareOptions)
+
+// compareOptions holds the fields Equal takes into account beyond code, codes and HTTP status
+type compareOptions struct {
+	message    bool
+	data       bool
+	ignoreKeys map[string]struct{}
+}
+
+// CompareMessage makes Equal compare the client-facing messages
+func CompareMessage() CompareOption {
+	return func(o *compareOptions) {
+		o.message = true
+	}
+}
+
+// CompareData makes Equal compare data, ignoring the given keys at any depth (timestamps, IDs)
+func CompareData(ignoreKeys ...string) CompareOption {
+	return func(o *compareOptions) {
+		o.data = true
+		for _, key := range ignoreKeys {
+			o.ignoreKeys[key] = struct{}{}
+		}
+	}
+}
+
+// Equal reports whether two AppErrors have the same primary code, error code list and HTTP status,
+// and optionally the same message and data. Volatile fields such as the error ID and stack are
+// never compared. Data is compared by its JSON representation, so an int and the float64 it
+// decodes to are equal.
+func Equal(a, b *AppError, opts ...CompareOption) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	o := &compareOptions{ignoreKeys: map[string]struct{}{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if primaryCode(a) != primaryCode(b) || a.httpCode != b.httpCode || !equalCodes(a.ErrorCodes, b.ErrorCodes) {
+		return false
+	}
+	if o.message && a.GetMsg() != b.GetMsg() {
+		return false
+	}
+	if o.data && !equalData(a.data, b.data, o.ignoreKeys) {
+		return false
+	}
+	return true
+}
+
+// equalCodes compares code lists, treating nil and empty as equal
+func equalCodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// equalData compares data through its JSON representation with the ignored keys removed
+func equalData(a, b interface{}, ignoreKeys map[string]struct{}) bool {
+	na, errA := normalizeJSON(a)
+	nb, errB := normalizeJSON(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return reflect.DeepEqual(stripKeys(na, ignoreKeys), stripKeys(nb, ignoreKeys))
+}
+
+// normalizeJSON round-trips a value through JSON into generic values
+func normalizeJSON(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(raw, &out)
+	return out, err
+}
+
+// stripKeys removes the ignored keys from generic JSON values at any depth
+func stripKeys(v interface{}, ignoreKeys map[string]struct{}) interface{} {
+	if len(ignoreKeys) == 0 {
+		return v
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			if _, ignored := ignoreKeys[key]; ignored {
+				delete(t, key)
+				continue
+			}
+			t[key] = stripKeys(value, ignoreKeys)
+		}
+	case []interface{}:
+		for i, value := range t {
+			t[i] = stripKeys(value, ignoreKeys)
+		}
+	}
+	return v
+}
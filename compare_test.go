@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	customErr := GetCustomErr("ERR_CMP_1", "order not found", false)
+	other := GetCustomErr("ERR_CMP_2", "order locked", false)
+	newErr := func(customErr *CustomErr, status int, data interface{}) *AppError {
+		return GetAppErr(context.Background(), errors.New("failed"), customErr, status, data)
+	}
+	base := newErr(customErr, http.StatusNotFound, map[string]interface{}{"id": 1, "at": "10:00"})
+
+	tests := []struct {
+		name string
+		b    *AppError
+		opts []CompareOption
+		want bool
+	}{
+		{"same fields", newErr(customErr, http.StatusNotFound, nil), nil, true},
+		{"other code", newErr(other, http.StatusNotFound, nil), nil, false},
+		{"other status", newErr(customErr, http.StatusGone, nil), nil, false},
+		{"other code chain", Wrap(context.Background(), newErr(other, 0, nil), customErr, http.StatusNotFound), nil, false},
+		{"message ignored", newErr(customErr, http.StatusNotFound, nil).SetMsg("gone"), nil, true},
+		{"message compared", newErr(customErr, http.StatusNotFound, nil).SetMsg("gone"), []CompareOption{CompareMessage()}, false},
+		{"data ignored", newErr(customErr, http.StatusNotFound, map[string]interface{}{"id": 2}), nil, true},
+		{"data compared", newErr(customErr, http.StatusNotFound, map[string]interface{}{"id": 2, "at": "10:00"}), []CompareOption{CompareData()}, false},
+		{"int and float data", newErr(customErr, http.StatusNotFound, map[string]interface{}{"id": 1.0, "at": "10:00"}), []CompareOption{CompareData()}, true},
+		{"ignored data keys", newErr(customErr, http.StatusNotFound, map[string]interface{}{"id": 1, "at": "11:00"}), []CompareOption{CompareData("at")}, true},
+		{"nil", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(base, tt.b, tt.opts...); got != tt.want {
+				t.Errorf("Equal = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !Equal(nil, nil) {
+		t.Error("Equal(nil, nil) = false, want true")
+	}
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"sync/atomic"
+)
+
+// Config holds package wide settings applied when errors are created and rendered
+type Config struct {
+	StatsEnabled           bool        // Collect in-process error statistics for every created AppError, off by default as it locks once per error
+	MaxStatsKeys           int         // Distinct codes, and tenants, the statistics track before counting further ones as "other", 0 disables the bound
+	MaxDataBytes           int         // Encoded size above which data is replaced by a truncation marker, 0 disables
+	MaxStringLen           int         // Length above which strings in data are truncated (omitted for clients), 0 disables
+	ScrubSecrets           bool        // Scrub secrets from error text, messages, data and traces before they are exported
+	ComplianceMode         bool        // Strip restricted data and stack traces from every output leaving the process
+	MaxErrorsPerRequest    int         // AppErrors a request context may accumulate before further ones become Internal errors, 0 disables
+	CapturePanicGoroutines bool        // Attach a goroutine dump to the debug data of errors converted from panics
+	GoroutineDumpBytes     int         // Maximum size of the goroutine dump
+	StackDepth             int         // Maximum number of frames captured where an AppError is created, 0 disables capture
+	ProblemTypeBaseURI     string      // Base URI the primary code is appended to for the Problem Details "type", "about:blank" when empty
+	PanicCustomErr         *CustomErr  // Custom error of errors converted from panics, PanicRecovered when nil
+	MaxTraceEntries        int         // Trace error lines a TraceMeta retains, dropping the oldest beyond it, 0 disables the bound
+	CodePolicy             *CodePolicy // Format custom error codes must follow, checked by GetCustomErr and Register, nil disables
+}
+
+// DefaultConfig returns the configuration used when none has been set
+func DefaultConfig() Config {
+	return Config{
+		MaxStatsKeys:       defaultMaxStatsKeys,
+		MaxDataBytes:       64 << 10,
+		MaxStringLen:       4 << 10,
+		ScrubSecrets:       true,
+		GoroutineDumpBytes: defaultGoroutineDumpBytes,
+		StackDepth:         defaultStackDepth,
+		MaxTraceEntries:    defaultMaxTraceEntries,
+	}
+}
+
+// config holds the active configuration
+var config atomic.Pointer[Config]
+
+func init() {
+	cfg := DefaultConfig()
+	config.Store(&cfg)
+}
+
+// SetConfig replaces the active configuration
+func SetConfig(cfg Config) {
+	config.Store(&cfg)
+}
+
+// GetConfig returns a copy of the active configuration
+func GetConfig() Config {
+	return *config.Load()
+}
+
+// currentConfig returns the active configuration without copying it
+func currentConfig() *Config {
+	return config.Load()
+}
@@ -3,3 +3,58 @@ package errors
 const (
 	TraceMetaKey = "TraceMeta"
 )
+
+// Identifier keys set automatically in TraceMeta
+const (
+	RequestIDIdentifier = "request_id"
+)
+
+// Label keys set automatically on AppErrors
+const (
+	TenantLabel = "tenant"
+)
+
+// HTTP header names read and written by the HTTP helpers
+const (
+	HeaderAuthorization      = "Authorization"
+	HeaderAllow              = "Allow"
+	HeaderContentType        = "Content-Type"
+	HeaderRetryAfter         = "Retry-After"
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+	HeaderRequestID          = "X-Request-ID"
+	HeaderAcceptLanguage     = "Accept-Language"
+	HeaderContentLanguage    = "Content-Language"
+	HeaderVary               = "Vary"
+	HeaderTraceParent        = "traceparent"
+)
+
+// Content types written by the HTTP helpers
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeProblemJSON = "application/problem+json"
+)
+
+// gRPC metadata keys written by the gRPC helpers
+const (
+	GRPCRetryableKey     = "x-retryable"
+	GRPCRetryAfterKey    = "retry-after"
+	GRPCRetryPushbackKey = "grpc-retry-pushback-ms"
+)
+
+// Server-Sent Events names written by the SSE helpers
+const (
+	SSEErrorEvent = "error"
+)
+
+// HTTP header names written by the webhook notifier
+const (
+	HeaderWebhookTimestamp = "X-AppError-Timestamp"
+	HeaderWebhookSignature = "X-AppError-Signature"
+)
+
+// Domain of the google.rpc.ErrorInfo details written by the gRPC helpers
+const (
+	GRPCErrorDomain = "app-error.piyushkumar96.github.com"
+)
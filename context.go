@@ -2,27 +2,277 @@ package errors
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
 
 	c "github.com/piyushkumar96/app-error/constants"
 )
 
+// TraceMeta collects the trace of a request; it is safe for concurrent use by the package's helpers,
+// so handlers may fan out goroutines sharing the request context. Read it through Snapshot while
+// the request is in flight
 type TraceMeta struct {
-	Trace              []string
-	Error              []string
+	Trace              []TraceEntry // Steps of the request, e.g. the route or RPC method
+	Error              []TraceEntry // Errors added with AddTraceLog or while creating AppErrors; a ring once bounded, so read it through Snapshot or ErrorLines
 	IdentifierMappings map[string]interface{}
+	mu                 sync.Mutex // Guards the fields above and below
+	errorCount         int        // Number of AppErrors created with the request context
+	lastError          string     // Last message appended to Error, before repeat collapsing
+	lastErrorRepeats   int        // Consecutive times lastError was appended
+	dropped            int        // Error lines dropped to stay within Config.MaxTraceEntries
+	errorHead          int        // Index of the oldest line once Error is a full ring of Config.MaxTraceEntries lines
 }
 
-func AddTraceLog(ctx context.Context, errorMsg string) *TraceMeta {
+// defaultMaxTraceEntries is the default number of trace error lines a TraceMeta retains
+const defaultMaxTraceEntries = 1000
+
+// ctxKey is the type of the context keys owned by this package, so they cannot collide with keys
+// set by other packages; values are reached through accessors such as TenantFromContext
+type ctxKey int
+
+// Context keys of the values stored by this package
+const (
+	tenantCtxKey ctxKey = iota
+	debugOutputCtxKey
+	endpointCtxKey
+)
+
+// WithTenant returns a copy of ctx carrying the tenant (organization) ID; AppErrors created with it
+// are labeled with the tenant, which flows into statistics and reporter tags
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey, tenantID)
+}
+
+// TenantFromContext returns the tenant ID stored by WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
 	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantCtxKey).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// ErrorCount returns how many AppErrors were created with the request context; it requires a
+// TraceMeta in ctx and is 0 otherwise
+func ErrorCount(ctx context.Context) int {
+	traceMeta, ok := TraceFromContext(ctx)
+	if !ok {
+		return 0
+	}
+	traceMeta.mu.Lock()
+	defer traceMeta.mu.Unlock()
+	return traceMeta.errorCount
+}
+
+// TooManyErrors reports whether more than n AppErrors were created with the request context
+func TooManyErrors(ctx context.Context, n int) bool {
+	return ErrorCount(ctx) > n
+}
+
+// CheckErrorLimit returns an Internal AppError once the request context has accumulated more
+// AppErrors than Config.MaxErrorsPerRequest allows, so callers can fail fast; nil otherwise
+func CheckErrorLimit(ctx context.Context) *AppError {
+	limit := currentConfig().MaxErrorsPerRequest
+	if limit <= 0 || !TooManyErrors(ctx, limit) {
 		return nil
 	}
+	return GetAppErr(ctx, fmt.Errorf("request exceeded the limit of %d errors", limit),
+		ErrorLimitExceeded, http.StatusInternalServerError)
+}
+
+// NewTraceMeta returns an empty TraceMeta ready to be stored in a context
+func NewTraceMeta() *TraceMeta {
+	return &TraceMeta{IdentifierMappings: map[string]interface{}{}}
+}
+
+// ContextWithTrace returns a copy of ctx carrying a new TraceMeta, so AddTraceLog and GetAppErr
+// record into it; a ctx already carrying a TraceMeta is returned unchanged
+func ContextWithTrace(ctx context.Context) context.Context {
+	if _, ok := TraceFromContext(ctx); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, c.TraceMetaKey, NewTraceMeta())
+}
+
+// TraceFromContext returns the TraceMeta stored in ctx by ContextWithTrace or one of the middlewares
+func TraceFromContext(ctx context.Context) (*TraceMeta, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	traceMeta, ok := ctx.Value(c.TraceMetaKey).(*TraceMeta)
+	return traceMeta, ok && traceMeta != nil
+}
 
-	trace := ctx.Value(c.TraceMetaKey)
-	traceMeta, ok := trace.(*TraceMeta)
+// traceSnapshot returns a snapshot of the TraceMeta stored in ctx
+func traceSnapshot(ctx context.Context) (*TraceMeta, bool) {
+	traceMeta, ok := TraceFromContext(ctx)
 	if !ok {
+		return nil, false
+	}
+	return traceMeta.Snapshot(), true
+}
+
+// Snapshot returns a copy of the trace and identifiers that stays consistent while other goroutines
+// keep adding to the TraceMeta
+func (t *TraceMeta) Snapshot() *TraceMeta {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := &TraceMeta{
+		Trace:      append([]TraceEntry(nil), t.Trace...),
+		Error:      t.errorEntries(),
+		errorCount: t.errorCount,
+		dropped:    t.dropped,
+	}
+	snapshot.IdentifierMappings = copyIdentifiers(t.IdentifierMappings)
+	return snapshot
+}
+
+// identifiers returns a copy of the identifier mappings, nil when there are none
+func (t *TraceMeta) identifiers() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return copyIdentifiers(t.IdentifierMappings)
+}
+
+// TraceLines returns the messages of the trace steps
+func (t *TraceMeta) TraceLines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return traceMessages(t.Trace)
+}
+
+// ErrorLines returns the messages of the trace errors, as recorded before entries carried a
+// timestamp and caller
+func (t *TraceMeta) ErrorLines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return traceMessages(t.errorEntries())
+}
+
+// errorEntries returns a copy of the trace errors, oldest first; the caller holds the lock
+func (t *TraceMeta) errorEntries() []TraceEntry {
+	if len(t.Error) == 0 {
 		return nil
 	}
+	entries := make([]TraceEntry, 0, len(t.Error))
+	entries = append(entries, t.Error[t.errorHead:]...)
+	return append(entries, t.Error[:t.errorHead]...)
+}
 
-	traceMeta.Error = append(traceMeta.Error, errorMsg)
+// Dropped returns how many of the oldest trace error lines were dropped to stay within
+// Config.MaxTraceEntries
+func (t *TraceMeta) Dropped() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.dropped
+}
+
+// countError counts an AppError created with the request context and returns the new count
+func (t *TraceMeta) countError() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errorCount++
+	return t.errorCount
+}
+
+// AddIdentifier stores an identifier such as a user, order or request ID in the TraceMeta of ctx;
+// AppErrors created with ctx carry the identifiers into their logs, recordings and internal
+// serializations. It returns the TraceMeta, or nil when ctx carries none
+func AddIdentifier(ctx context.Context, key string, value interface{}) *TraceMeta {
+	traceMeta, ok := TraceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	traceMeta.mu.Lock()
+	defer traceMeta.mu.Unlock()
+	if traceMeta.IdentifierMappings == nil {
+		traceMeta.IdentifierMappings = map[string]interface{}{}
+	}
+	traceMeta.IdentifierMappings[key] = value
 	return traceMeta
 }
+
+// GetIdentifiers returns a copy of the identifiers stored in the TraceMeta of ctx, nil when there
+// are none
+func GetIdentifiers(ctx context.Context) map[string]interface{} {
+	traceMeta, ok := TraceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return traceMeta.identifiers()
+}
+
+// copyIdentifiers returns a copy of the identifiers, nil when there are none
+func copyIdentifiers(identifiers map[string]interface{}) map[string]interface{} {
+	if len(identifiers) == 0 {
+		return nil
+	}
+	cp := make(map[string]interface{}, len(identifiers))
+	for k, v := range identifiers {
+		cp[k] = v
+	}
+	return cp
+}
+
+// AddTraceLog appends an error message to the trace stored in ctx, stamped with the time and the
+// caller; it returns the TraceMeta, or nil when ctx carries none
+func AddTraceLog(ctx context.Context, errorMsg string) *TraceMeta {
+	traceMeta, ok := TraceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	addTraceLog(traceMeta, errorMsg, caller(1))
+	return traceMeta
+}
+
+// AddTraceLogf is AddTraceLog with a format string; the message is only formatted when ctx carries
+// a TraceMeta, so hot paths pay nothing for tracing when it is off
+func AddTraceLogf(ctx context.Context, format string, args ...interface{}) *TraceMeta {
+	traceMeta, ok := TraceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	addTraceLog(traceMeta, fmt.Sprintf(format, args...), caller(1))
+	return traceMeta
+}
+
+// addTraceLog appends an error message recorded at pc to the trace
+func addTraceLog(traceMeta *TraceMeta, errorMsg string, pc uintptr) {
+	errorMsg = scrub(errorMsg)
+	entry := newTraceEntry(slog.LevelError, errorMsg, pc)
+
+	traceMeta.mu.Lock()
+	defer traceMeta.mu.Unlock()
+
+	// Collapse consecutive repeats into a single "message (xN)" entry
+	if n := len(traceMeta.Error); n > 0 && traceMeta.lastErrorRepeats > 0 && errorMsg == traceMeta.lastError {
+		traceMeta.lastErrorRepeats++
+		last := (traceMeta.errorHead + n - 1) % n
+		traceMeta.Error[last].Message = fmt.Sprintf("%s (x%d)", errorMsg, traceMeta.lastErrorRepeats)
+		return
+	}
+
+	// Keep the most recent lines once the bound is reached, overwriting the oldest in place
+	limit := currentConfig().MaxTraceEntries
+	if limit > 0 && len(traceMeta.Error) == limit {
+		traceMeta.Error[traceMeta.errorHead] = entry
+		traceMeta.errorHead = (traceMeta.errorHead + 1) % limit
+		traceMeta.dropped++
+	} else {
+		// The bound changed since the ring filled up: restore the order before trimming or growing
+		if traceMeta.errorHead != 0 {
+			traceMeta.Error, traceMeta.errorHead = traceMeta.errorEntries(), 0
+		}
+		if drop := len(traceMeta.Error) - limit + 1; limit > 0 && drop > 0 {
+			traceMeta.Error = append([]TraceEntry(nil), traceMeta.Error[drop:]...)
+			traceMeta.dropped += drop
+		}
+		traceMeta.Error = append(traceMeta.Error, entry)
+	}
+	traceMeta.lastError = errorMsg
+	traceMeta.lastErrorRepeats = 1
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// FromContextError converts a context error into an AppError: context.Canceled becomes a 499
+// ERR_CLIENT_CLOSED_REQUEST and context.DeadlineExceeded a retryable 504 ERR_DEADLINE_EXCEEDED.
+// Wrapped context errors are detected too, and AppErrors wrapping one are extended like Wrap does.
+// It returns nil when err is not a context error, so callers can fall back to their own mapping
+func FromContextError(ctx context.Context, err error) *AppError {
+	customErr, status := contextCustomErr(err)
+	if customErr == nil {
+		return nil
+	}
+	return wrapAppErr(ctx, err, customErr, status, 2)
+}
+
+// IsContextError reports whether err is, or wraps, context.Canceled or context.DeadlineExceeded
+func IsContextError(err error) bool {
+	customErr, _ := contextCustomErr(err)
+	return customErr != nil
+}
+
+// contextCustomErr returns the custom error and HTTP status matching a context error, nil otherwise
+func contextCustomErr(err error) (*CustomErr, int) {
+	switch {
+	case err == nil:
+		return nil, 0
+	case errors.Is(err, context.Canceled):
+		return ClientClosedRequest, StatusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded, http.StatusGatewayTimeout
+	}
+	return nil, 0
+}
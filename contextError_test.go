@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFromContextError(t *testing.T) {
+	ctx := context.Background()
+	upstream := GetAppErr(ctx, context.DeadlineExceeded, GetCustomErr("ERR_CTX_1", "inventory call failed", false), http.StatusBadGateway)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantNil       bool
+		wantCodes     []string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{"nil", nil, true, nil, 0, false},
+		{"other error", errors.New("boom"), true, nil, 0, false},
+		{"canceled", context.Canceled, false, []string{ClientClosedRequest.Code}, StatusClientClosedRequest, false},
+		{"deadline", context.DeadlineExceeded, false, []string{DeadlineExceeded.Code}, http.StatusGatewayTimeout, true},
+		{"wrapped deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), false, []string{DeadlineExceeded.Code}, http.StatusGatewayTimeout, true},
+		{"AppError wrapping one", upstream, false, []string{"ERR_CTX_1", DeadlineExceeded.Code}, http.StatusGatewayTimeout, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsContextError(tt.err); got == tt.wantNil {
+				t.Errorf("IsContextError = %v, want %v", got, !tt.wantNil)
+			}
+
+			appErr := FromContextError(ctx, tt.err)
+			if (appErr == nil) != tt.wantNil {
+				t.Fatalf("FromContextError = %v, want nil %v", appErr, tt.wantNil)
+			}
+			if appErr == nil {
+				return
+			}
+			if !reflect.DeepEqual(appErr.GetErrCodes(), tt.wantCodes) || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromContextError = %v %d retryable=%v, want %v %d retryable=%v",
+					appErr.GetErrCodes(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCodes, tt.wantStatus, tt.wantRetryable)
+			}
+			if !IsContextError(appErr) {
+				t.Errorf("FromContextError lost the context error of %v", tt.err)
+			}
+		})
+	}
+}
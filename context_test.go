@@ -0,0 +1,382 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestContextAccessors(t *testing.T) {
+	spec := Endpoint("GET /ctx-test")
+	var fromMiddleware context.Context
+	spec.Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		fromMiddleware = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Plain string keys named like the package's values must not be picked up
+	foreign := context.WithValue(context.Background(), "Tenant", "acme")
+	foreign = context.WithValue(foreign, "DebugOutput", true)
+	foreign = context.WithValue(foreign, "Endpoint", spec)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantTenant   string
+		wantDebug    bool
+		wantEndpoint *EndpointSpec
+	}{
+		{"empty", context.Background(), "", false, nil},
+		{"tenant", WithTenant(context.Background(), "acme"), "acme", false, nil},
+		{"empty tenant", WithTenant(context.Background(), ""), "", false, nil},
+		{"debug output", WithDebugOutput(context.Background()), "", true, nil},
+		{"endpoint", fromMiddleware, "", false, spec},
+		{"all", WithDebugOutput(WithTenant(fromMiddleware, "acme")), "acme", true, spec},
+		{"foreign string keys", foreign, "", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, _ := TenantFromContext(tt.ctx)
+			endpoint, _ := EndpointFromContext(tt.ctx)
+			if tenant != tt.wantTenant || IsDebugOutput(tt.ctx) != tt.wantDebug || endpoint != tt.wantEndpoint {
+				t.Errorf("tenant %q, debug %v, endpoint %p, want %q, %v, %p",
+					tenant, IsDebugOutput(tt.ctx), endpoint, tt.wantTenant, tt.wantDebug, tt.wantEndpoint)
+			}
+		})
+	}
+}
+
+func TestTenantLabel(t *testing.T) {
+	customErr := GetCustomErr("ERR_TNT_1", "failed", false)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no tenant", context.Background(), ""},
+		{"tenant", WithTenant(context.Background(), "acme"), "acme"},
+		{"empty tenant", WithTenant(context.Background(), ""), ""},
+		{"innermost tenant", WithTenant(WithTenant(context.Background(), "acme"), "globex"), "globex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := GetAppErr(tt.ctx, errors.New("boom"), customErr, 500)
+			if got := appErr.GetTenant(); got != tt.want {
+				t.Errorf("GetTenant = %q, want %q", got, tt.want)
+			}
+			if _, ok := appErr.GetLabels()[c.TenantLabel]; ok != (tt.want != "") {
+				t.Errorf("labels = %v, want tenant label %v", appErr.GetLabels(), tt.want != "")
+			}
+		})
+	}
+}
+
+func TestErrorLimit(t *testing.T) {
+	customErr := GetCustomErr("ERR_LIM_1", "failed", false)
+
+	tests := []struct {
+		name     string
+		limit    int
+		created  int
+		wantCode string
+		wantFail bool
+	}{
+		{"disabled", 0, 5, "ERR_LIM_1", false},
+		{"below limit", 3, 2, "ERR_LIM_1", false},
+		{"at limit", 3, 3, "ERR_LIM_1", false},
+		{"over limit", 3, 4, ErrorLimitExceeded.Code, true},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxErrorsPerRequest = tt.limit
+			SetConfig(cfg)
+
+			ctx := ContextWithTrace(context.Background())
+			var last *AppError
+			for i := 0; i < tt.created; i++ {
+				last = GetAppErr(ctx, errors.New("boom"), customErr, 400)
+			}
+			if got := ErrorCount(ctx); got != tt.created {
+				t.Errorf("ErrorCount = %d, want %d", got, tt.created)
+			}
+			if last.CustomErr.Code != tt.wantCode {
+				t.Errorf("last error code = %s, want %s", last.CustomErr.Code, tt.wantCode)
+			}
+			if got := TooManyErrors(ctx, tt.limit); tt.limit > 0 && got != tt.wantFail {
+				t.Errorf("TooManyErrors = %v, want %v", got, tt.wantFail)
+			}
+			if got := CheckErrorLimit(ctx); (got != nil) != tt.wantFail || (got != nil && got.GetHTTPCode() != http.StatusInternalServerError) {
+				t.Errorf("CheckErrorLimit = %v, want failure %v", got, tt.wantFail)
+			}
+		})
+	}
+
+	if ErrorCount(context.Background()) != 0 || TooManyErrors(context.Background(), 0) {
+		t.Error("a context without a TraceMeta must count no errors")
+	}
+}
+
+func TestAddTraceLogCollapsesRepeats(t *testing.T) {
+	tests := []struct {
+		name string
+		msgs []string
+		want []string
+	}{
+		{"single", []string{"a"}, []string{"a"}},
+		{"repeats", []string{"a", "a", "a"}, []string{"a (x3)"}},
+		{"interleaved", []string{"a", "a", "b", "a"}, []string{"a (x2)", "b", "a"}},
+		{"runs", []string{"a", "b", "b", "c", "c", "c"}, []string{"a", "b (x2)", "c (x3)"}},
+		{"message ending like a count", []string{"a (x2)", "a (x2)"}, []string{"a (x2) (x2)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextWithTrace(context.Background())
+			for _, msg := range tt.msgs {
+				AddTraceLog(ctx, msg)
+			}
+			traceMeta, _ := TraceFromContext(ctx)
+			if got := traceMeta.ErrorLines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ErrorLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceMetaConcurrentUse(t *testing.T) {
+	const goroutines, iterations = 8, 50
+	const total = 2 * goroutines * iterations // Each iteration adds a trace line and creates an AppError
+	customErr := GetCustomErr("ERR_CNC_1", "failed", false)
+
+	tests := []struct {
+		name      string
+		limit     int
+		wantLines int
+	}{
+		{"unbounded", 0, total},
+		{"bounded", 64, 64},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxTraceEntries = tt.limit
+			SetConfig(cfg)
+
+			ctx := ContextWithTrace(context.Background())
+			traceMeta, _ := TraceFromContext(ctx)
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < iterations; i++ {
+						AddTraceLogf(ctx, "step %d-%d", g, i)
+						AddIdentifier(ctx, fmt.Sprintf("worker_%d", g), i)
+						GetAppErr(ctx, fmt.Errorf("failure %d-%d", g, i), customErr, 500)
+						_ = traceMeta.Snapshot()
+						_ = traceMeta.ErrorLines()
+						_ = GetIdentifiers(ctx)
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			snapshot := traceMeta.Snapshot()
+			if len(snapshot.Error) != tt.wantLines || len(snapshot.Error)+traceMeta.Dropped() != total {
+				t.Errorf("kept %d lines and dropped %d, want %d of %d", len(snapshot.Error), traceMeta.Dropped(), tt.wantLines, total)
+			}
+			if len(snapshot.IdentifierMappings) != goroutines {
+				t.Errorf("identifiers = %v, want one per goroutine", snapshot.IdentifierMappings)
+			}
+			if got := ErrorCount(ctx); got != goroutines*iterations {
+				t.Errorf("ErrorCount = %d, want %d", got, goroutines*iterations)
+			}
+		})
+	}
+}
+
+func TestTraceRingBuffer(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		msgs        []string
+		newLimit    int      // Bound applied before appending more, 0 keeps it
+		more        []string // Messages appended after the bound changed
+		want        []string
+		wantDropped int
+	}{
+		{"within bound", 3, []string{"a", "b"}, 0, nil, []string{"a", "b"}, 0},
+		{"at bound", 3, []string{"a", "b", "c"}, 0, nil, []string{"a", "b", "c"}, 0},
+		{"wraps", 3, []string{"a", "b", "c", "d", "e"}, 0, nil, []string{"c", "d", "e"}, 2},
+		{"wraps twice", 2, []string{"a", "b", "c", "d", "e"}, 0, nil, []string{"d", "e"}, 3},
+		{"repeat after wrap", 3, []string{"a", "b", "c", "d", "d", "d"}, 0, nil, []string{"b", "c", "d (x3)"}, 1},
+		{"repeats do not evict", 2, []string{"a", "b", "b", "b", "b"}, 0, nil, []string{"a", "b (x4)"}, 0},
+		{"unbounded", 0, []string{"a", "b", "c", "d"}, 0, nil, []string{"a", "b", "c", "d"}, 0},
+		{"shrinks after wrap", 3, []string{"a", "b", "c", "d"}, 2, []string{"e"}, []string{"d", "e"}, 3},
+		{"grows after wrap", 2, []string{"a", "b", "c"}, 4, []string{"d", "e"}, []string{"b", "c", "d", "e"}, 1},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxTraceEntries = tt.limit
+			SetConfig(cfg)
+
+			ctx := ContextWithTrace(context.Background())
+			for _, msg := range tt.msgs {
+				AddTraceLog(ctx, msg)
+			}
+			if tt.newLimit > 0 {
+				cfg.MaxTraceEntries = tt.newLimit
+				SetConfig(cfg)
+			}
+			for _, msg := range tt.more {
+				AddTraceLog(ctx, msg)
+			}
+
+			traceMeta, _ := TraceFromContext(ctx)
+			if got := traceMeta.ErrorLines(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ErrorLines = %q, want %q", got, tt.want)
+			}
+			if got := traceMessages(traceMeta.Snapshot().Error); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Snapshot().Error = %q, want %q", got, tt.want)
+			}
+			if traceMeta.Dropped() != tt.wantDropped {
+				t.Errorf("Dropped = %d, want %d", traceMeta.Dropped(), tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestTraceFromContext(t *testing.T) {
+	traced := ContextWithTrace(context.Background())
+	existing, _ := TraceFromContext(traced)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"nil context", nil, false},
+		{"untraced", context.Background(), false},
+		{"traced", traced, true},
+		{"nil TraceMeta", context.WithValue(context.Background(), c.TraceMetaKey, (*TraceMeta)(nil)), false},
+		{"foreign value", context.WithValue(context.Background(), c.TraceMetaKey, "trace"), false},
+		{"child context", context.WithValue(traced, ctxKey(99), "x"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceMeta, ok := TraceFromContext(tt.ctx)
+			if ok != tt.want || (ok && traceMeta == nil) {
+				t.Errorf("TraceFromContext = %v, %v, want found %v", traceMeta, ok, tt.want)
+			}
+			if added := AddTraceLog(tt.ctx, "step"); (added != nil) != tt.want {
+				t.Errorf("AddTraceLog = %v, want a TraceMeta %v", added, tt.want)
+			}
+		})
+	}
+
+	if again, _ := TraceFromContext(ContextWithTrace(traced)); again != existing {
+		t.Error("ContextWithTrace must keep the TraceMeta already in the context")
+	}
+	if fresh := NewTraceMeta(); fresh.IdentifierMappings == nil || len(fresh.Trace) != 0 || len(fresh.Error) != 0 {
+		t.Errorf("NewTraceMeta = %+v, want an empty TraceMeta with identifier mappings", fresh)
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		identifiers map[string]interface{}
+		want        map[string]interface{}
+	}{
+		{"untraced", context.Background(), map[string]interface{}{"user_id": "u1"}, nil},
+		{"traced without identifiers", ContextWithTrace(context.Background()), nil, nil},
+		{"traced", ContextWithTrace(context.Background()),
+			map[string]interface{}{"user_id": "u1", "order_id": 42},
+			map[string]interface{}{"user_id": "u1", "order_id": 42}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.identifiers {
+				if traceMeta := AddIdentifier(tt.ctx, k, v); (traceMeta != nil) != (tt.want != nil) {
+					t.Errorf("AddIdentifier = %v, want a TraceMeta %v", traceMeta, tt.want != nil)
+				}
+			}
+
+			got := GetIdentifiers(tt.ctx)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetIdentifiers = %v, want %v", got, tt.want)
+			}
+			if got != nil {
+				got["user_id"] = "changed"
+				if GetIdentifiers(tt.ctx)["user_id"] != "u1" {
+					t.Error("GetIdentifiers must return a copy")
+				}
+			}
+
+			appErr := GetAppErr(tt.ctx, errors.New("boom"), InternalError, http.StatusInternalServerError)
+			if ids := appErr.GetIdentifiers(); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("AppError.GetIdentifiers = %v, want %v", ids, tt.want)
+			}
+			if ids := appErr.ToWire().Identifiers; !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("ToWire().Identifiers = %v, want %v", ids, tt.want)
+			}
+
+			// Identifiers logged without the request context fall back to the AppError's own
+			attr, ok := identifierAttr(context.Background(), appErr)
+			if ok != (tt.want != nil) {
+				t.Fatalf("identifierAttr found = %v, want %v", ok, tt.want != nil)
+			}
+			if ok && (attr.Key != "identifiers" || len(attr.Value.Group()) != len(tt.want) || attr.Value.Group()[0].Key != "order_id") {
+				t.Errorf("identifierAttr = %v, want a sorted identifiers group", attr)
+			}
+		})
+	}
+}
+
+// countingStringer counts how often it is formatted
+type countingStringer struct{ calls *int }
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "order-7"
+}
+
+func TestAddTraceLogfFormatsLazily(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantCalls int
+		wantLines []string
+	}{
+		{"untraced", context.Background(), 0, nil},
+		{"nil context", nil, 0, nil},
+		{"traced", ContextWithTrace(context.Background()), 1, []string{"failed to load order-7: attempt 3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			traceMeta := AddTraceLogf(tt.ctx, "failed to load %s: attempt %d", countingStringer{&calls}, 3)
+			if calls != tt.wantCalls {
+				t.Errorf("argument formatted %d times, want %d", calls, tt.wantCalls)
+			}
+			if (traceMeta != nil) != (tt.wantLines != nil) {
+				t.Fatalf("AddTraceLogf = %v, want a TraceMeta %v", traceMeta, tt.wantLines != nil)
+			}
+			if traceMeta != nil && !reflect.DeepEqual(traceMeta.ErrorLines(), tt.wantLines) {
+				t.Errorf("ErrorLines = %v, want %v", traceMeta.ErrorLines(), tt.wantLines)
+			}
+		})
+	}
+}
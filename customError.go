@@ -2,16 +2,59 @@ package errors
 
 // CustomErr represents a structured custom error
 type CustomErr struct {
-	Code      string // The most important error code for API response
-	Message   string // Human-readable error message
-	Retryable bool   // Indicates whether the error is retryable
+	Code      string   // The most important error code for API response
+	Message   string   // Human-readable error message
+	Retryable bool     // Indicates whether the error is retryable
+	Severity  Severity // How serious the error is, SeverityError when unset
+	Category  Category // Class of the error, which implies a default HTTP status
+	HTTPCode  int      // Default HTTP status of AppErrors created without one, overrides the category's
 }
 
-// GetCustomErr creates a new instance of CustomErr
-func GetCustomErr(code, msg string, retryable bool) *CustomErr {
-	return &CustomErr{
+// GetCustomErr creates a new instance of CustomErr; the code is checked against Config.CodePolicy
+// and a violation panics in strict mode, as definitions are created when the program starts
+func GetCustomErr(code, msg string, retryable bool, opts ...CustomErrOption) *CustomErr {
+	if err := checkCode(code); err != nil {
+		panic(err)
+	}
+	customErr := &CustomErr{
 		Code:      code,
 		Message:   msg,
 		Retryable: retryable,
 	}
+	for _, opt := range opts {
+		opt(customErr)
+	}
+	return customErr
+}
+
+// Clone returns a copy of the custom error; AppErrors hold such a copy, so changing an AppError
+// never touches the shared definition it was created from
+func (ce *CustomErr) Clone() *CustomErr {
+	if ce == nil {
+		return nil
+	}
+	cp := *ce
+	return &cp
+}
+
+// Error implements the error interface so a CustomErr can be used as an errors.Is target
+func (ce *CustomErr) Error() string {
+	return ce.Code + ": " + ce.Message
+}
+
+// WithDefaultHTTPCode sets the HTTP status AppErrors created from the custom error get when the
+// call site gives none, e.g. GetAppErr with a zero httpCode or New without WithHTTPCode
+func WithDefaultHTTPCode(httpCode int) CustomErrOption {
+	return func(ce *CustomErr) {
+		ce.HTTPCode = httpCode
+	}
+}
+
+// DefaultHTTPCode returns the HTTP status of the custom error: its own HTTPCode, else the default
+// of its category, else 0
+func (ce *CustomErr) DefaultHTTPCode() int {
+	if ce.HTTPCode != 0 {
+		return ce.HTTPCode
+	}
+	return ce.Category.HTTPCode()
 }
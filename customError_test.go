@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDefaultHTTPCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []CustomErrOption
+		httpCode int // Status given at the call site
+		wantOwn  int // DefaultHTTPCode of the definition
+		wantHTTP int // Status of the AppError
+	}{
+		{"none", nil, 0, 0, 0},
+		{"call site only", nil, http.StatusTeapot, 0, http.StatusTeapot},
+		{"own default", []CustomErrOption{WithDefaultHTTPCode(http.StatusNotFound)}, 0, http.StatusNotFound, http.StatusNotFound},
+		{"category default", []CustomErrOption{WithCategory(CategoryConflict)}, 0, http.StatusConflict, http.StatusConflict},
+		{"own default beats category", []CustomErrOption{WithCategory(CategoryConflict), WithDefaultHTTPCode(http.StatusGone)},
+			0, http.StatusGone, http.StatusGone},
+		{"call site overrides", []CustomErrOption{WithDefaultHTTPCode(http.StatusNotFound)}, http.StatusGone, http.StatusNotFound, http.StatusGone},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customErr := GetCustomErr(fmt.Sprintf("ERR_DHC_%d", i), "message", false, tt.opts...)
+			if got := customErr.DefaultHTTPCode(); got != tt.wantOwn {
+				t.Errorf("DefaultHTTPCode = %d, want %d", got, tt.wantOwn)
+			}
+
+			appErr := GetAppErr(context.Background(), errors.New("boom"), customErr, tt.httpCode)
+			if got := appErr.GetHTTPCode(); got != tt.wantHTTP {
+				t.Errorf("GetAppErr status = %d, want %d", got, tt.wantHTTP)
+			}
+
+			opts := []Option{WithCustomErr(customErr)}
+			if tt.httpCode != 0 {
+				opts = append(opts, WithHTTPCode(tt.httpCode))
+			}
+			if got := New(context.Background(), errors.New("boom"), opts...).GetHTTPCode(); got != tt.wantHTTP {
+				t.Errorf("New status = %d, want %d", got, tt.wantHTTP)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Truncation markers inserted when data exceeds the configured limits
+const (
+	truncatedStringMarker = "...[truncated %d bytes]"
+	omittedStringMarker   = "[string of %d bytes omitted]"
+	omittedBytesMarker    = "[%d bytes omitted]"
+)
+
+// Placeholders replacing values encoding/json cannot encode
+const (
+	unsupportedValueMarker = "[unsupported %s]"
+	cyclicValueMarker      = "[cycle %s]"
+	unencodableValueMarker = "[unencodable %s]"
+)
+
+// dataEncoder prepares data values for serialization, applying the configured size limits
+type dataEncoder struct {
+	clientFacing bool // Output leaves the process towards clients
+	maxStringLen int  // Strings and byte slices longer than this are truncated or omitted
+	scrubSecrets bool // Scrub secrets from strings
+	compliance   bool // Strip restricted fields from every output
+
+	visiting map[visit]struct{} // Pointers, maps and slices on the current path, to detect cycles
+	issues   []string           // Values replaced by placeholders
+}
+
+// visit identifies a reference value on the current path
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// encodeData converts data into JSON friendly values with the active size limits applied, and
+// replaces it with a marker object when its encoded size exceeds Config.MaxDataBytes. Values that
+// cannot be encoded (channels, functions, NaN, cycles, failing marshalers) become placeholders and
+// are noted in the trace stored in ctx, so rendering an error never fails
+func encodeData(ctx context.Context, data interface{}, clientFacing bool) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	cfg := currentConfig()
+	enc := &dataEncoder{
+		clientFacing: clientFacing,
+		maxStringLen: cfg.MaxStringLen,
+		scrubSecrets: cfg.ScrubSecrets,
+		compliance:   cfg.ComplianceMode,
+	}
+	value := enc.encode(reflect.ValueOf(data))
+	for _, issue := range enc.issues {
+		AddTraceLog(ctx, "error data: "+issue)
+	}
+
+	if cfg.MaxDataBytes > 0 {
+		if raw, err := json.Marshal(value); err == nil && len(raw) > cfg.MaxDataBytes {
+			return map[string]interface{}{
+				"_truncated": true,
+				"_size":      len(raw),
+				"_limit":     cfg.MaxDataBytes,
+			}
+		}
+	}
+	return value
+}
+
+// encode walks a value, returning its JSON friendly representation
+func (d *dataEncoder) encode(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	// Classified values are unwrapped, or dropped when their class may not reach this output
+	if cv, ok := asClassifiedValue(v); ok {
+		if !d.allowed(cv.Class) {
+			return nil
+		}
+		return d.encode(reflect.ValueOf(cv.Value))
+	}
+
+	// Sensitive fields keep their key but never their value
+	if sv, ok := asSensitiveValue(v); ok {
+		return map[string]interface{}{sv.Key: redacted}
+	}
+
+	// Numbers decoded from marshaler output are kept as they are
+	if v.Type() == jsonNumberType {
+		return v.Interface()
+	}
+
+	// Values with their own encoding are encoded up front so their failures cannot surface later
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil
+		}
+		return d.marshal(v)
+	}
+
+	// Reference values already on the current path would recurse forever
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if !v.IsNil() {
+			key := visit{ptr: v.Pointer(), typ: v.Type()}
+			if _, ok := d.visiting[key]; ok {
+				return d.placeholder(cyclicValueMarker, v.Type())
+			}
+			if d.visiting == nil {
+				d.visiting = map[visit]struct{}{}
+			}
+			d.visiting[key] = struct{}{}
+			defer delete(d.visiting, key)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return d.encode(v.Elem())
+	case reflect.String:
+		return d.encodeString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return d.encodeBytes(v.Bytes())
+		}
+		return d.encodeList(v)
+	case reflect.Array:
+		return d.encodeList(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if d.stripped(keyClass(key), iter.Value()) {
+				continue
+			}
+			out[key] = d.encodeField(key, iter.Value())
+		}
+		return out
+	case reflect.Struct:
+		out := map[string]interface{}{}
+		d.encodeStruct(v, out)
+		return out
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return d.placeholder(unsupportedValueMarker, fmt.Sprint(f))
+		}
+		return v.Interface()
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return d.placeholder(unsupportedValueMarker, v.Type())
+	default:
+		if !v.CanInterface() {
+			return d.placeholder(unsupportedValueMarker, v.Type())
+		}
+		return v.Interface()
+	}
+}
+
+// marshal encodes a json or text Marshaler and encodes its decoded output like any other value, so
+// it is scrubbed, redacted and truncated too; failures and panics are replaced with a placeholder
+func (d *dataEncoder) marshal(v reflect.Value) (out interface{}) {
+	if !v.CanInterface() {
+		return d.placeholder(unsupportedValueMarker, v.Type())
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			out = d.issue(fmt.Sprintf(unencodableValueMarker, v.Type()), fmt.Sprint(recovered))
+		}
+	}()
+
+	raw, err := json.Marshal(v.Interface())
+	if err != nil {
+		return d.issue(fmt.Sprintf(unencodableValueMarker, v.Type()), err.Error())
+	}
+
+	// Decode the output again so scrubbing, redaction and truncation apply to it like to any value
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return d.issue(fmt.Sprintf(unencodableValueMarker, v.Type()), err.Error())
+	}
+	return d.encode(reflect.ValueOf(decoded))
+}
+
+// placeholder records an encoding issue and returns the placeholder string replacing the value
+func (d *dataEncoder) placeholder(format string, args ...interface{}) string {
+	marker := fmt.Sprintf(format, args...)
+	d.issues = append(d.issues, marker)
+	return marker
+}
+
+// issue records an encoding issue with its cause, keeping the cause out of the placeholder
+func (d *dataEncoder) issue(marker, cause string) string {
+	d.issues = append(d.issues, marker+": "+scrub(cause))
+	return marker
+}
+
+// encodeField encodes the value of a map entry or struct field, redacting it when its key is sensitive
+func (d *dataEncoder) encodeField(key string, v reflect.Value) interface{} {
+	if isSensitiveKey(key) {
+		return redacted
+	}
+	return d.encode(v)
+}
+
+// encodeString scrubs secrets and truncates long strings, or omits them from client-facing output
+func (d *dataEncoder) encodeString(s string) interface{} {
+	if d.scrubSecrets {
+		s = Scrub(s)
+	}
+	if d.maxStringLen <= 0 || len(s) <= d.maxStringLen {
+		return s
+	}
+	if d.clientFacing {
+		return fmt.Sprintf(omittedStringMarker, len(s))
+	}
+	return s[:d.maxStringLen] + fmt.Sprintf(truncatedStringMarker, len(s)-d.maxStringLen)
+}
+
+// encodeBytes omits raw bytes from client-facing output and truncates them elsewhere
+func (d *dataEncoder) encodeBytes(b []byte) interface{} {
+	if d.clientFacing {
+		return fmt.Sprintf(omittedBytesMarker, len(b))
+	}
+	if d.maxStringLen > 0 && len(b) > d.maxStringLen {
+		return string(b[:d.maxStringLen]) + fmt.Sprintf(truncatedStringMarker, len(b)-d.maxStringLen)
+	}
+	return b
+}
+
+// encodeList encodes the elements of a slice or array, dropping stripped classified elements
+func (d *dataEncoder) encodeList(v reflect.Value) []interface{} {
+	out := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if d.stripped(ClassPublic, v.Index(i)) {
+			continue
+		}
+		out = append(out, d.encode(v.Index(i)))
+	}
+	return out
+}
+
+// allowed reports whether data of the given class may reach this output: clients only get public
+// data and compliance mode strips restricted data everywhere
+func (d *dataEncoder) allowed(class Classification) bool {
+	switch class {
+	case ClassInternal:
+		return !d.clientFacing
+	case ClassRestricted:
+		return !d.clientFacing && !d.compliance
+	default:
+		return true
+	}
+}
+
+// stripped reports whether a field must be left out, given the class of its key and the class
+// of its value when it is a ClassifiedValue
+func (d *dataEncoder) stripped(class Classification, v reflect.Value) bool {
+	if cv, ok := asClassifiedValue(v); ok && cv.Class > class {
+		class = cv.Class
+	}
+	return !d.allowed(class)
+}
+
+// encodeStruct encodes the exported fields of a struct into out, honoring json tags and
+// flattening untagged embedded structs like encoding/json does
+func (d *dataEncoder) encodeStruct(v reflect.Value, out map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				d.encodeStruct(fv, out)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		class := keyClass(name)
+		if tagged := parseClassification(field.Tag.Get("ae")); tagged > class {
+			class = tagged
+		}
+		if d.stripped(class, fv) {
+			continue
+		}
+		out[name] = d.encodeField(name, fv)
+	}
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
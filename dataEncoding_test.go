@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// textSecret renders through encoding.TextMarshaler
+type textSecret string
+
+func (s textSecret) MarshalText() ([]byte, error) {
+	return []byte("auth: Bearer " + string(s)), nil
+}
+
+func TestEncodeDataScrubsMarshalerOutput(t *testing.T) {
+	long := strings.Repeat("x", 64)
+	tests := []struct {
+		name   string
+		maxLen int
+		data   interface{}
+		want   string
+	}{
+		{
+			name: "plain string",
+			data: map[string]interface{}{"note": "Bearer abc.def"},
+			want: `{"note":"Bearer [REDACTED]"}`,
+		},
+		{
+			name: "raw message text",
+			data: map[string]interface{}{"raw": json.RawMessage(`{"note":"Bearer abc.def"}`)},
+			want: `{"raw":{"note":"Bearer [REDACTED]"}}`,
+		},
+		{
+			name: "raw message sensitive key",
+			data: map[string]interface{}{"raw": json.RawMessage(`{"token":"abc","id":7}`)},
+			want: `{"raw":{"id":7,"token":"[REDACTED]"}}`,
+		},
+		{
+			name: "raw message keeps large numbers",
+			data: json.RawMessage(`{"id":9007199254740993}`),
+			want: `{"id":9007199254740993}`,
+		},
+		{
+			name: "text marshaler",
+			data: map[string]interface{}{"auth": textSecret("abc")},
+			want: `{"auth":"auth: Bearer [REDACTED]"}`,
+		},
+		{
+			name:   "raw message truncated",
+			maxLen: 16,
+			data:   json.RawMessage(`{"blob":"` + long + `"}`),
+			want:   `{"blob":"` + long[:16] + `...[truncated 48 bytes]"}`,
+		},
+	}
+
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if tt.maxLen > 0 {
+				cfg.MaxStringLen = tt.maxLen
+			}
+			SetConfig(cfg)
+			raw, err := json.Marshal(encodeData(context.Background(), tt.data, false))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("encodeData = %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeDataFailingMarshaler(t *testing.T) {
+	raw, _ := json.Marshal(encodeData(context.Background(), map[string]interface{}{"raw": json.RawMessage(`{broken`)}, false))
+	if !strings.Contains(string(raw), "[unencodable ") || strings.Contains(string(raw), "broken") {
+		t.Errorf("encodeData = %s, want a placeholder for the broken marshaler", raw)
+	}
+}
+
+func TestEncodeDataSizeLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxString    int
+		maxData      int
+		clientFacing bool
+		data         interface{}
+		want         string
+	}{
+		{"short string", 8, 0, false, map[string]interface{}{"s": "short"}, `{"s":"short"}`},
+		{"long string internally", 4, 0, false, map[string]interface{}{"s": "abcdefgh"}, `{"s":"abcd...[truncated 4 bytes]"}`},
+		{"long string for clients", 4, 0, true, map[string]interface{}{"s": "abcdefgh"}, `{"s":"[string of 8 bytes omitted]"}`},
+		{"bytes internally", 4, 0, false, map[string]interface{}{"b": []byte("abcdefgh")}, `{"b":"abcd...[truncated 4 bytes]"}`},
+		{"bytes for clients", 0, 0, true, map[string]interface{}{"b": []byte("ab")}, `{"b":"[2 bytes omitted]"}`},
+		{"string limit disabled", 0, 0, false, map[string]interface{}{"s": "abcdefgh"}, `{"s":"abcdefgh"}`},
+		{"data within limit", 0, 64, false, map[string]interface{}{"id": 1}, `{"id":1}`},
+		{"data over limit", 0, 16, false, map[string]interface{}{"items": []int{1, 2, 3, 4, 5, 6, 7, 8}}, `{"_limit":16,"_size":27,"_truncated":true}`},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxStringLen = tt.maxString
+			cfg.MaxDataBytes = tt.maxData
+			SetConfig(cfg)
+
+			raw, err := json.Marshal(encodeData(context.Background(), tt.data, tt.clientFacing))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("encodeData = %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+// panicMarshaler panics while marshaling
+type panicMarshaler struct{}
+
+func (panicMarshaler) MarshalJSON() ([]byte, error) {
+	panic("marshal exploded")
+}
+
+// chainNode links to another node, possibly forming a cycle
+type chainNode struct {
+	Name string
+	Next *chainNode
+}
+
+func TestEncodeDataPlaceholders(t *testing.T) {
+	cyclicMap := map[string]interface{}{"id": 1}
+	cyclicMap["self"] = cyclicMap
+	loop := &chainNode{Name: "a"}
+	loop.Next = loop
+	shared := &chainNode{Name: "leaf"}
+
+	tests := []struct {
+		name      string
+		data      interface{}
+		want      string
+		wantTrace string
+	}{
+		{"channel", map[string]interface{}{"c": make(chan int)}, `{"c":"[unsupported chan int]"}`, "error data: [unsupported chan int]"},
+		{"function", map[string]interface{}{"f": func() {}}, `{"f":"[unsupported func()]"}`, "error data: [unsupported func()]"},
+		{"complex", map[string]interface{}{"z": complex(1, 2)}, `{"z":"[unsupported complex128]"}`, "error data: [unsupported complex128]"},
+		{"NaN", map[string]interface{}{"n": math.NaN()}, `{"n":"[unsupported NaN]"}`, "error data: [unsupported NaN]"},
+		{"cyclic map", cyclicMap, `{"id":1,"self":"[cycle map[string]interface {}]"}`, "error data: [cycle map[string]interface {}]"},
+		{"cyclic pointer", loop, `{"Name":"a","Next":"[cycle *errors.chainNode]"}`, "error data: [cycle *errors.chainNode]"},
+		{"shared pointer is not a cycle", []*chainNode{shared, shared}, `[{"Name":"leaf","Next":null},{"Name":"leaf","Next":null}]`, ""},
+		{"panicking marshaler", map[string]interface{}{"p": panicMarshaler{}}, `{"p":"[unencodable errors.panicMarshaler]"}`,
+			"error data: [unencodable errors.panicMarshaler]: marshal exploded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextWithTrace(context.Background())
+			raw, err := json.Marshal(encodeData(ctx, tt.data, false))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("encodeData = %s, want %s", raw, tt.want)
+			}
+			traceMeta, _ := TraceFromContext(ctx)
+			if lines := traceMeta.ErrorLines(); (tt.wantTrace == "" && len(lines) > 0) ||
+				(tt.wantTrace != "" && (len(lines) != 1 || lines[0] != tt.wantTrace)) {
+				t.Errorf("trace = %q, want %q", lines, tt.wantTrace)
+			}
+		})
+	}
+}
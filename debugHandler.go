@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strings"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// defaultDebugSamples is the number of recent recordings exposed by the debug handler
+const defaultDebugSamples = 50
+
+// DebugOption configures the handler returned by DebugHandler
+type DebugOption func(*debugHandler)
+
+// WithDebugAuth authorizes debug requests with a custom check
+func WithDebugAuth(authorize func(r *http.Request) bool) DebugOption {
+	return func(h *debugHandler) {
+		h.authorize = authorize
+	}
+}
+
+// WithDebugToken authorizes debug requests carrying "Authorization: Bearer <token>"
+func WithDebugToken(token string) DebugOption {
+	return func(h *debugHandler) {
+		h.authorize = func(r *http.Request) bool {
+			given, ok := strings.CutPrefix(r.Header.Get(c.HeaderAuthorization), "Bearer ")
+			return ok && token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+		}
+	}
+}
+
+// WithDebugSamples sets how many recent recordings are exposed
+func WithDebugSamples(n int) DebugOption {
+	return func(h *debugHandler) {
+		h.samples = n
+	}
+}
+
+// debugHandler serves the package state for admin endpoints
+type debugHandler struct {
+	authorize func(r *http.Request) bool
+	samples   int
+}
+
+// DebugHandler returns an http.Handler exposing the registered catalog, live per-code counters,
+// recent recorded errors and the active configuration as JSON. The last path segment selects a
+// view (catalog, ranges, endpoints, stats, samples, config); any other path returns all of them.
+// Requests are rejected unless an authorization option is given.
+func DebugHandler(opts ...DebugOption) http.Handler {
+	h := &debugHandler{samples: defaultDebugSamples}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *debugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.authorize == nil || !h.authorize(r) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set(c.HeaderAllow, "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body interface{}
+	switch path.Base(r.URL.Path) {
+	case "catalog":
+		body = Catalog()
+	case "ranges":
+		body = RangeReport()
+	case "endpoints":
+		body = EndpointReports()
+	case "stats":
+		body = Stats()
+	case "samples":
+		body = h.recentSamples()
+	case "config":
+		body = GetConfig()
+	default:
+		body = map[string]interface{}{
+			"catalog":   Catalog(),
+			"ranges":    RangeReport(),
+			"endpoints": EndpointReports(),
+			"stats":     Stats(),
+			"samples":   h.recentSamples(),
+			"config":    GetConfig(),
+		}
+	}
+
+	w.Header().Set(c.HeaderContentType, c.ContentTypeJSON)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(body)
+}
+
+// recentSamples returns the most recent recordings, or none when no recorder is installed
+func (h *debugHandler) recentSamples() []*Recording {
+	rec := GetRecorder()
+	if rec == nil {
+		return []*Recording{}
+	}
+	return rec.Recent(h.samples)
+}
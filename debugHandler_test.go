@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestDebugHandlerAccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.Handler
+		method    string
+		auth      string
+		wantCode  int
+		wantAllow string
+	}{
+		{"no authorization option", DebugHandler(), http.MethodGet, "Bearer t0k", http.StatusForbidden, ""},
+		{"missing token", DebugHandler(WithDebugToken("t0k")), http.MethodGet, "", http.StatusForbidden, ""},
+		{"wrong token", DebugHandler(WithDebugToken("t0k")), http.MethodGet, "Bearer nope", http.StatusForbidden, ""},
+		{"not a bearer token", DebugHandler(WithDebugToken("t0k")), http.MethodGet, "Basic t0k", http.StatusForbidden, ""},
+		{"valid token", DebugHandler(WithDebugToken("t0k")), http.MethodGet, "Bearer t0k", http.StatusOK, ""},
+		{"method not allowed", DebugHandler(WithDebugToken("t0k")), http.MethodPost, "Bearer t0k", http.StatusMethodNotAllowed, "GET, HEAD"},
+		{"catalog", CatalogHandler(), http.MethodGet, "", http.StatusOK, ""},
+		{"catalog method not allowed", CatalogHandler(), http.MethodDelete, "", http.StatusMethodNotAllowed, "GET, HEAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/debug/errors/config", nil)
+			if tt.auth != "" {
+				req.Header.Set(c.HeaderAuthorization, tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get(c.HeaderAllow); got != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.wantCode == http.StatusOK && rec.Header().Get(c.HeaderContentType) != c.ContentTypeJSON {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get(c.HeaderContentType), c.ContentTypeJSON)
+			}
+		})
+	}
+}
+
+func TestDebugHandlerViews(t *testing.T) {
+	rec := NewRecorder(8)
+	for i := 0; i < 3; i++ {
+		rec.Record(context.Background(), GetAppErr(context.Background(), errors.New("boom"), nil, 0))
+	}
+	SetRecorder(rec)
+	defer SetRecorder(nil)
+
+	tests := []struct {
+		path     string
+		samples  int
+		wantKeys []string
+		wantLen  int
+	}{
+		{"/debug/errors", 0, []string{"catalog", "ranges", "endpoints", "stats", "samples", "config"}, -1},
+		{"/debug/errors/samples", 2, nil, 2},
+		{"/debug/errors/samples", 0, nil, 3},
+		{"/debug/errors/catalog", 0, nil, len(Catalog())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			opts := []DebugOption{WithDebugAuth(func(*http.Request) bool { return true })}
+			if tt.samples > 0 {
+				opts = append(opts, WithDebugSamples(tt.samples))
+			}
+			resp := httptest.NewRecorder()
+			DebugHandler(opts...).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if tt.wantLen >= 0 {
+				var items []json.RawMessage
+				if err := json.Unmarshal(resp.Body.Bytes(), &items); err != nil || len(items) != tt.wantLen {
+					t.Errorf("view holds %d items (%v), want %d", len(items), err, tt.wantLen)
+				}
+				return
+			}
+			var views map[string]json.RawMessage
+			if err := json.Unmarshal(resp.Body.Bytes(), &views); err != nil {
+				t.Fatalf("decoding views: %v", err)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := views[key]; !ok {
+					t.Errorf("missing view %q", key)
+				}
+			}
+		})
+	}
+}
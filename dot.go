@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotMaxLabel bounds the length of plain error texts shown as graph nodes
+const dotMaxLabel = 80
+
+// ToDOT renders the cause structure of the AppError as a Graphviz digraph: AppErrors are nodes
+// labeled with their code and HTTP status, plain errors are labeled with their text, and edges
+// point from an error to its causes, labeled with the WrapMsg annotations ("cause" when none) or
+// "join" for each member of a joined error
+func (e *AppError) ToDOT() string {
+	g := &dotGraph{ids: map[error]string{}}
+	g.b.WriteString("digraph errors {\n\tnode [shape=box, fontname=\"monospace\"];\n")
+	g.node(e)
+	g.b.WriteString("}\n")
+	return g.b.String()
+}
+
+// dotGraph accumulates the nodes and edges of an error graph
+type dotGraph struct {
+	b   strings.Builder
+	ids map[error]string // Node IDs of comparable errors already written
+}
+
+// node writes err and, recursively, its causes, returning the node ID
+func (g *dotGraph) node(err error) string {
+	if id, ok := g.lookup(err); ok {
+		return id
+	}
+	id := fmt.Sprintf("n%d", len(g.ids))
+	g.remember(err, id)
+
+	appErr, isAppErr := err.(*AppError)
+	if !isAppErr || appErr == nil {
+		fmt.Fprintf(&g.b, "\t%s [label=%s, style=dashed];\n", id, dotQuote(truncateLabel(scrub(err.Error()))))
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, member := range u.Unwrap() {
+				if member != nil {
+					g.edge(id, g.node(member), "join")
+				}
+			}
+		case interface{ Unwrap() error }:
+			if cause := u.Unwrap(); cause != nil {
+				g.edge(id, g.node(cause), "cause")
+			}
+		}
+		return id
+	}
+
+	label := fmt.Sprintf("%s\n%d", primaryCode(appErr), appErr.httpCode)
+	if len(appErr.ErrorCodes) > 1 {
+		label += "\n" + strings.Join(appErr.ErrorCodes, " > ")
+	}
+	fmt.Fprintf(&g.b, "\t%s [label=%s];\n", id, dotQuote(label))
+
+	if appErr.ActualErr != nil {
+		op := "cause"
+		if len(appErr.contexts) > 0 {
+			ops := make([]string, len(appErr.contexts))
+			for i, annotation := range appErr.contexts {
+				ops[len(ops)-1-i] = annotation
+			}
+			op = strings.Join(ops, "\n")
+		}
+		g.edge(id, g.node(appErr.ActualErr), op)
+	}
+	return id
+}
+
+// edge writes a labeled edge
+func (g *dotGraph) edge(from, to, label string) {
+	fmt.Fprintf(&g.b, "\t%s -> %s [label=%s];\n", from, to, dotQuote(scrub(label)))
+}
+
+// lookup returns the node ID of an error already written; errors of uncomparable types are never
+// shared
+func (g *dotGraph) lookup(err error) (id string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			id, ok = "", false
+		}
+	}()
+	id, ok = g.ids[err]
+	return id, ok
+}
+
+// remember stores the node ID of an error, keyed by a placeholder when it is not comparable
+func (g *dotGraph) remember(err error, id string) {
+	defer func() {
+		if recover() != nil {
+			g.ids[dotUncomparable(id)] = id
+		}
+	}()
+	g.ids[err] = id
+}
+
+// dotUncomparable stands in for errors that cannot be used as map keys
+type dotUncomparable string
+
+// Error implements error
+func (u dotUncomparable) Error() string {
+	return string(u)
+}
+
+// truncateLabel shortens long error texts
+func truncateLabel(s string) string {
+	if len(s) > dotMaxLabel {
+		return s[:dotMaxLabel] + "..."
+	}
+	return s
+}
+
+// dotQuote renders a DOT string literal
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
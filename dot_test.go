@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	ctx := context.Background()
+	root := errors.New("connection refused")
+	notFound := GetCustomErr("ERR_DOT_1", "not found", false)
+	upstream := GetCustomErr("ERR_DOT_2", "upstream failed", true)
+
+	tests := []struct {
+		name string
+		err  *AppError
+		want string
+	}{
+		{"plain cause", GetAppErr(ctx, root, notFound, 404),
+			`	n0 [label="ERR_DOT_1\n404"];
+	n1 [label="connection refused", style=dashed];
+	n0 -> n1 [label="cause"];
+`},
+		{"annotations", GetAppErr(ctx, root, notFound, 404).WrapMsg("loading order").WrapMsg("handling request"),
+			`	n0 [label="ERR_DOT_1\n404"];
+	n1 [label="connection refused", style=dashed];
+	n0 -> n1 [label="handling request\nloading order"];
+`},
+		{"wrapped chain", Wrap(ctx, GetAppErr(ctx, fmt.Errorf("dial: %w", root), notFound, 404), upstream, 502),
+			`	n0 [label="ERR_DOT_2\n502\nERR_DOT_1 > ERR_DOT_2"];
+	n1 [label="dial: connection refused", style=dashed];
+	n2 [label="connection refused", style=dashed];
+	n1 -> n2 [label="cause"];
+	n0 -> n1 [label="cause"];
+`},
+		{"joined members share causes", GetAppErr(ctx, errors.Join(root, GetAppErr(ctx, root, notFound, 404)), upstream, 502),
+			`	n0 [label="ERR_DOT_2\n502"];
+	n1 [label="connection refused\nconnection refused", style=dashed];
+	n2 [label="connection refused", style=dashed];
+	n1 -> n2 [label="join"];
+	n3 [label="ERR_DOT_1\n404"];
+	n3 -> n2 [label="cause"];
+	n1 -> n3 [label="join"];
+	n0 -> n1 [label="cause"];
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "digraph errors {\n\tnode [shape=box, fontname=\"monospace\"];\n" + tt.want + "}\n"
+			if got := tt.err.ToDOT(); got != want {
+				t.Errorf("ToDOT =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package echomw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Trace seeds a TraceMeta into every request context like ae.TraceMiddleware, including the
+// X-Request-ID handling
+func Trace() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err error
+			ae.TraceMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				err = next(c)
+			})).ServeHTTP(c.Response(), c.Request())
+			return err
+		}
+	}
+}
+
+// Errors converts the errors returned by later handlers into AppErrors, so middleware further out
+// (logging, metrics) sees the same error the client will get; echo.HTTPError values are converted
+// with FromHTTPError
+func Errors() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err == nil {
+				return nil
+			}
+			return ToAppError(c.Request().Context(), err)
+		}
+	}
+}
+
+// HTTPErrorHandler is an echo.HTTPErrorHandler writing the structured JSON response of an AppError
+// with its HTTP code; echo.HTTPError values are converted with FromHTTPError and any other error
+// becomes a 500 Internal error. Install it with e.HTTPErrorHandler = echomw.HTTPErrorHandler
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	appErr := ToAppError(c.Request().Context(), err)
+	if c.Request().Method == http.MethodHead {
+		status := appErr.GetHTTPCode()
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		_ = c.NoContent(status)
+		return
+	}
+	appErr.WriteHTTP(c.Response(), c.Request())
+}
+
+// ToAppError converts err into an AppError: AppErrors in the chain are returned as they are,
+// echo.HTTPError values are converted with FromHTTPError and other errors become 500 Internal errors
+func ToAppError(ctx context.Context, err error) *ae.AppError {
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return FromHTTPError(ctx, httpErr)
+	}
+	return ae.GetAppErr(ctx, err, ae.InternalError, http.StatusInternalServerError)
+}
+
+// FromHTTPError wraps an echo.HTTPError into an AppError with the same status and a code derived
+// from it (see ae.CustomErrForStatus); string messages set by handlers are kept as the client-facing
+// message. An AppError set as the internal error takes precedence
+func FromHTTPError(ctx context.Context, httpErr *echo.HTTPError) *ae.AppError {
+	var appErr *ae.AppError
+	if errors.As(httpErr.Internal, &appErr) {
+		return appErr
+	}
+
+	customErr := *ae.CustomErrForStatus(httpErr.Code)
+	if msg, ok := httpErr.Message.(string); ok && msg != "" {
+		customErr.Message = msg
+	}
+	return ae.New(ctx, httpErr,
+		ae.WithCustomErr(&customErr),
+		ae.WithHTTPCode(httpErr.Code),
+		ae.WithStackSkip(1))
+}
@@ -0,0 +1,96 @@
+package echomw
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestHTTPErrorHandler(t *testing.T) {
+	notFound := ae.GetAppErr(context.Background(), errors.New("no rows"), ae.GetCustomErr("ERR_ECH_1", "order not found", false), 404)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		err        error
+		wantStatus int
+		wantCode   string // Code of the JSON error body, empty when no body is expected
+		wantMsg    string
+	}{
+		{"app error", http.MethodGet, "/", notFound, http.StatusNotFound, "ERR_ECH_1", "order not found"},
+		{"http error with message", http.MethodGet, "/", echo.NewHTTPError(http.StatusBadRequest, "bad order id"),
+			http.StatusBadRequest, ae.CustomErrForStatus(http.StatusBadRequest).Code, "bad order id"},
+		{"http error with internal app error", http.MethodGet, "/", echo.NewHTTPError(http.StatusBadRequest).SetInternal(notFound),
+			http.StatusNotFound, "ERR_ECH_1", "order not found"},
+		{"unknown route", http.MethodGet, "/missing", nil,
+			http.StatusNotFound, ae.CustomErrForStatus(http.StatusNotFound).Code, http.StatusText(http.StatusNotFound)},
+		{"plain error", http.MethodGet, "/", errors.New("pq: password authentication failed"),
+			http.StatusInternalServerError, ae.InternalError.Code, ae.InternalError.Message},
+		{"head request", http.MethodHead, "/", notFound, http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var traced bool
+			e := echo.New()
+			e.HTTPErrorHandler = HTTPErrorHandler
+			e.Use(Trace(), Errors())
+			handler := func(ctx echo.Context) error {
+				_, traced = ae.TraceFromContext(ctx.Request().Context())
+				return tt.err
+			}
+			e.GET("/", handler)
+			e.HEAD("/", handler)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.path == "/" && !traced {
+				t.Error("handler ran without a TraceMeta")
+			}
+			if tt.wantCode == "" {
+				if rec.Body.Len() != 0 {
+					t.Errorf("body = %q, want none", rec.Body.String())
+				}
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+			}
+			if body["code"] != tt.wantCode || body["message"] != tt.wantMsg || rec.Header().Get(c.HeaderContentType) != c.ContentTypeJSON {
+				t.Errorf("body = %v, want code %s and message %q", body, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestErrorsMiddleware(t *testing.T) {
+	e := echo.New()
+	var seen error
+	outer := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			seen = next(ctx)
+			return seen
+		}
+	}
+	e.Use(outer, Errors())
+	e.GET("/", func(echo.Context) error { return echo.NewHTTPError(http.StatusConflict, "duplicate") })
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var appErr *ae.AppError
+	if !errors.As(seen, &appErr) || appErr.GetHTTPCode() != http.StatusConflict || appErr.GetMsg() != "duplicate" {
+		t.Errorf("outer middleware saw %v, want the converted AppError", seen)
+	}
+}
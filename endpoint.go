@@ -0,0 +1,234 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ErrUndeclaredCode is returned by EndpointSpec.Check for codes the route never declared
+var ErrUndeclaredCode = errors.New("undeclared error code")
+
+// EndpointSpec documents the custom errors a route may return and tracks the codes it actually
+// returned. Declarations are documentation only: nothing stops a handler from returning another
+// code, unless the spec is Strict and the route is served through its Middleware
+type EndpointSpec struct {
+	route    string
+	mu       sync.RWMutex
+	declared map[string]*CustomErr // Documented errors keyed by code
+	observed map[string]int        // Codes seen at runtime with their counts
+	strict   bool                  // Log undeclared codes written for the route
+}
+
+// EndpointReport summarizes the documented and observed codes of a route
+type EndpointReport struct {
+	Route        string         `json:"route"`
+	Declared     []string       `json:"declared"`
+	Observed     map[string]int `json:"observed"`
+	Undocumented []string       `json:"undocumented"`
+}
+
+// endpoints holds every route registered through Endpoint
+var endpoints = struct {
+	sync.RWMutex
+	specs map[string]*EndpointSpec
+}{specs: map[string]*EndpointSpec{}}
+
+// Endpoint returns the spec for a route such as "POST /users", creating it on first use
+func Endpoint(route string) *EndpointSpec {
+	endpoints.RLock()
+	spec, ok := endpoints.specs[route]
+	endpoints.RUnlock()
+	if ok {
+		return spec
+	}
+
+	endpoints.Lock()
+	defer endpoints.Unlock()
+	if spec, ok = endpoints.specs[route]; ok {
+		return spec
+	}
+	spec = &EndpointSpec{
+		route:    route,
+		declared: map[string]*CustomErr{},
+		observed: map[string]int{},
+	}
+	endpoints.specs[route] = spec
+	return spec
+}
+
+// Route returns the route the spec documents
+func (s *EndpointSpec) Route() string {
+	return s.route
+}
+
+// MayReturn documents custom errors the route can return and returns the spec
+func (s *EndpointSpec) MayReturn(customErrs ...*CustomErr) *EndpointSpec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, customErr := range customErrs {
+		if customErr != nil && customErr.Code != "" {
+			s.declared[customErr.Code] = customErr
+		}
+	}
+	return s
+}
+
+// Strict logs a warning whenever WriteHTTP or WriteProblem writes a code the route never declared,
+// for requests served through Middleware, and returns the spec
+func (s *EndpointSpec) Strict() *EndpointSpec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strict = true
+	return s
+}
+
+// Middleware stores the spec in the request context, so the AppErrors WriteHTTP and WriteProblem
+// write for the route are observed without calling Observe, and checked when the spec is Strict
+func (s *EndpointSpec) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), endpointCtxKey, s)))
+	})
+}
+
+// EndpointFromContext returns the spec stored by EndpointSpec.Middleware
+func EndpointFromContext(ctx context.Context) (*EndpointSpec, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	s, ok := ctx.Value(endpointCtxKey).(*EndpointSpec)
+	return s, ok
+}
+
+// Check returns ErrUndeclaredCode when err is an AppError whose primary code the route never
+// declared, e.g. to fail tests; other errors return nil
+func (s *EndpointSpec) Check(err error) error {
+	appErr, ok := asAppError(err)
+	if !ok || appErr.CustomErr == nil || appErr.CustomErr.Code == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	_, declared := s.declared[appErr.CustomErr.Code]
+	s.mu.RUnlock()
+	if declared {
+		return nil
+	}
+	return fmt.Errorf("%w: %s returned %s", ErrUndeclaredCode, s.route, appErr.CustomErr.Code)
+}
+
+// observeResponse observes an AppError written for a request served through Middleware, logging
+// undeclared codes when the spec is Strict
+func observeResponse(ctx context.Context, appErr *AppError) {
+	s, ok := EndpointFromContext(ctx)
+	if !ok {
+		return
+	}
+	s.Observe(appErr)
+
+	s.mu.RLock()
+	strict := s.strict
+	s.mu.RUnlock()
+	if !strict {
+		return
+	}
+	if s.Check(appErr) != nil {
+		getLogger().WarnContext(ctx, "endpoint returned an undeclared error code", "route", s.route, "code", appErr.CustomErr.Code)
+	}
+}
+
+// Declared returns the documented codes of the route, sorted
+func (s *EndpointSpec) Declared() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	codes := make([]string, 0, len(s.declared))
+	for code := range s.declared {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Observe records the primary code of an AppError returned by the route; other errors are ignored
+func (s *EndpointSpec) Observe(err error) {
+	appErr, ok := asAppError(err)
+	if !ok || appErr.CustomErr == nil || appErr.CustomErr.Code == "" {
+		return
+	}
+
+	s.mu.Lock()
+	s.observed[appErr.CustomErr.Code]++
+	s.mu.Unlock()
+}
+
+// Report summarizes the route's documented, observed and undocumented codes
+func (s *EndpointSpec) Report() EndpointReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report := EndpointReport{
+		Route:        s.route,
+		Declared:     make([]string, 0, len(s.declared)),
+		Observed:     make(map[string]int, len(s.observed)),
+		Undocumented: []string{},
+	}
+	for code := range s.declared {
+		report.Declared = append(report.Declared, code)
+	}
+	for code, count := range s.observed {
+		report.Observed[code] = count
+		if _, documented := s.declared[code]; !documented {
+			report.Undocumented = append(report.Undocumented, code)
+		}
+	}
+	sort.Strings(report.Declared)
+	sort.Strings(report.Undocumented)
+	return report
+}
+
+// ObserveEndpoint records an error returned by the given route
+func ObserveEndpoint(route string, err error) {
+	Endpoint(route).Observe(err)
+}
+
+// EndpointReports returns the reports of every registered route, ordered by route
+func EndpointReports() []EndpointReport {
+	endpoints.RLock()
+	specs := make([]*EndpointSpec, 0, len(endpoints.specs))
+	for _, spec := range endpoints.specs {
+		specs = append(specs, spec)
+	}
+	endpoints.RUnlock()
+
+	sort.Slice(specs, func(i, j int) bool { return specs[i].route < specs[j].route })
+	reports := make([]EndpointReport, 0, len(specs))
+	for _, spec := range specs {
+		reports = append(reports, spec.Report())
+	}
+	return reports
+}
+
+// UndocumentedCodes returns, per route, the codes observed at runtime that were never declared
+func UndocumentedCodes() map[string][]string {
+	undocumented := map[string][]string{}
+	for _, report := range EndpointReports() {
+		if len(report.Undocumented) > 0 {
+			undocumented[report.Route] = report.Undocumented
+		}
+	}
+	return undocumented
+}
+
+// ExportEndpoints writes the reports of every registered route as JSON
+func ExportEndpoints(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(EndpointReports())
+}
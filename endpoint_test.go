@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEndpointMiddleware(t *testing.T) {
+	declared := GetCustomErr("ERR_EP_1001", "order not found", false)
+	undeclared := GetCustomErr("ERR_EP_1002", "order locked", false)
+
+	tests := []struct {
+		name          string
+		strict        bool
+		returned      []error
+		wantObserved  map[string]int
+		wantUndoc     []string
+		wantLogged    bool
+		wantCheckFail bool
+	}{
+		{
+			name:         "declared code",
+			returned:     []error{GetAppErr(context.Background(), errors.New("missing"), declared, http.StatusNotFound)},
+			wantObserved: map[string]int{"ERR_EP_1001": 1},
+			wantUndoc:    []string{},
+		},
+		{
+			name:          "undeclared code",
+			returned:      []error{GetAppErr(context.Background(), errors.New("locked"), undeclared, http.StatusLocked)},
+			wantObserved:  map[string]int{"ERR_EP_1002": 1},
+			wantUndoc:     []string{"ERR_EP_1002"},
+			wantCheckFail: true,
+		},
+		{
+			name:          "undeclared code in strict mode",
+			strict:        true,
+			returned:      []error{GetAppErr(context.Background(), errors.New("locked"), undeclared, http.StatusLocked)},
+			wantObserved:  map[string]int{"ERR_EP_1002": 1},
+			wantUndoc:     []string{"ERR_EP_1002"},
+			wantLogged:    true,
+			wantCheckFail: true,
+		},
+		{
+			name:         "plain errors are written as internal errors",
+			returned:     []error{errors.New("boom"), errors.New("boom")},
+			wantObserved: map[string]int{InternalError.Code: 2},
+			wantUndoc:    []string{InternalError.Code},
+		},
+	}
+	var logs bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer SetLogger(nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			spec := Endpoint("GET /orders/" + tt.name).MayReturn(declared)
+			if tt.strict {
+				spec.Strict()
+			}
+			var returned error
+			handler := spec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WriteError(w, r, returned)
+			}))
+			for _, returned = range tt.returned {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}
+
+			report := spec.Report()
+			if !reflect.DeepEqual(report.Observed, tt.wantObserved) || !reflect.DeepEqual(report.Undocumented, tt.wantUndoc) {
+				t.Errorf("report observed %v undocumented %v, want %v %v",
+					report.Observed, report.Undocumented, tt.wantObserved, tt.wantUndoc)
+			}
+			if logged := strings.Contains(logs.String(), "undeclared error code"); logged != tt.wantLogged {
+				t.Errorf("logged = %v, want %v: %s", logged, tt.wantLogged, logs.String())
+			}
+			if err := spec.Check(tt.returned[0]); (err != nil) != tt.wantCheckFail || (err != nil && !errors.Is(err, ErrUndeclaredCode)) {
+				t.Errorf("Check = %v, want failure %v", err, tt.wantCheckFail)
+			}
+		})
+	}
+}
+
+func TestEndpointDeclarations(t *testing.T) {
+	spec := Endpoint("POST /endpoint-declarations").
+		MayReturn(GetCustomErr("ERR_EP_2002", "b", false), nil, &CustomErr{}, GetCustomErr("ERR_EP_2001", "a", false))
+	if got, want := spec.Declared(), []string{"ERR_EP_2001", "ERR_EP_2002"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Declared() = %v, want %v", got, want)
+	}
+	if Endpoint("POST /endpoint-declarations") != spec {
+		t.Error("Endpoint returned a new spec for a known route")
+	}
+
+	ObserveEndpoint("POST /endpoint-declarations", errors.New("not an AppError"))
+	if observed := spec.Report().Observed; len(observed) != 0 {
+		t.Errorf("plain errors were observed: %v", observed)
+	}
+}
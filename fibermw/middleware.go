@@ -0,0 +1,117 @@
+package fibermw
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Trace seeds a TraceMeta into the UserContext of every request like ae.TraceMiddleware, including
+// the X-Request-ID handling; handlers pass c.UserContext() to GetAppErr and AddTraceLog
+func Trace() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		r, err := request(c)
+		if err != nil {
+			return err
+		}
+
+		w := &responseWriter{c: c, header: http.Header{}}
+		ae.TraceMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			w.flushHeader()
+			c.SetUserContext(r.Context())
+			err = c.Next()
+		})).ServeHTTP(w, r)
+		return err
+	}
+}
+
+// ErrorHandler is a fiber.ErrorHandler writing the structured JSON response of an AppError with its
+// HTTP code; fiber.Error values are converted with FromFiberError and any other error becomes a
+// 500 Internal error. Install it with fiber.Config{ErrorHandler: fibermw.ErrorHandler}
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	appErr := ToAppError(c.UserContext(), err)
+
+	// WriteHTTP accepts a nil request, which only skips recording the response
+	r, _ := request(c)
+	c.Response().ResetBody()
+	appErr.WriteHTTP(&responseWriter{c: c, header: http.Header{}}, r)
+	return nil
+}
+
+// ToAppError converts err into an AppError: AppErrors in the chain are returned as they are,
+// fiber.Error values are converted with FromFiberError and other errors become 500 Internal errors
+func ToAppError(ctx context.Context, err error) *ae.AppError {
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return FromFiberError(ctx, fiberErr)
+	}
+	return ae.GetAppErr(ctx, err, ae.InternalError, http.StatusInternalServerError)
+}
+
+// FromFiberError wraps a fiber.Error into an AppError with the same status and a code derived from
+// it (see ae.CustomErrForStatus); the error's message is kept as the client-facing message
+func FromFiberError(ctx context.Context, fiberErr *fiber.Error) *ae.AppError {
+	customErr := *ae.CustomErrForStatus(fiberErr.Code)
+	if fiberErr.Message != "" {
+		customErr.Message = fiberErr.Message
+	}
+	return ae.New(ctx, fiberErr,
+		ae.WithCustomErr(&customErr),
+		ae.WithHTTPCode(fiberErr.Code),
+		ae.WithStackSkip(1))
+}
+
+// request converts the fiber request into a net/http request carrying the UserContext
+func request(c *fiber.Ctx) (*http.Request, error) {
+	r, err := adaptor.ConvertRequest(c, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.WithContext(c.UserContext()), nil
+}
+
+// responseWriter adapts a fiber response to http.ResponseWriter for the net/http based helpers
+type responseWriter struct {
+	c           *fiber.Ctx
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.flushHeader()
+	w.c.Status(status)
+	w.wroteHeader = true
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.c.Response().AppendBody(b)
+	return len(b), nil
+}
+
+// flushHeader copies the headers set so far into the fiber response
+func (w *responseWriter) flushHeader() {
+	for key, values := range w.header {
+		w.c.Response().Header.Del(key)
+		for _, value := range values {
+			w.c.Response().Header.Add(key, value)
+		}
+	}
+}
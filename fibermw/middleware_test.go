@@ -0,0 +1,80 @@
+package fibermw
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestErrorHandler(t *testing.T) {
+	notFound := ae.GetAppErr(context.Background(), errors.New("no rows"), ae.GetCustomErr("ERR_FBR_1", "order not found", true), 404).
+		SetRetryAfter(2 * time.Second)
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+		wantHeader http.Header
+	}{
+		{"app error", "/", notFound, http.StatusNotFound, "ERR_FBR_1", "order not found", http.Header{c.HeaderRetryAfter: {"2"}}},
+		{"fiber error", "/", fiber.NewError(http.StatusConflict, "duplicate order"),
+			http.StatusConflict, ae.CustomErrForStatus(http.StatusConflict).Code, "duplicate order", nil},
+		{"unknown route", "/missing", nil,
+			http.StatusNotFound, ae.CustomErrForStatus(http.StatusNotFound).Code, "Cannot GET /missing", nil},
+		{"plain error", "/", errors.New("pq: password authentication failed"),
+			http.StatusInternalServerError, ae.InternalError.Code, ae.InternalError.Message, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var traced bool
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Use(Trace())
+			app.Get("/", func(ctx *fiber.Ctx) error {
+				_, traced = ae.TraceFromContext(ctx.UserContext())
+				return tt.err
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			defer resp.Body.Close()
+			raw, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.path == "/" && !traced {
+				t.Error("handler ran without a TraceMeta")
+			}
+			if resp.Header.Get(c.HeaderRequestID) == "" {
+				t.Error("response lacks the request ID header")
+			}
+			for key, values := range tt.wantHeader {
+				if got := resp.Header.Get(key); got != values[0] {
+					t.Errorf("header %s = %q, want %q", key, got, values[0])
+				}
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(raw, &body); err != nil {
+				t.Fatalf("decoding %q: %v", raw, err)
+			}
+			if body["code"] != tt.wantCode || body["message"] != tt.wantMsg {
+				t.Errorf("body = %v, want code %s and message %q", body, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
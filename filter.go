@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorFilter selects the AppErrors a notifier or alerting sink acts on
+type ErrorFilter func(appErr *AppError) bool
+
+// MinStatus selects AppErrors whose HTTP code is at least status; an unset code counts as 500
+func MinStatus(status int) ErrorFilter {
+	return func(appErr *AppError) bool {
+		code := appErr.httpCode
+		if code == 0 {
+			code = http.StatusInternalServerError
+		}
+		return code >= status
+	}
+}
+
+// MinSeverity selects AppErrors at least as serious as severity, e.g. MinSeverity(SeverityCritical)
+// to alert only on critical errors; an unset severity counts as SeverityError
+func MinSeverity(severity Severity) ErrorFilter {
+	return func(appErr *AppError) bool {
+		return appErr.GetSeverity() >= severity.orDefault()
+	}
+}
+
+// CodePrefix selects AppErrors whose primary code starts with one of the prefixes, e.g. a code
+// family such as "ERR_PAYMENT_"
+func CodePrefix(prefixes ...string) ErrorFilter {
+	return func(appErr *AppError) bool {
+		code := primaryCode(appErr)
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(code, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AllOf selects AppErrors matched by every filter
+func AllOf(filters ...ErrorFilter) ErrorFilter {
+	return func(appErr *AppError) bool {
+		for _, filter := range filters {
+			if !filter(appErr) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf selects AppErrors matched by at least one filter
+func AnyOf(filters ...ErrorFilter) ErrorFilter {
+	return func(appErr *AppError) bool {
+		for _, filter := range filters {
+			if filter(appErr) {
+				return true
+			}
+		}
+		return false
+	}
+}
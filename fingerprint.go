@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// fingerprintFrames is the number of innermost stack frames contributing to a fingerprint
+const fingerprintFrames = 3
+
+// Fingerprint returns a stable hash identifying the kind of failure, built from the code chain,
+// the type of the root cause and the functions of the innermost stack frames. Line numbers and
+// error text are left out so the fingerprint survives unrelated edits and volatile messages. An
+// override set with SetFingerprint is returned as is
+func (e *AppError) Fingerprint() string {
+	if e.fingerprint != "" {
+		return e.fingerprint
+	}
+
+	parts := append([]string{}, e.ErrorCodes...)
+	parts = append(parts, rootCauseType(e.ActualErr))
+	frames := e.stack
+	if len(frames) > fingerprintFrames {
+		frames = frames[:fingerprintFrames]
+	}
+	for _, frame := range resolveFrames(frames) {
+		parts = append(parts, frame.Function)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SetFingerprint overrides the computed fingerprint, e.g. to group failures that surface at
+// different call sites, and returns the AppError; an empty fingerprint restores the computed one
+func (e *AppError) SetFingerprint(fingerprint string) *AppError {
+	e.fingerprint = fingerprint
+	return e
+}
+
+// GroupByFingerprint groups occurrences by their Fingerprint
+var GroupByFingerprint GroupingStrategy = GroupingFunc(func(appErr *AppError) string {
+	return appErr.Fingerprint()
+})
+
+// rootCauseType returns the type of the innermost error of the chain, looking through AppErrors
+// and fmt.Errorf wrappers; joined errors end the walk
+func rootCauseType(err error) string {
+	if err == nil {
+		return ""
+	}
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = next
+	}
+}
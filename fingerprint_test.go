@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+)
+
+// fingerprintedErr creates an AppError from a single call site
+func fingerprintedErr(err error, customErr *CustomErr) *AppError {
+	return GetAppErr(context.Background(), err, customErr, 500)
+}
+
+func TestFingerprint(t *testing.T) {
+	notFound := GetCustomErr("ERR_FPR_1", "order not found", false)
+	conflict := GetCustomErr("ERR_FPR_2", "order exists", false)
+	base := fingerprintedErr(errors.New("no rows for o1"), notFound)
+
+	tests := []struct {
+		name string
+		err  *AppError
+		same bool
+	}{
+		{"different message", fingerprintedErr(errors.New("no rows for o2"), notFound), true},
+		{"different code", fingerprintedErr(errors.New("no rows for o1"), conflict), false},
+		{"different root cause type", fingerprintedErr(context.DeadlineExceeded, notFound), false},
+		{"same root cause type wrapped", fingerprintedErr(fmt.Errorf("query: %w", errors.New("no rows")), notFound), true},
+		{"different call site", GetAppErr(context.Background(), errors.New("no rows for o1"), notFound, 500), false},
+		{"wrapped chain", Wrap(context.Background(), base, conflict, 500), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Fingerprint() == base.Fingerprint(); got != tt.same {
+				t.Errorf("Fingerprint %s vs %s: same = %v, want %v", tt.err.Fingerprint(), base.Fingerprint(), got, tt.same)
+			}
+			if len(tt.err.Fingerprint()) != 16 {
+				t.Errorf("Fingerprint %q, want 16 hex digits", tt.err.Fingerprint())
+			}
+		})
+	}
+}
+
+func TestSetFingerprint(t *testing.T) {
+	appErr := fingerprintedErr(errors.New("boom"), GetCustomErr("ERR_FPR_3", "failed", false))
+	computed := appErr.Fingerprint()
+
+	tests := []struct {
+		name     string
+		override string
+		want     string
+	}{
+		{"override", "checkout-timeouts", "checkout-timeouts"},
+		{"restored", "", computed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appErr.SetFingerprint(tt.override).Fingerprint(); got != tt.want {
+				t.Errorf("Fingerprint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCauseType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), "*errors.errorString"},
+		{"wrapped", fmt.Errorf("a: %w", fmt.Errorf("b: %w", fs.ErrNotExist)), "*errors.errorString"},
+		{"path error", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}, "*errors.errorString"},
+		{"joined", errors.Join(errors.New("a"), errors.New("b")), "*errors.joinError"},
+		{"unwrapping AppError", GetAppErr(context.Background(), &fs.PathError{Op: "open", Path: "x"}, nil, 500), "*fs.PathError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rootCauseType(tt.err); got != tt.want {
+				t.Errorf("rootCauseType = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format implements fmt.Formatter. %v and %s print the terse error text and %q quotes it, while
+// %+v prints a multi-line report with the code, message, HTTP code, WrapMsg contexts, code chain,
+// data, stack and the sites that wrapped the error:
+//
+//	ERR_DB_1001: database is not reachable (http 503)
+//	error: loading order: ping: connection refused
+//	contexts:
+//		loading order
+//	codes: ERR_DB_1001 > ERR_ORDER_2001
+//	data: {"db":"orders"}
+//	stack:
+//		main.loadOrder
+//			/app/order.go:42
+//	wrapped at:
+//		main.placeOrder
+//			/app/order.go:87
+func (e *AppError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.verboseString())
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+// verboseString renders the %+v report
+func (e *AppError) verboseString() string {
+	var b strings.Builder
+
+	code, msg := "", ""
+	if e.CustomErr != nil {
+		code, msg = e.CustomErr.Code, e.CustomErr.Message
+	}
+	fmt.Fprintf(&b, "%s: %s (http %d)", code, msg, e.httpCode)
+	fmt.Fprintf(&b, "\nerror: %s", e.Error())
+	if e.internalMsg != "" {
+		fmt.Fprintf(&b, "\ninternal: %s", e.internalMsg)
+	}
+	if len(e.contexts) > 0 {
+		b.WriteString("\ncontexts:")
+		for _, msg := range e.contexts {
+			fmt.Fprintf(&b, "\n\t%s", msg)
+		}
+	}
+	if len(e.ErrorCodes) > 0 {
+		fmt.Fprintf(&b, "\ncodes: %s", strings.Join(e.ErrorCodes, " > "))
+	}
+	if e.data != nil {
+		raw, _ := json.Marshal(encodeData(context.Background(), e.data, false))
+		fmt.Fprintf(&b, "\ndata: %s", raw)
+	}
+	if frames := e.GetStackTrace(); len(frames) > 0 {
+		b.WriteString("\nstack:")
+		for _, frame := range frames {
+			fmt.Fprintf(&b, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+		}
+	}
+	if sites := e.GetWrapSites(); len(sites) > 0 {
+		b.WriteString("\nwrapped at:")
+		for _, site := range sites {
+			fmt.Fprintf(&b, "\n\t%s\n\t\t%s:%d", site.Function, site.File, site.Line)
+		}
+	}
+	return b.String()
+}
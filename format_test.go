@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	defer SetConfig(DefaultConfig())
+	cfg := DefaultConfig()
+	cfg.StackDepth = 0
+	SetConfig(cfg)
+
+	ctx := context.Background()
+	customErr := GetCustomErr("ERR_FMT_1", "database is not reachable", true)
+	plain := GetAppErr(ctx, errors.New(`ping: "refused"`), customErr, 503)
+	detailed := GetAppErr(ctx, errors.New("ping: connection refused"), customErr, 503, map[string]string{"db": "orders"}).
+		SetInternalMsg("primary down").WrapMsg("loading order")
+
+	tests := []struct {
+		name   string
+		format string
+		err    *AppError
+		want   string
+	}{
+		{"v", "%v", plain, `ping: "refused"`},
+		{"s", "%s", plain, `ping: "refused"`},
+		{"q", "%q", plain, `"ping: \"refused\""`},
+		{"other verb", "%d", plain, `ping: "refused"`},
+		{"plus v terse", "%+v", plain, "ERR_FMT_1: database is not reachable (http 503)\n" +
+			"error: ping: \"refused\"\n" +
+			"codes: ERR_FMT_1"},
+		{"plus v detailed", "%+v", detailed, "ERR_FMT_1: database is not reachable (http 503)\n" +
+			"error: loading order: ping: connection refused\n" +
+			"internal: primary down\n" +
+			"contexts:\n\tloading order\n" +
+			"codes: ERR_FMT_1\n" +
+			`data: {"db":"orders"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.err); got != tt.want {
+				t.Errorf("Sprintf(%s) =\n%s\nwant\n%s", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatStack(t *testing.T) {
+	got := fmt.Sprintf("%+v", Wrap(context.Background(), stackOrigin(), nil, 0))
+	for _, want := range []string{"\nstack:\n\t", "stackOrigin\n\t\t", "\nwrapped at:\n\t", "TestFormatStack\n\t\t"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%%+v output lacks %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ginmw
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Trace seeds a TraceMeta into every request context like ae.TraceMiddleware, including the
+// X-Request-ID handling
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ae.TraceMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// Recovery converts panics in later handlers into Internal AppErrors, adds them to c.Errors and
+// aborts with the structured JSON response
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			appErr := ae.FromPanic(c.Request.Context(), recovered)
+			_ = c.Error(appErr)
+			c.Abort()
+			if !c.Writer.Written() {
+				appErr.WriteHTTP(c.Writer, c.Request)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// ErrorHandler writes the structured JSON response for errors handlers added with c.Error when
+// they did not write a response themselves: the last AppError in c.Errors is written with its HTTP
+// code, otherwise the last error becomes a 500 Internal error
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		var err error = c.Errors.Last().Err
+		for i := len(c.Errors) - 1; i >= 0; i-- {
+			var appErr *ae.AppError
+			if errors.As(c.Errors[i].Err, &appErr) {
+				err = appErr
+				break
+			}
+		}
+		ae.WriteError(c.Writer, c.Request, err)
+	}
+}
@@ -0,0 +1,71 @@
+package ginmw
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	notFound := ae.GetAppErr(context.Background(), errors.New("no rows"), ae.GetCustomErr("ERR_GIN_1", "order not found", false), 404)
+
+	tests := []struct {
+		name       string
+		handler    gin.HandlerFunc
+		wantStatus int
+		wantCode   string // Code of the JSON error body, empty when the handler's own response is kept
+	}{
+		{"success", func(ctx *gin.Context) { ctx.String(http.StatusOK, "ok") }, http.StatusOK, ""},
+		{"app error", func(ctx *gin.Context) { _ = ctx.Error(notFound) }, http.StatusNotFound, "ERR_GIN_1"},
+		{"plain error", func(ctx *gin.Context) { _ = ctx.Error(errors.New("boom")) }, http.StatusInternalServerError, ae.InternalError.Code},
+		{"last app error wins over later plain error", func(ctx *gin.Context) {
+			_ = ctx.Error(notFound)
+			_ = ctx.Error(errors.New("cleanup failed"))
+		}, http.StatusNotFound, "ERR_GIN_1"},
+		{"handler wrote response", func(ctx *gin.Context) {
+			_ = ctx.Error(notFound)
+			ctx.String(http.StatusConflict, "custom")
+		}, http.StatusConflict, ""},
+		{"panic", func(*gin.Context) { panic("boom") }, http.StatusInternalServerError, ae.PanicRecovered.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var traced bool
+			router := gin.New()
+			router.Use(Trace(), ErrorHandler(), Recovery())
+			router.GET("/", func(ctx *gin.Context) {
+				_, traced = ae.TraceFromContext(ctx.Request.Context())
+				tt.handler(ctx)
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !traced || rec.Header().Get(c.HeaderRequestID) == "" {
+				t.Error("handler ran without a TraceMeta and request ID")
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+			}
+			if body["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s", body["code"], tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+)
+
+// GoOption configures Go
+type GoOption func(*goOptions)
+
+// goOptions holds the settings of a background goroutine started by Go
+type goOptions struct {
+	customErr *CustomErr
+	reporter  Reporter
+}
+
+// WithGoCustomErr sets the custom error used for failures that are not AppErrors (InternalError
+// by default)
+func WithGoCustomErr(customErr *CustomErr) GoOption {
+	return func(o *goOptions) {
+		o.customErr = customErr
+	}
+}
+
+// WithGoReporter reports failures of the goroutine to the given reporter
+func WithGoReporter(reporter Reporter) GoOption {
+	return func(o *goOptions) {
+		o.reporter = reporter
+	}
+}
+
+// Go runs fn in a new goroutine for fire-and-forget work: panics are recovered, failures are
+// converted into AppErrors, logged with the trace identifiers of ctx and optionally reported.
+// Pass context.WithoutCancel(ctx) for work that must outlive the request.
+func Go(ctx context.Context, fn func(ctx context.Context) error, opts ...GoOption) {
+	o := &goOptions{customErr: InternalError}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	go func() {
+		err := runRecovered(ctx, fn)
+		if err == nil {
+			return
+		}
+
+		appErr, ok := asAppError(err)
+		if !ok {
+			appErr = GetAppErr(ctx, err, o.customErr, http.StatusInternalServerError)
+		}
+		logError(ctx, "background goroutine failed", appErr)
+		if o.reporter != nil {
+			_ = o.reporter.Report(ctx, appErr)
+		}
+	}()
+}
+
+// runRecovered calls fn, converting a panic into an AppError
+func runRecovered(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer Recover(ctx, &err)
+	return fn(ctx)
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestGo(t *testing.T) {
+	SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer SetLogger(nil)
+	existing := GetAppErr(context.Background(), errors.New("db down"), GetCustomErr("ERR_GO_1", "db down", true), 503)
+	custom := GetCustomErr("ERR_GO_2", "job failed", false)
+
+	tests := []struct {
+		name       string
+		fn         func(ctx context.Context) error
+		opts       []GoOption
+		wantReport bool
+		wantCode   string
+	}{
+		{"success", func(context.Context) error { return nil }, nil, false, ""},
+		{"plain error", func(context.Context) error { return errors.New("boom") }, nil, true, InternalError.Code},
+		{"custom error", func(context.Context) error { return errors.New("boom") }, []GoOption{WithGoCustomErr(custom)}, true, "ERR_GO_2"},
+		{"app error", func(context.Context) error { return existing }, nil, true, "ERR_GO_1"},
+		{"panic", func(context.Context) error { panic("boom") }, nil, true, PanicRecovered.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported := make(chan *AppError, 1)
+			done := make(chan struct{})
+			opts := append(tt.opts, WithGoReporter(ReporterFunc(func(_ context.Context, appErr *AppError) error {
+				reported <- appErr
+				return nil
+			})))
+
+			Go(context.Background(), func(ctx context.Context) error {
+				defer close(done)
+				return tt.fn(ctx)
+			}, opts...)
+
+			<-done
+			timeout := time.Second
+			if !tt.wantReport {
+				timeout = 50 * time.Millisecond
+			}
+			select {
+			case appErr := <-reported:
+				if !tt.wantReport || appErr.CustomErr.Code != tt.wantCode {
+					t.Errorf("reported %s, want report %v with %s", appErr.CustomErr.Code, tt.wantReport, tt.wantCode)
+				}
+			case <-time.After(timeout):
+				if tt.wantReport {
+					t.Error("failure was not reported")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package grpcae
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// ClientOption configures the client interceptors and FromGRPCError
+type ClientOption func(*clientOptions)
+
+// clientOptions holds the settings of the client interceptors
+type clientOptions struct {
+	translator *ae.Translator
+}
+
+// WithTranslator rewrites rehydrated downstream errors into this service's codes, see
+// ae.Translator.Translate
+func WithTranslator(translator *ae.Translator) ClientOption {
+	return func(o *clientOptions) {
+		o.translator = translator
+	}
+}
+
+// newClientOptions applies the client options
+func newClientOptions(opts []ClientOption) *clientOptions {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// InterceptorOption configures the server interceptors
+type InterceptorOption func(*interceptorOptions)
+
+// interceptorOptions holds the settings of the server interceptors
+type interceptorOptions struct {
+	logger *slog.Logger
+}
+
+// WithLogger logs every failed call with the given logger; wrap its handler with ae.NewSlogHandler
+// to get the AppError attributes expanded
+func WithLogger(logger *slog.Logger) InterceptorOption {
+	return func(o *interceptorOptions) {
+		o.logger = logger
+	}
+}
+
+// UnaryServerInterceptor seeds a TraceMeta for every call (unless the context already carries
+// one) and converts errors returned by handlers into gRPC statuses with ToGRPCStatus, attaching
+// the retry hints as trailers
+func UnaryServerInterceptor(opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	o := newInterceptorOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withTraceMeta(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		_ = SetRetryTrailer(ctx, err)
+		o.log(ctx, info.FullMethod, err)
+		return resp, ToGRPCStatus(err).Err()
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor
+func StreamServerInterceptor(opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	o := newInterceptorOptions(opts)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withTraceMeta(stream.Context(), info.FullMethod)
+		err := handler(srv, &tracedServerStream{ServerStream: stream, ctx: ctx})
+		if err == nil {
+			return nil
+		}
+
+		SetStreamRetryTrailer(stream, err)
+		o.log(ctx, info.FullMethod, err)
+		return ToGRPCStatus(err).Err()
+	}
+}
+
+// UnaryClientInterceptor rehydrates AppErrors from the statuses returned by calls
+func UnaryClientInterceptor(clientOpts ...ClientOption) grpc.UnaryClientInterceptor {
+	o := newClientOptions(clientOpts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return o.fromCallError(ctx, method, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor rehydrates AppErrors from the statuses returned by streams
+func StreamClientInterceptor(clientOpts ...ClientOption) grpc.StreamClientInterceptor {
+	o := newClientOptions(clientOpts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, o.fromCallError(ctx, method, err)
+		}
+		return &rehydratingClientStream{ClientStream: stream, ctx: ctx, method: method, opts: o}, nil
+	}
+}
+
+// newInterceptorOptions applies the interceptor options
+func newInterceptorOptions(opts []InterceptorOption) *interceptorOptions {
+	o := &interceptorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// log logs a failed call when a logger is configured
+func (o *interceptorOptions) log(ctx context.Context, method string, err error) {
+	if o.logger != nil {
+		o.logger.ErrorContext(ctx, "grpc call failed", "method", method, "err", err)
+	}
+}
+
+// withTraceMeta returns ctx carrying a TraceMeta for the call, keeping an existing one
+func withTraceMeta(ctx context.Context, method string) context.Context {
+	if _, ok := ae.TraceFromContext(ctx); ok {
+		return ctx
+	}
+	ctx = ae.ContextWithTrace(ctx)
+	traceMeta, _ := ae.TraceFromContext(ctx)
+	traceMeta.Trace = append(traceMeta.Trace, ae.TraceEntry{Time: time.Now(), Message: method, Severity: slog.LevelInfo})
+	traceMeta.IdentifierMappings["grpc_method"] = method
+	return ctx
+}
+
+// tracedServerStream overrides the context of a server stream
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the TraceMeta
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// rehydratingClientStream converts the statuses returned by a client stream into AppErrors
+type rehydratingClientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	method string
+	opts   *clientOptions
+}
+
+// SendMsg sends a message, rehydrating a failure
+func (s *rehydratingClientStream) SendMsg(m interface{}) error {
+	return s.convert(s.ClientStream.SendMsg(m))
+}
+
+// RecvMsg receives a message, rehydrating a failure; io.EOF is passed through
+func (s *rehydratingClientStream) RecvMsg(m interface{}) error {
+	return s.convert(s.ClientStream.RecvMsg(m))
+}
+
+// convert rehydrates an AppError from a stream error other than io.EOF
+func (s *rehydratingClientStream) convert(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		return err
+	}
+	return s.opts.fromCallError(s.ctx, s.method, err)
+}
@@ -0,0 +1,140 @@
+package grpcae
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	appErr := ae.GetAppErr(context.Background(), errors.New("no rows"), ae.GetCustomErr("ERR_INT_1", "order not found", false), 404)
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}
+	traced := ae.ContextWithTrace(context.Background())
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		wantCode   codes.Code
+		wantLogged bool
+		wantSeeded bool
+	}{
+		{"success", context.Background(), nil, codes.OK, false, true},
+		{"app error", context.Background(), appErr, codes.NotFound, true, true},
+		{"plain error", context.Background(), errors.New("boom"), codes.Internal, true, true},
+		{"existing trace kept", traced, appErr, codes.NotFound, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			interceptor := UnaryServerInterceptor(WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+			var handlerCtx context.Context
+			_, err := interceptor(tt.ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				handlerCtx = ctx
+				return nil, tt.err
+			})
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("status code = %v, want %v", got, tt.wantCode)
+			}
+			if (logs.Len() > 0) != tt.wantLogged {
+				t.Errorf("logged %q, want logged %v", logs.String(), tt.wantLogged)
+			}
+			if _, ok := ae.TraceFromContext(handlerCtx); !ok {
+				t.Fatal("handler context carries no TraceMeta")
+			}
+			if seeded := ae.GetIdentifiers(handlerCtx)["grpc_method"] == info.FullMethod; seeded != tt.wantSeeded {
+				t.Errorf("grpc_method identifier seeded %v, want %v", seeded, tt.wantSeeded)
+			}
+		})
+	}
+}
+
+// fakeClientStream is a grpc.ClientStream failing with err
+type fakeClientStream struct {
+	grpc.ClientStream
+	err error
+}
+
+func (s *fakeClientStream) SendMsg(interface{}) error { return s.err }
+func (s *fakeClientStream) RecvMsg(interface{}) error { return s.err }
+
+func TestClientInterceptors(t *testing.T) {
+	appErr := ae.GetAppErr(context.Background(), errors.New("no rows"), ae.GetCustomErr("ERR_INT_2", "order not found", false), 404)
+	translated := ae.GetCustomErr("ERR_INT_3", "item unavailable", false)
+	translator := ae.NewTranslator(ae.TranslationTable{"ERR_INT_2": translated})
+
+	tests := []struct {
+		name     string
+		err      error
+		opts     []ClientOption
+		wantCode string // Primary code of the rehydrated AppError, empty when err is passed through
+	}{
+		{"success", nil, nil, ""},
+		{"status of this package", ToGRPCStatus(appErr).Err(), nil, "ERR_INT_2"},
+		{"foreign status", status.Error(codes.Unavailable, "connection reset"), nil, "ERR_GRPC_UNAVAILABLE"},
+		{"translated", ToGRPCStatus(appErr).Err(), []ClientOption{WithTranslator(translator)}, "ERR_INT_3"},
+		{"not a status", io.ErrUnexpectedEOF, nil, ""},
+	}
+	const method = "/orders.Orders/Get"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(kind string, err error) {
+				t.Helper()
+				var got *ae.AppError
+				if !errors.As(err, &got) {
+					if tt.wantCode != "" || err != tt.err {
+						t.Errorf("%s: error %v, want AppError %s", kind, err, tt.wantCode)
+					}
+					return
+				}
+				if got.GetErrCode() != tt.wantCode {
+					t.Errorf("%s: code %s, want %s", kind, got.GetErrCode(), tt.wantCode)
+				}
+			}
+
+			unary := UnaryClientInterceptor(tt.opts...)
+			check("unary", unary(context.Background(), method, nil, nil, nil,
+				func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+					return tt.err
+				}))
+
+			streaming := StreamClientInterceptor(tt.opts...)
+			_, err := streaming(context.Background(), &grpc.StreamDesc{}, nil, method,
+				func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+					return nil, tt.err
+				})
+			if tt.err != nil {
+				check("stream open", err)
+			}
+
+			stream, err := streaming(context.Background(), &grpc.StreamDesc{}, nil, method,
+				func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+					return &fakeClientStream{err: tt.err}, nil
+				})
+			if err != nil {
+				t.Fatalf("opening stream: %v", err)
+			}
+			check("send", stream.SendMsg(nil))
+			check("recv", stream.RecvMsg(nil))
+		})
+	}
+
+	stream, _ := StreamClientInterceptor()(context.Background(), &grpc.StreamDesc{}, nil, method,
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &fakeClientStream{err: io.EOF}, nil
+		})
+	if err := stream.RecvMsg(nil); err != io.EOF {
+		t.Errorf("RecvMsg = %v, want io.EOF passed through", err)
+	}
+}
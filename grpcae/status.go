@@ -0,0 +1,270 @@
+package grpcae
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// ErrorInfo metadata keys written by ToGRPCStatus
+const (
+	metaMessage    = "message"
+	metaErrorCodes = "error_codes"
+	metaHTTPCode   = "http_code"
+	metaRetryable  = "retryable"
+	metaData       = "data"
+	metaErrorID    = "error_id"
+)
+
+// HTTPToGRPCCode maps an HTTP status code to the closest gRPC code; an unset code maps to Internal
+func HTTPToGRPCCode(httpCode int) codes.Code {
+	switch httpCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return codes.OutOfRange
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case ae.StatusClientClosedRequest:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if httpCode >= 400 && httpCode < 500 {
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// GRPCCodeToHTTP maps a gRPC code to the closest HTTP status code
+func GRPCCodeToHTTP(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return ae.StatusClientClosedRequest
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// ToGRPCStatus converts err into a gRPC status. AppErrors get the code mapped from their HTTP code,
+// the client-facing message, and a google.rpc.ErrorInfo detail (reason = primary code) carrying
+// the message, error codes, HTTP code, retryability, error ID and client-facing data, plus a
+// google.rpc.RetryInfo detail when a retry delay is set. Errors already carrying a status keep it
+// and any other error becomes Internal without exposing its text; nil returns nil
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *ae.AppError
+	if !errors.As(err, &appErr) || appErr == nil {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+		return status.New(codes.Internal, ae.InternalError.Message)
+	}
+
+	body := struct {
+		Code       string          `json:"code"`
+		Message    string          `json:"message"`
+		ErrorCodes []string        `json:"error_codes"`
+		Data       json.RawMessage `json:"data"`
+		Retryable  bool            `json:"retryable"`
+	}{}
+	if raw, err := appErr.SerializeAs(ae.SerializerPublic); err == nil {
+		_ = json.Unmarshal(raw, &body)
+	}
+
+	st := status.New(HTTPToGRPCCode(appErr.GetHTTPCode()), body.Message)
+	info := &errdetails.ErrorInfo{
+		Reason: body.Code,
+		Domain: c.GRPCErrorDomain,
+		Metadata: map[string]string{
+			metaMessage:    body.Message,
+			metaErrorCodes: strings.Join(body.ErrorCodes, ","),
+			metaHTTPCode:   strconv.Itoa(appErr.GetHTTPCode()),
+			metaRetryable:  strconv.FormatBool(body.Retryable),
+			metaErrorID:    appErr.GetID(),
+		},
+	}
+	if len(body.Data) > 0 && string(body.Data) != "null" {
+		info.Metadata[metaData] = string(body.Data)
+	}
+
+	details := []protoadapt.MessageV1{info}
+	if wait := appErr.GetRetryAfter(); wait > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// FromGRPCStatus rehydrates an AppError from a gRPC status. Statuses written by ToGRPCStatus keep
+// their code, message, error codes, HTTP code, retryability, data and retry delay; other statuses
+// become an AppError coded "ERR_GRPC_<CODE>" with the HTTP code mapped from the gRPC code, retryable
+// for Unavailable, ResourceExhausted, Aborted and DeadlineExceeded. Their message is the generic one
+// of the HTTP code, so downstream text never reaches clients; the status message is kept as the
+// internal message. The category follows the HTTP code. The status error is kept as the underlying
+// error and an OK or nil status returns nil
+func FromGRPCStatus(ctx context.Context, st *status.Status) *ae.AppError {
+	if st == nil {
+		return nil
+	}
+	return fromStatus(ctx, st, st.Err(), 2)
+}
+
+// fromStatus implements FromGRPCStatus with err as the underlying error, capturing the stack skip
+// frames above fromStatus
+func fromStatus(ctx context.Context, st *status.Status, err error, skip int) *ae.AppError {
+	if st.Code() == codes.OK {
+		return nil
+	}
+
+	httpCode := GRPCCodeToHTTP(st.Code())
+	customErr := &ae.CustomErr{
+		Code:    "ERR_GRPC_" + strings.ToUpper(toSnake(st.Code().String())),
+		Message: ae.CustomErrForStatus(httpCode).Message,
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		customErr.Retryable = true
+	}
+
+	opts := []ae.Option{ae.WithStackSkip(skip)}
+	foreign := true
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() != c.GRPCErrorDomain {
+				continue
+			}
+			foreign = false
+			meta := d.GetMetadata()
+			customErr.Code = d.GetReason()
+			customErr.Message = meta[metaMessage]
+			customErr.Retryable, _ = strconv.ParseBool(meta[metaRetryable])
+			if n, err := strconv.Atoi(meta[metaHTTPCode]); err == nil {
+				httpCode = n
+			}
+			if codes := meta[metaErrorCodes]; codes != "" {
+				opts = append(opts, ae.WithErrorCodes(strings.Split(codes, ",")...))
+			}
+			if raw, ok := meta[metaData]; ok {
+				var data interface{}
+				if err := json.Unmarshal([]byte(raw), &data); err == nil {
+					opts = append(opts, ae.WithData(data))
+				}
+			}
+		case *errdetails.RetryInfo:
+			if delay := d.GetRetryDelay(); delay != nil {
+				opts = append(opts, ae.WithRetryAfter(delay.AsDuration()))
+			}
+		}
+	}
+
+	if foreign && st.Message() != "" {
+		opts = append(opts, ae.WithInternalMsg(st.Message()))
+	}
+	customErr.Category = ae.CustomErrForStatus(httpCode).Category
+	opts = append(opts, ae.WithCustomErr(customErr), ae.WithHTTPCode(httpCode))
+	return ae.New(ctx, err, opts...)
+}
+
+// FromGRPCError rehydrates an AppError from an error returned by a downstream gRPC call, see
+// FromGRPCStatus; err stays the underlying error, so its text is added to the trace stored in ctx.
+// Errors without a gRPC status and AppErrors are returned as they are
+func FromGRPCError(ctx context.Context, err error, opts ...ClientOption) error {
+	return newClientOptions(opts).fromError(ctx, err, "", 2)
+}
+
+// fromError implements FromGRPCError, capturing the stack skip frames above fromError. The status
+// is extracted from err itself; a non-empty method is only named in the underlying error, since
+// statuses of wrapped errors take the whole wrapped text as their message
+func (o *clientOptions) fromError(ctx context.Context, err error, method string, skip int) error {
+	var appErr *ae.AppError
+	if err == nil || errors.As(err, &appErr) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	underlying := err
+	if method != "" {
+		underlying = fmt.Errorf("grpc call %s: %w", method, err)
+	}
+	appErr = fromStatus(ctx, st, underlying, skip+1)
+	if appErr == nil {
+		return nil
+	}
+	if o.translator != nil {
+		return o.translator.Translate(ctx, appErr)
+	}
+	return appErr
+}
+
+// fromCallError rehydrates an AppError from the error of a call to method, naming the method in
+// the underlying error and the trace
+func (o *clientOptions) fromCallError(ctx context.Context, method string, err error) error {
+	return o.fromError(ctx, err, method, 2)
+}
+
+// toSnake converts a CamelCase gRPC code name into snake_case
+func toSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
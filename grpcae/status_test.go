@@ -0,0 +1,185 @@
+package grpcae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+func TestStatusRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	notFound := ae.GetCustomErr("ERR_GST_1", "order not found", false)
+	busy := ae.GetCustomErr("ERR_GST_2", "try again", true)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantGRPC      codes.Code
+		wantCode      string
+		wantMsg       string
+		wantCodes     []string
+		wantHTTP      int
+		wantRetryable bool
+		wantData      interface{}
+		wantAfter     time.Duration
+	}{
+		{"not found", ae.GetAppErr(ctx, errors.New("no rows"), notFound, http.StatusNotFound),
+			codes.NotFound, "ERR_GST_1", "order not found", []string{"ERR_GST_1"}, 404, false, nil, 0},
+		{"data", ae.GetAppErr(ctx, errors.New("no rows"), notFound, http.StatusNotFound, map[string]interface{}{"order": "o1", "token": "abc"}),
+			codes.NotFound, "ERR_GST_1", "order not found", []string{"ERR_GST_1"}, 404, false,
+			map[string]interface{}{"order": "o1", "token": "[REDACTED]"}, 0},
+		{"retry delay", ae.GetAppErr(ctx, errors.New("busy"), busy, http.StatusServiceUnavailable).SetRetryAfter(1500 * time.Millisecond),
+			codes.Unavailable, "ERR_GST_2", "try again", []string{"ERR_GST_2"}, 503, true, nil, 1500 * time.Millisecond},
+		{"chain", ae.Wrap(ctx, ae.GetAppErr(ctx, errors.New("no rows"), notFound, http.StatusNotFound), busy, http.StatusTooManyRequests),
+			codes.ResourceExhausted, "ERR_GST_2", "try again", []string{"ERR_GST_1", "ERR_GST_2"}, 429, true, nil, 0},
+		{"wrapped by fmt", fmt.Errorf("handler: %w", ae.GetAppErr(ctx, errors.New("conflict"), notFound, http.StatusConflict)),
+			codes.Aborted, "ERR_GST_1", "order not found", []string{"ERR_GST_1"}, 409, false, nil, 0},
+		{"unmapped client error", ae.GetAppErr(ctx, errors.New("teapot"), notFound, http.StatusTeapot),
+			codes.FailedPrecondition, "ERR_GST_1", "order not found", []string{"ERR_GST_1"}, 418, false, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := ToGRPCStatus(tt.err)
+			if st.Code() != tt.wantGRPC || st.Message() != tt.wantMsg {
+				t.Errorf("ToGRPCStatus = %v %q, want %v %q", st.Code(), st.Message(), tt.wantGRPC, tt.wantMsg)
+			}
+
+			got := FromGRPCStatus(ctx, st)
+			if got.GetErrCode() != tt.wantCode || got.GetMsg() != tt.wantMsg || !reflect.DeepEqual(got.GetErrCodes(), tt.wantCodes) {
+				t.Errorf("FromGRPCStatus = %s %q %v, want %s %q %v",
+					got.GetErrCode(), got.GetMsg(), got.GetErrCodes(), tt.wantCode, tt.wantMsg, tt.wantCodes)
+			}
+			if got.GetHTTPCode() != tt.wantHTTP || got.IsRetryable() != tt.wantRetryable || got.GetRetryAfter() != tt.wantAfter {
+				t.Errorf("FromGRPCStatus = http %d retryable %v after %v, want %d %v %v",
+					got.GetHTTPCode(), got.IsRetryable(), got.GetRetryAfter(), tt.wantHTTP, tt.wantRetryable, tt.wantAfter)
+			}
+			if !reflect.DeepEqual(got.GetData(), tt.wantData) {
+				t.Errorf("data = %#v, want %#v", got.GetData(), tt.wantData)
+			}
+			if got.GetInternalMsg() != "" {
+				t.Errorf("internal message = %q, want none for statuses of this package", got.GetInternalMsg())
+			}
+		})
+	}
+}
+
+func TestFromGRPCStatusForeign(t *testing.T) {
+	tests := []struct {
+		name          string
+		st            *status.Status
+		wantCode      string
+		wantHTTP      int
+		wantRetryable bool
+	}{
+		{"not found", status.New(codes.NotFound, "row 7 missing in shard-3"), "ERR_GRPC_NOT_FOUND", 404, false},
+		{"unavailable", status.New(codes.Unavailable, "connection reset"), "ERR_GRPC_UNAVAILABLE", 503, true},
+		{"deadline", status.New(codes.DeadlineExceeded, "deadline exceeded"), "ERR_GRPC_DEADLINE_EXCEEDED", 504, true},
+		{"already exists", status.New(codes.AlreadyExists, "duplicate"), "ERR_GRPC_ALREADY_EXISTS", 409, false},
+		{"unknown", status.New(codes.Unknown, "panic in handler"), "ERR_GRPC_UNKNOWN", 500, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromGRPCStatus(context.Background(), tt.st)
+			if got.GetErrCode() != tt.wantCode || got.GetHTTPCode() != tt.wantHTTP || got.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromGRPCStatus = %s %d %v, want %s %d %v",
+					got.GetErrCode(), got.GetHTTPCode(), got.IsRetryable(), tt.wantCode, tt.wantHTTP, tt.wantRetryable)
+			}
+			if got.GetMsg() != ae.CustomErrForStatus(tt.wantHTTP).Message || got.GetInternalMsg() != tt.st.Message() {
+				t.Errorf("message %q, internal %q: downstream text must only be kept internally", got.GetMsg(), got.GetInternalMsg())
+			}
+			if s, ok := status.FromError(got); !ok || s.Code() != tt.st.Code() {
+				t.Errorf("the status error must stay the underlying error, got %v", got.GetErr())
+			}
+		})
+	}
+
+	if FromGRPCStatus(context.Background(), nil) != nil || FromGRPCStatus(context.Background(), status.New(codes.OK, "")) != nil {
+		t.Error("nil and OK statuses must convert to nil")
+	}
+}
+
+func TestToGRPCStatusOtherErrors(t *testing.T) {
+	existing := status.Error(codes.PermissionDenied, "denied")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{"plain error", errors.New("pq: password authentication failed"), codes.Internal, ae.InternalError.Message},
+		{"status error", existing, codes.PermissionDenied, "denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := ToGRPCStatus(tt.err)
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg || len(st.Details()) != 0 {
+				t.Errorf("ToGRPCStatus = %v %q %v, want %v %q", st.Code(), st.Message(), st.Details(), tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+	if ToGRPCStatus(nil) != nil {
+		t.Error("ToGRPCStatus(nil) must be nil")
+	}
+}
+
+func TestFromGRPCError(t *testing.T) {
+	embedded := ToGRPCStatus(ae.GetAppErr(context.Background(), errors.New("no rows"),
+		ae.GetCustomErr("ERR_GST_3", "order not found", false), http.StatusNotFound)).Err()
+	unavailable := status.Error(codes.Unavailable, "connection reset")
+	plain := errors.New("dial failed")
+	existing := ae.GetAppErr(context.Background(), errors.New("no rows"), ae.GetCustomErr("ERR_GST_4", "missing", false), http.StatusNotFound)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantHTTP      int
+		wantRetryable bool
+		wantTrace     []string
+	}{
+		{"embedded custom error", embedded, "ERR_GST_3", 404, false, []string{embedded.Error()}},
+		{"unavailable", unavailable, "ERR_GRPC_UNAVAILABLE", 503, true, []string{unavailable.Error()}},
+		{"not found", status.Error(codes.NotFound, "row 7"), "ERR_GRPC_NOT_FOUND", 404, false,
+			[]string{"rpc error: code = NotFound desc = row 7"}},
+		{"wrapped by fmt", fmt.Errorf("load order: %w", unavailable), "ERR_GRPC_UNAVAILABLE", 503, true,
+			[]string{"load order: " + unavailable.Error()}},
+		{"AppError", existing, "ERR_GST_4", 404, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ae.ContextWithTrace(context.Background())
+			err := FromGRPCError(ctx, tt.err)
+			var got *ae.AppError
+			if !errors.As(err, &got) {
+				t.Fatalf("FromGRPCError = %T %v, want an AppError", err, err)
+			}
+			if got.GetErrCode() != tt.wantCode || got.GetHTTPCode() != tt.wantHTTP || got.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromGRPCError = %s %d %v, want %s %d %v",
+					got.GetErrCode(), got.GetHTTPCode(), got.IsRetryable(), tt.wantCode, tt.wantHTTP, tt.wantRetryable)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("FromGRPCError must keep %v as the underlying error", tt.err)
+			}
+			traceMeta, _ := ae.TraceFromContext(ctx)
+			if lines := traceMeta.ErrorLines(); !reflect.DeepEqual(lines, tt.wantTrace) {
+				t.Errorf("trace = %q, want %q", lines, tt.wantTrace)
+			}
+		})
+	}
+
+	for _, err := range []error{nil, plain} {
+		if got := FromGRPCError(context.Background(), err); got != err {
+			t.Errorf("FromGRPCError(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
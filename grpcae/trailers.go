@@ -0,0 +1,70 @@
+package grpcae
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// RetryTrailer builds trailer metadata carrying the retry hints of an AppError: whether it is
+// retryable, the Retry-After delay in seconds and the gRPC retry pushback in milliseconds; it
+// returns nil when err is not an AppError
+func RetryTrailer(err error) metadata.MD {
+	var appErr *ae.AppError
+	if !errors.As(err, &appErr) || appErr == nil {
+		return nil
+	}
+
+	retryable := appErr.CustomErr != nil && appErr.CustomErr.Retryable
+	md := metadata.Pairs(c.GRPCRetryableKey, strconv.FormatBool(retryable))
+	if wait := appErr.GetRetryAfter(); retryable && wait > 0 {
+		md.Set(c.GRPCRetryAfterKey, strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10))
+		md.Set(c.GRPCRetryPushbackKey, strconv.FormatInt(wait.Milliseconds(), 10))
+	}
+	return md
+}
+
+// SetRetryTrailer attaches the retry hints of err to the trailer of a unary call
+func SetRetryTrailer(ctx context.Context, err error) error {
+	md := RetryTrailer(err)
+	if md == nil {
+		return nil
+	}
+	return grpc.SetTrailer(ctx, md)
+}
+
+// SetStreamRetryTrailer attaches the retry hints of err to the trailer of a server stream
+func SetStreamRetryTrailer(stream grpc.ServerStream, err error) {
+	if md := RetryTrailer(err); md != nil {
+		stream.SetTrailer(md)
+	}
+}
+
+// RetryHintsFromTrailer reads the retry hints written by RetryTrailer; ok is false when the
+// trailer carries none
+func RetryHintsFromTrailer(md metadata.MD) (retryable bool, retryAfter time.Duration, ok bool) {
+	values := md.Get(c.GRPCRetryableKey)
+	if len(values) == 0 {
+		return false, 0, false
+	}
+	retryable, _ = strconv.ParseBool(values[0])
+
+	if ms := md.Get(c.GRPCRetryPushbackKey); len(ms) > 0 {
+		if n, err := strconv.ParseInt(ms[0], 10, 64); err == nil && n > 0 {
+			retryAfter = time.Duration(n) * time.Millisecond
+		}
+	} else if secs := md.Get(c.GRPCRetryAfterKey); len(secs) > 0 {
+		if n, err := strconv.ParseInt(secs[0], 10, 64); err == nil && n > 0 {
+			retryAfter = time.Duration(n) * time.Second
+		}
+	}
+	return retryable, retryAfter, true
+}
@@ -0,0 +1,66 @@
+package grpcae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestRetryTrailerRoundTrip(t *testing.T) {
+	retryable := ae.GetCustomErr("ERR_TRL_1", "unavailable", true)
+	permanent := ae.GetCustomErr("ERR_TRL_2", "invalid", false)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantMD        metadata.MD
+		wantRetryable bool
+		wantAfter     time.Duration
+	}{
+		{"plain error", errors.New("boom"), nil, false, 0},
+		{"permanent", ae.GetAppErr(context.Background(), errors.New("bad"), permanent, 400).SetRetryAfter(time.Second),
+			metadata.Pairs(c.GRPCRetryableKey, "false"), false, 0},
+		{"retryable without delay", ae.GetAppErr(context.Background(), errors.New("down"), retryable, 503),
+			metadata.Pairs(c.GRPCRetryableKey, "true"), true, 0},
+		{"retryable with delay", ae.GetAppErr(context.Background(), errors.New("down"), retryable, 503).SetRetryAfter(1500 * time.Millisecond),
+			metadata.Pairs(c.GRPCRetryableKey, "true", c.GRPCRetryAfterKey, "2", c.GRPCRetryPushbackKey, "1500"), true, 1500 * time.Millisecond},
+		{"wrapped", fmt.Errorf("calling: %w", ae.GetAppErr(context.Background(), errors.New("down"), retryable, 503).SetRetryAfter(time.Second)),
+			metadata.Pairs(c.GRPCRetryableKey, "true", c.GRPCRetryAfterKey, "1", c.GRPCRetryPushbackKey, "1000"), true, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := RetryTrailer(tt.err)
+			if fmt.Sprint(md) != fmt.Sprint(tt.wantMD) {
+				t.Errorf("RetryTrailer = %v, want %v", md, tt.wantMD)
+			}
+			gotRetryable, gotAfter, ok := RetryHintsFromTrailer(md)
+			if ok != (tt.wantMD != nil) || gotRetryable != tt.wantRetryable || gotAfter != tt.wantAfter {
+				t.Errorf("RetryHintsFromTrailer = %v, %v, %v, want %v, %v, %v",
+					gotRetryable, gotAfter, ok, tt.wantRetryable, tt.wantAfter, tt.wantMD != nil)
+			}
+		})
+	}
+}
+
+func TestRetryHintsFromTrailerSeconds(t *testing.T) {
+	tests := []struct {
+		md   metadata.MD
+		want time.Duration
+	}{
+		{metadata.Pairs(c.GRPCRetryableKey, "true", c.GRPCRetryAfterKey, "3"), 3 * time.Second},
+		{metadata.Pairs(c.GRPCRetryableKey, "true", c.GRPCRetryAfterKey, "soon"), 0},
+		{metadata.Pairs(c.GRPCRetryableKey, "true", c.GRPCRetryPushbackKey, "-1"), 0},
+	}
+	for _, tt := range tests {
+		if _, got, _ := RetryHintsFromTrailer(tt.md); got != tt.want {
+			t.Errorf("RetryHintsFromTrailer(%v) = %v, want %v", tt.md, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// IsRetryable reports whether err is an AppError marked retryable
+func IsRetryable(err error) bool {
+	appErr, ok := asAppError(err)
+	return ok && appErr.IsRetryable()
+}
+
+// hedgeResult is the outcome of a single hedged attempt
+type hedgeResult struct {
+	value interface{}
+	err   error
+}
+
+// Hedge calls fn and, while no attempt has succeeded, launches a duplicate attempt every delay up
+// to max attempts in total, returning the first success and cancelling the other attempts. A
+// retryable AppError failure launches the next attempt right away, while any other failure stops
+// hedging and is returned, so only idempotent calls failing transiently are duplicated.
+func Hedge(ctx context.Context, fn func(ctx context.Context) (interface{}, error), delay time.Duration, max int) (interface{}, error) {
+	if max < 1 {
+		max = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, max)
+	launched, pending := 0, 0
+	launch := func() {
+		launched++
+		pending++
+		go func() {
+			value, err := fn(ctx)
+			results <- hedgeResult{value: value, err: err}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				return r.value, nil
+			}
+			lastErr = r.err
+			if !IsRetryable(r.err) {
+				return nil, r.err
+			}
+			if launched < max {
+				launch()
+				timer.Reset(delay)
+			} else if pending == 0 {
+				return nil, lastErr
+			}
+		case <-timer.C:
+			if launched < max {
+				launch()
+				timer.Reset(delay)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
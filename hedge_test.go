@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	transient := GetAppErr(context.Background(), errors.New("timeout"), GetCustomErr("ERR_HDG_1", "timeout", true), 504)
+	permanent := GetAppErr(context.Background(), errors.New("invalid"), GetCustomErr("ERR_HDG_2", "invalid", false), 400)
+
+	tests := []struct {
+		name         string
+		delay        time.Duration
+		max          int
+		attempt      func(ctx context.Context, n int32) (interface{}, error)
+		want         interface{}
+		wantErr      error
+		wantAttempts int32
+	}{
+		{"first succeeds", time.Hour, 3, func(context.Context, int32) (interface{}, error) { return "ok", nil }, "ok", nil, 1},
+		{"slow attempt hedged", 10 * time.Millisecond, 3, func(ctx context.Context, n int32) (interface{}, error) {
+			if n == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return n, nil
+		}, int32(2), nil, 2},
+		{"retryable failure retried at once", time.Hour, 3, func(_ context.Context, n int32) (interface{}, error) {
+			if n == 1 {
+				return nil, transient
+			}
+			return "ok", nil
+		}, "ok", nil, 2},
+		{"retryable failures exhaust max", time.Hour, 3, func(context.Context, int32) (interface{}, error) { return nil, transient }, nil, transient, 3},
+		{"permanent failure stops hedging", time.Hour, 3, func(context.Context, int32) (interface{}, error) { return nil, permanent }, nil, permanent, 1},
+		{"max below one", time.Hour, 0, func(context.Context, int32) (interface{}, error) { return nil, transient }, nil, transient, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			got, err := Hedge(context.Background(), func(ctx context.Context) (interface{}, error) {
+				return tt.attempt(ctx, attempts.Add(1))
+			}, tt.delay, tt.max)
+
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Hedge = %v, %v, want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+			if n := attempts.Load(); n != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", n, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestHedgeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Hedge(ctx, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil, ctx.Err()
+	}, time.Hour, 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Hedge = %v, want %v", err, context.Canceled)
+	}
+}
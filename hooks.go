@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Hook is invoked for every AppError created by GetAppErr, New and Wrap, e.g. to add metrics,
+// sampling, enrichment or alerting
+type Hook func(ctx context.Context, appErr *AppError)
+
+// hooks holds the registered hooks in registration order; writers are serialized by hooksMu
+var (
+	hooks   atomic.Pointer[[]Hook]
+	hooksMu sync.Mutex
+)
+
+// RegisterHook adds a hook to the creation pipeline. Hooks run synchronously in registration
+// order after the error is fully built, so later hooks see the enrichment of earlier ones; a
+// panicking hook is logged and skipped without affecting the others or the caller
+func RegisterHook(hook Hook) {
+	if hook == nil {
+		return
+	}
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	registered := []Hook{}
+	if current := hooks.Load(); current != nil {
+		registered = append(registered, *current...)
+	}
+	registered = append(registered, hook)
+	hooks.Store(&registered)
+}
+
+// ClearHooks removes every registered hook
+func ClearHooks() {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks.Store(nil)
+}
+
+// runHooks passes a newly created AppError through the registered hooks
+func runHooks(ctx context.Context, appErr *AppError) {
+	registered := hooks.Load()
+	if registered == nil {
+		return
+	}
+	for i, hook := range *registered {
+		runHook(ctx, i, hook, appErr)
+	}
+}
+
+// runHook invokes a single hook, recovering and logging its panic
+func runHook(ctx context.Context, index int, hook Hook, appErr *AppError) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			getLogger().ErrorContext(ctx, "error hook panicked",
+				"hook", index, "code", primaryCode(appErr), "panic", scrub(fmt.Sprint(recovered)))
+		}
+	}()
+	hook(ctx, appErr)
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterHook(t *testing.T) {
+	defer ClearHooks()
+	customErr := GetCustomErr("ERR_HKS_1", "order not found", false)
+
+	tests := []struct {
+		name      string
+		hooks     func(calls *[]string) []Hook
+		create    func(ctx context.Context) *AppError
+		wantCalls []string
+		wantLabel string
+		wantLog   bool
+	}{
+		{"none", func(*[]string) []Hook { return nil },
+			func(ctx context.Context) *AppError { return GetAppErr(ctx, errors.New("no rows"), customErr, 404) }, nil, "", false},
+		{"in registration order", func(calls *[]string) []Hook {
+			return []Hook{
+				func(_ context.Context, e *AppError) { *calls = append(*calls, "first"); e.SetLabel("team", "orders") },
+				nil,
+				func(_ context.Context, e *AppError) { *calls = append(*calls, "second:"+e.GetLabels()["team"]) },
+			}
+		}, func(ctx context.Context) *AppError { return GetAppErr(ctx, errors.New("no rows"), customErr, 404) },
+			[]string{"first", "second:orders"}, "orders", false},
+		{"panicking hook is isolated", func(calls *[]string) []Hook {
+			return []Hook{
+				func(context.Context, *AppError) { panic("enrichment failed") },
+				func(context.Context, *AppError) { *calls = append(*calls, "after panic") },
+			}
+		}, func(ctx context.Context) *AppError { return GetAppErr(ctx, errors.New("no rows"), customErr, 404) },
+			[]string{"after panic"}, "", true},
+		{"New", func(calls *[]string) []Hook {
+			return []Hook{func(_ context.Context, e *AppError) { *calls = append(*calls, e.GetErrCode()) }}
+		}, func(ctx context.Context) *AppError { return New(ctx, errors.New("no rows"), WithCustomErr(customErr)) },
+			[]string{"ERR_HKS_1"}, "", false},
+		{"Wrap", func(calls *[]string) []Hook {
+			return []Hook{func(_ context.Context, e *AppError) { *calls = append(*calls, e.GetErrCode()) }}
+		}, func(ctx context.Context) *AppError { return Wrap(ctx, errors.New("no rows"), customErr, 404) },
+			[]string{"ERR_HKS_1"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClearHooks()
+			var logged bytes.Buffer
+			SetLogger(slog.New(slog.NewTextHandler(&logged, nil)))
+			defer SetLogger(nil)
+
+			var calls []string
+			for _, hook := range tt.hooks(&calls) {
+				RegisterHook(hook)
+			}
+			appErr := tt.create(context.Background())
+
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+			if got := appErr.GetLabels()["team"]; got != tt.wantLabel {
+				t.Errorf("label = %q, want %q", got, tt.wantLabel)
+			}
+			if got := strings.Contains(logged.String(), "error hook panicked"); got != tt.wantLog {
+				t.Errorf("logged panic = %v, want %v: %s", got, tt.wantLog, logged.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// errorEnvelope is the JSON body written for an AppError
+type errorEnvelope struct {
+	Code        string                 `json:"code"`
+	Message     string                 `json:"message"`
+	ErrorCodes  []string               `json:"error_codes"`
+	Data        interface{}            `json:"data,omitempty"`
+	Retryable   bool                   `json:"retryable"`
+	Retry       *retryHint             `json:"retry,omitempty"`
+	TraceID     string                 `json:"trace_id,omitempty"`
+	Severity    Severity               `json:"severity,omitempty"`
+	Category    Category               `json:"category,omitempty"`
+	Identifiers map[string]interface{} `json:"identifiers,omitempty"`
+	Debug       *debugInfo             `json:"debug,omitempty"`
+}
+
+// envelope builds the JSON body of the AppError, preparing data for clients or internal use;
+// encoding issues are noted in the trace stored in ctx
+func (e *AppError) envelope(ctx context.Context, clientFacing bool) errorEnvelope {
+	env := errorEnvelope{
+		ErrorCodes: e.ErrorCodes,
+		Data:       encodeData(ctx, e.data, clientFacing),
+	}
+	if env.ErrorCodes == nil {
+		env.ErrorCodes = []string{}
+	}
+	if !clientFacing {
+		env.Severity = e.GetSeverity()
+		env.Category = e.GetCategory()
+		env.Identifiers = e.identifiers
+	}
+	if e.CustomErr != nil {
+		env.Code = e.CustomErr.Code
+		env.Message = scrub(e.CustomErr.Message)
+		env.Retryable = e.CustomErr.Retryable
+	}
+	env.Retry = e.retryHint()
+	env.TraceID = e.traceID
+	return env
+}
+
+// MarshalJSON encodes the AppError as the stable client-facing envelope written by WriteHTTP:
+// code, message, error_codes, data and retryable. Internal details such as the underlying error
+// and internal or restricted data are omitted; use SerializeAs(SerializerInternal) to keep the data
+func (e *AppError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.envelope(context.Background(), true))
+}
+
+// WriteHTTP writes the AppError as a JSON response using its HTTP code (500 when unset), extra
+// headers and Retry-After hint; the error is recorded and persisted when a recorder or store is
+// installed, and requests switched into debug output also get full diagnostics.
+// r may be nil
+func (e *AppError) WriteHTTP(w http.ResponseWriter, r *http.Request) {
+	e.recordResponse(r)
+	e.writeHeaders(w)
+	w.Header().Set(c.HeaderContentType, c.ContentTypeJSON)
+
+	status := e.httpCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+	env := e.envelope(ctx, true)
+	if debugRequested(r) {
+		env.Debug = e.debugInfo(r.Context())
+	}
+	e.writeLocalized(w, r, &env.Message)
+
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(env)
+}
+
+// ParseRetryAfter parses a Retry-After header value given either as delay seconds or as an HTTP
+// date relative to now; it reports false when the value is empty or malformed
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := at.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// WriteError writes err as the JSON error response: AppErrors anywhere in the chain are written with
+// WriteHTTP, any other error becomes a 500 Internal error so its text never reaches the client.
+// A nil err writes nothing; r may be nil
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	// Aggregated errors are written as a whole rather than as their first member
+	var joined *AppErrors
+	if errors.As(err, &joined) && joined != nil {
+		joined.WriteHTTP(w, r)
+		return
+	}
+
+	appErr, ok := asAppError(err)
+	if !ok {
+		ctx := context.Background()
+		if r != nil {
+			ctx = r.Context()
+		}
+		appErr = GetAppErr(ctx, err, InternalError, http.StatusInternalServerError)
+	}
+	appErr.WriteHTTP(w, r)
+}
+
+// recordResponse records, persists and observes for the endpoint the AppError about to be written
+// for the request, if any
+func (e *AppError) recordResponse(r *http.Request) {
+	if r == nil {
+		return
+	}
+	RecordError(r.Context(), e)
+	observeResponse(r.Context(), e)
+	if err := PersistError(r.Context(), e); err != nil {
+		getLogger().ErrorContext(r.Context(), "persisting error event failed", "error", err)
+	}
+}
+
+// writeHeaders copies the extra headers and the Retry-After hint into the response
+func (e *AppError) writeHeaders(w http.ResponseWriter) {
+	header := w.Header()
+	for key, values := range e.headers {
+		header[key] = append([]string(nil), values...)
+	}
+	if e.retryAfter > 0 {
+		header.Set(c.HeaderRetryAfter, formatRetryAfter(e.retryAfter))
+	}
+}
+
+// formatRetryAfter renders a duration as Retry-After delay seconds, rounded up
+func formatRetryAfter(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
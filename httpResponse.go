@@ -0,0 +1,165 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// maxResponseBodyBytes caps how much of a downstream error body FromHTTPResponse reads
+const maxResponseBodyBytes = 1 << 20
+
+// problemExtensionMembers are the extension members ToProblemDetails adds besides the data keys
+var problemExtensionMembers = map[string]struct{}{
+	"code": {}, "error_codes": {}, "retryable": {}, "error_id": {}, "retry": {}, "trace_id": {},
+}
+
+// responseBody holds the members of an error envelope or Problem Details document
+type responseBody struct {
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	ErrorCodes []string    `json:"error_codes"`
+	Data       interface{} `json:"data"`
+	Retryable  bool        `json:"retryable"`
+	Retry      *retryHint  `json:"retry"`
+	Category   Category    `json:"category"`
+	Detail     string      `json:"detail"`
+}
+
+// ResponseOption configures FromHTTPResponse
+type ResponseOption func(*responseOptions)
+
+// responseOptions holds the settings of FromHTTPResponse
+type responseOptions struct {
+	translator *Translator
+}
+
+// WithTranslator rewrites the reconstructed downstream error into this service's codes, see
+// Translator.Translate
+func WithTranslator(translator *Translator) ResponseOption {
+	return func(o *responseOptions) {
+		o.translator = translator
+	}
+}
+
+// FromHTTPResponse reconstructs the AppError a downstream service wrote with WriteHTTP or
+// WriteProblem, so errors propagate transparently across service hops: the code, message, error
+// codes, retryability, retry hints and client-facing data are taken from the body and the HTTP code
+// from the response status. Responses with another body fall back to CustomErrForStatus. The body is
+// restored for the caller, who still has to close it. It returns nil for responses below 400
+func FromHTTPResponse(ctx context.Context, resp *http.Response, respOpts ...ResponseOption) *AppError {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	o := &responseOptions{}
+	for _, opt := range respOpts {
+		opt(o)
+	}
+
+	customErr := CustomErrForStatus(resp.StatusCode)
+	opts := []Option{WithHTTPCode(resp.StatusCode), WithStackSkip(1)}
+
+	if body, ok := decodeResponseBody(resp); ok && body.Code != "" {
+		customErr = &CustomErr{
+			Code:      body.Code,
+			Message:   body.Message,
+			Retryable: body.Retryable,
+			Category:  body.Category,
+		}
+		if customErr.Category == "" {
+			customErr.Category = categoryForStatus(resp.StatusCode)
+		}
+		if len(body.ErrorCodes) > 0 {
+			opts = append(opts, WithErrorCodes(body.ErrorCodes...))
+		}
+		if body.Data != nil {
+			opts = append(opts, WithData(body.Data))
+		}
+		if hint := body.Retry; hint != nil {
+			opts = append(opts, WithRetryAfter(time.Duration(hint.AfterSeconds)*time.Second),
+				WithRetryMaxAttempts(hint.MaxAttempts), WithRetryBackoff(hint.Backoff))
+		}
+	}
+	if wait, ok := ParseRetryAfter(resp.Header.Get(c.HeaderRetryAfter), time.Now()); ok && wait > 0 {
+		opts = append(opts, WithRetryAfter(wait))
+	}
+
+	opts = append(opts, WithCustomErr(customErr))
+	appErr := New(ctx, responseError(resp, customErr.Code), opts...)
+	if o.translator != nil {
+		if translated, ok := o.translator.translate(ctx, appErr, 2); ok {
+			return translated
+		}
+	}
+	return appErr
+}
+
+// responseError describes the failed downstream call, without the query string of the URL
+func responseError(resp *http.Response, code string) error {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return fmt.Errorf("downstream responded %s (%s)", resp.Status, code)
+	}
+	u := *resp.Request.URL
+	u.RawQuery, u.Fragment = "", ""
+	return fmt.Errorf("%s %s: downstream responded %s (%s)", resp.Request.Method, u.Redacted(), resp.Status, code)
+}
+
+// decodeResponseBody decodes a JSON error envelope or Problem Details body, restoring the body
+func decodeResponseBody(resp *http.Response) (responseBody, bool) {
+	var body responseBody
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return body, false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get(c.HeaderContentType))
+	if err != nil || (mediaType != c.ContentTypeJSON && mediaType != c.ContentTypeProblemJSON) {
+		return body, false
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	resp.Body = &restoredBody{Reader: io.MultiReader(bytes.NewReader(raw), resp.Body), Closer: resp.Body}
+	if err != nil || json.Unmarshal(raw, &body) != nil {
+		return body, false
+	}
+	if mediaType == c.ContentTypeProblemJSON {
+		body.Message = body.Detail
+		body.Data = problemData(raw)
+	}
+	return body, true
+}
+
+// problemData rebuilds the client-facing data ToProblemDetails spread into extension members
+func problemData(raw []byte) interface{} {
+	var members map[string]interface{}
+	if json.Unmarshal(raw, &members) != nil {
+		return nil
+	}
+	if data, ok := members["data"]; ok {
+		return data
+	}
+
+	data := map[string]interface{}{}
+	for key, value := range members {
+		_, standard := problemMembers[key]
+		_, extension := problemExtensionMembers[key]
+		if !standard && !extension {
+			data[key] = value
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+// restoredBody replays the bytes already read before the rest of the original body
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
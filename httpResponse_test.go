@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// downstreamResponse returns the response write produces for a GET of /orders/1?token=x
+func downstreamResponse(write func(w http.ResponseWriter, r *http.Request)) *http.Response {
+	req := httptest.NewRequest(http.MethodGet, "http://inventory/orders/1?token=x", nil)
+	rec := httptest.NewRecorder()
+	write(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	ctx := context.Background()
+	busy := GetAppErr(ctx, errors.New("busy"), GetCustomErr("ERR_RSP_1", "try again", true, WithCategory(CategoryUnavailable)), 503,
+		map[string]interface{}{"shard": "s7"}).SetRetryAfter(2 * time.Second).SetMaxAttempts(4)
+	chain := Wrap(ctx, GetAppErr(ctx, errors.New("no rows"), GetCustomErr("ERR_RSP_2", "sku missing", false), 404),
+		GetCustomErr("ERR_RSP_3", "order not found", false), 404)
+
+	tests := []struct {
+		name           string
+		resp           *http.Response
+		wantNil        bool
+		wantCodes      []string
+		wantMsg        string
+		wantStatus     int
+		wantRetryable  bool
+		wantRetryAfter time.Duration
+		wantData       interface{}
+	}{
+		{"nil", nil, true, nil, "", 0, false, 0, nil},
+		{"success", downstreamResponse(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }),
+			true, nil, "", 0, false, 0, nil},
+		{"envelope", downstreamResponse(busy.WriteHTTP), false, []string{"ERR_RSP_1"}, "try again", 503, true, 2 * time.Second,
+			map[string]interface{}{"shard": "s7"}},
+		{"error codes", downstreamResponse(chain.WriteHTTP), false, []string{"ERR_RSP_2", "ERR_RSP_3"}, "order not found", 404, false, 0, nil},
+		{"problem details", downstreamResponse(busy.WriteProblem), false, []string{"ERR_RSP_1"}, "try again", 503, true, 2 * time.Second,
+			map[string]interface{}{"shard": "s7"}},
+		{"plain text", downstreamResponse(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set(c.HeaderRetryAfter, "5")
+			http.Error(w, "upstream overloaded", http.StatusServiceUnavailable)
+		}), false, []string{CustomErrForStatus(503).Code}, CustomErrForStatus(503).Message, 503, CustomErrForStatus(503).Retryable, 5 * time.Second, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := FromHTTPResponse(ctx, tt.resp)
+			if (appErr == nil) != tt.wantNil {
+				t.Fatalf("FromHTTPResponse = %v, want nil %v", appErr, tt.wantNil)
+			}
+			if appErr == nil {
+				return
+			}
+			if !reflect.DeepEqual(appErr.GetErrCodes(), tt.wantCodes) || appErr.GetMsg() != tt.wantMsg || appErr.GetHTTPCode() != tt.wantStatus {
+				t.Errorf("FromHTTPResponse = %v %q %d, want %v %q %d",
+					appErr.GetErrCodes(), appErr.GetMsg(), appErr.GetHTTPCode(), tt.wantCodes, tt.wantMsg, tt.wantStatus)
+			}
+			if appErr.IsRetryable() != tt.wantRetryable || appErr.GetRetryAfter() != tt.wantRetryAfter {
+				t.Errorf("retryable=%v after %v, want %v after %v", appErr.IsRetryable(), appErr.GetRetryAfter(), tt.wantRetryable, tt.wantRetryAfter)
+			}
+			if !reflect.DeepEqual(appErr.GetData(), tt.wantData) {
+				t.Errorf("GetData = %#v, want %#v", appErr.GetData(), tt.wantData)
+			}
+			if msg := appErr.ActualErr.Error(); !strings.HasPrefix(msg, "GET http://inventory/orders/1: ") {
+				t.Errorf("underlying error %q, want the request without its query", msg)
+			}
+
+			// The body is restored for the caller
+			if body, err := io.ReadAll(tt.resp.Body); err != nil || len(body) == 0 {
+				t.Errorf("body after decoding = %q, %v, want it restored", body, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	ctx := context.Background()
+	notFound := GetCustomErr("ERR_JSN_1", "order not found", false)
+	busy := GetCustomErr("ERR_JSN_2", "try again", true)
+
+	tests := []struct {
+		name string
+		err  *AppError
+		want string
+	}{
+		{"minimal", GetAppErr(ctx, errors.New("no rows"), notFound, 404),
+			`{"code":"ERR_JSN_1","message":"order not found","error_codes":["ERR_JSN_1"],"retryable":false}`},
+		{"data", GetAppErr(ctx, errors.New("no rows"), notFound, 404, map[string]interface{}{"order": "o1", "password": "hunter2"}),
+			`{"code":"ERR_JSN_1","message":"order not found","error_codes":["ERR_JSN_1"],"data":{"order":"o1","password":"[REDACTED]"},"retryable":false}`},
+		{"retry hint", GetAppErr(ctx, errors.New("busy"), busy, 503).SetRetryAfter(1500 * time.Millisecond),
+			`{"code":"ERR_JSN_2","message":"try again","error_codes":["ERR_JSN_2"],"retryable":true,"retry":{"after_seconds":2}}`},
+		{"retry hint of permanent error", GetAppErr(ctx, errors.New("gone"), notFound, 404).SetRetryAfter(time.Second),
+			`{"code":"ERR_JSN_1","message":"order not found","error_codes":["ERR_JSN_1"],"retryable":false}`},
+		{"trace ID", GetAppErr(ctx, errors.New("no rows"), notFound, 404).SetTraceIDs("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"),
+			`{"code":"ERR_JSN_1","message":"order not found","error_codes":["ERR_JSN_1"],"retryable":false,"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"}`},
+		{"chain", Wrap(ctx, GetAppErr(ctx, errors.New("no rows"), notFound, 404), busy, 503),
+			`{"code":"ERR_JSN_2","message":"try again","error_codes":["ERR_JSN_1","ERR_JSN_2"],"retryable":true}`},
+		{"without custom error", &AppError{ActualErr: errors.New("bare")},
+			`{"code":"","message":"","error_codes":[],"retryable":false}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.err)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("json.Marshal =\n%s\nwant\n%s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	ctx := context.Background()
+	notFound := GetCustomErr("ERR_WRT_1", "order not found", false)
+	busy := GetCustomErr("ERR_WRT_2", "try again", true)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantHeader http.Header
+	}{
+		{"nil", nil, http.StatusOK, "", nil},
+		{"plain error", errors.New("pq: password authentication failed"), http.StatusInternalServerError, InternalError.Code, nil},
+		{"app error", GetAppErr(ctx, errors.New("no rows"), notFound, 404), http.StatusNotFound, "ERR_WRT_1", nil},
+		{"unset status", GetAppErr(ctx, errors.New("no rows"), notFound, 0), http.StatusInternalServerError, "ERR_WRT_1", nil},
+		{"wrapped by fmt", fmt.Errorf("handler: %w", GetAppErr(ctx, errors.New("no rows"), notFound, 404)), http.StatusNotFound, "ERR_WRT_1", nil},
+		{"headers and retry after", GetAppErr(ctx, errors.New("busy"), busy, 503).SetRetryAfter(2*time.Second).SetHeader("X-Shard", "7"),
+			http.StatusServiceUnavailable, "ERR_WRT_2", http.Header{c.HeaderRetryAfter: {"2"}, "X-Shard": {"7"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			for key, values := range tt.wantHeader {
+				if got := rec.Header().Values(key); strings.Join(got, ",") != strings.Join(values, ",") {
+					t.Errorf("header %s = %v, want %v", key, got, values)
+				}
+			}
+			if tt.err == nil {
+				if rec.Body.Len() != 0 {
+					t.Errorf("body = %q, want none", rec.Body.String())
+				}
+				return
+			}
+
+			var env map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+			}
+			if env["code"] != tt.wantCode || rec.Header().Get(c.HeaderContentType) != c.ContentTypeJSON {
+				t.Errorf("code = %v, content type %q, want %s", env["code"], rec.Header().Get(c.HeaderContentType), tt.wantCode)
+			}
+			if strings.Contains(rec.Body.String(), "password") {
+				t.Errorf("body %s leaks the underlying error", rec.Body.String())
+			}
+		})
+	}
+}
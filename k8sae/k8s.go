@@ -0,0 +1,136 @@
+package k8sae
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Custom errors Kubernetes API failures map to
+var (
+	ErrNotFound = ae.GetCustomErr("ERR_K8S_NOT_FOUND", "kubernetes resource not found", false,
+		ae.WithCategory(ae.CategoryNotFound))
+	ErrAlreadyExists = ae.GetCustomErr("ERR_K8S_ALREADY_EXISTS", "kubernetes resource already exists", false,
+		ae.WithCategory(ae.CategoryConflict))
+	ErrConflict = ae.GetCustomErr("ERR_K8S_CONFLICT", "kubernetes resource was modified, please retry", true,
+		ae.WithCategory(ae.CategoryConflict))
+	ErrInvalid = ae.GetCustomErr("ERR_K8S_INVALID", "kubernetes resource is invalid", false,
+		ae.WithCategory(ae.CategoryValidation), ae.WithDefaultHTTPCode(http.StatusUnprocessableEntity))
+	ErrBadRequest = ae.GetCustomErr("ERR_K8S_BAD_REQUEST", "kubernetes rejected the request", false,
+		ae.WithCategory(ae.CategoryValidation))
+	ErrUnauthorized = ae.GetCustomErr("ERR_K8S_UNAUTHORIZED", "kubernetes credentials are invalid", false,
+		ae.WithCategory(ae.CategoryAuth))
+	ErrForbidden = ae.GetCustomErr("ERR_K8S_FORBIDDEN", "kubernetes access forbidden", false,
+		ae.WithCategory(ae.CategoryForbidden))
+	ErrTooManyRequests = ae.GetCustomErr("ERR_K8S_TOO_MANY_REQUESTS", "kubernetes api is throttling requests", true,
+		ae.WithCategory(ae.CategoryRateLimited))
+	ErrExpired = ae.GetCustomErr("ERR_K8S_EXPIRED", "kubernetes resource version expired", true,
+		ae.WithDefaultHTTPCode(http.StatusGone))
+	ErrTimeout = ae.GetCustomErr("ERR_K8S_TIMEOUT", "kubernetes api timed out", true,
+		ae.WithCategory(ae.CategoryTimeout))
+	ErrUnavailable = ae.GetCustomErr("ERR_K8S_UNAVAILABLE", "kubernetes api is unavailable", true,
+		ae.WithCategory(ae.CategoryUnavailable))
+	ErrUnknown = ae.GetCustomErr("ERR_K8S_UNKNOWN", "kubernetes request failed", false,
+		ae.WithCategory(ae.CategoryUpstream))
+)
+
+// FromK8sError converts an error returned by client-go into an AppError so operators and controllers
+// get uniform error semantics: not found becomes a 404, conflicts a 409 (retryable after a re-read),
+// throttling a retryable 429 honoring the suggested delay, forbidden a 403 and invalid objects a 422
+// carrying the field causes, see Classify. The kind, name, group and reason of the status are
+// recorded in the data. nil stays nil and AppErrors are returned unchanged
+func FromK8sError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	opts := []ae.Option{ae.WithCustomErr(Classify(err)), ae.WithStackSkip(1)}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		opts = append(opts, ae.WithRetryAfter(time.Duration(seconds)*time.Second))
+	}
+	if data := Data(err); len(data) > 0 {
+		opts = append(opts, ae.WithData(data))
+	}
+	return ae.New(ctx, err, opts...)
+}
+
+// Classify returns the custom error a Kubernetes API error maps to
+func Classify(err error) *ae.CustomErr {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ae.ClientClosedRequest
+	case apierrors.IsNotFound(err):
+		return ErrNotFound
+	case apierrors.IsAlreadyExists(err):
+		return ErrAlreadyExists
+	case apierrors.IsConflict(err):
+		return ErrConflict
+	case apierrors.IsInvalid(err):
+		return ErrInvalid
+	case apierrors.IsBadRequest(err):
+		return ErrBadRequest
+	case apierrors.IsUnauthorized(err):
+		return ErrUnauthorized
+	case apierrors.IsForbidden(err):
+		return ErrForbidden
+	case apierrors.IsTooManyRequests(err):
+		return ErrTooManyRequests
+	case apierrors.IsResourceExpired(err), apierrors.IsGone(err):
+		return ErrExpired
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout
+	case apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err), apierrors.IsUnexpectedServerError(err):
+		return ErrUnavailable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrTimeout
+		}
+		return ErrUnavailable
+	}
+	return ErrUnknown
+}
+
+// Data returns the details of a Kubernetes API status recorded in the AppError data: "kind",
+// "name", "group" and "reason", each only when known, and the field "causes" of invalid objects
+func Data(err error) map[string]interface{} {
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		return nil
+	}
+	status := apiStatus.Status()
+
+	data := map[string]interface{}{}
+	if status.Reason != "" {
+		data["reason"] = string(status.Reason)
+	}
+	details := status.Details
+	if details == nil {
+		return data
+	}
+	for key, value := range map[string]string{"kind": details.Kind, "name": details.Name, "group": details.Group} {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	if len(details.Causes) > 0 {
+		causes := make([]ae.FieldViolation, 0, len(details.Causes))
+		for _, cause := range details.Causes {
+			causes = append(causes, ae.FieldViolation{Field: cause.Field, Message: cause.Message, Tag: string(cause.Type)})
+		}
+		data["causes"] = causes
+	}
+	return data
+}
@@ -0,0 +1,108 @@
+package k8sae
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+var (
+	deployments = schema.GroupResource{Group: "apps", Resource: "deployments"}
+	invalid     = apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web",
+		field.ErrorList{field.Required(field.NewPath("spec", "replicas"), "")})
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *ae.CustomErr
+	}{
+		{"not found", apierrors.NewNotFound(deployments, "web"), ErrNotFound},
+		{"already exists", apierrors.NewAlreadyExists(deployments, "web"), ErrAlreadyExists},
+		{"conflict", apierrors.NewConflict(deployments, "web", errors.New("object was modified")), ErrConflict},
+		{"invalid", invalid, ErrInvalid},
+		{"bad request", apierrors.NewBadRequest("malformed patch"), ErrBadRequest},
+		{"unauthorized", apierrors.NewUnauthorized("token expired"), ErrUnauthorized},
+		{"forbidden", apierrors.NewForbidden(deployments, "web", errors.New("rbac")), ErrForbidden},
+		{"too many requests", apierrors.NewTooManyRequests("slow down", 5), ErrTooManyRequests},
+		{"resource expired", apierrors.NewResourceExpired("too old resource version"), ErrExpired},
+		{"gone", apierrors.NewGone("gone"), ErrExpired},
+		{"server timeout", apierrors.NewServerTimeout(deployments, "list", 2), ErrTimeout},
+		{"timeout", apierrors.NewTimeoutError("watch timed out", 1), ErrTimeout},
+		{"service unavailable", apierrors.NewServiceUnavailable("etcd down"), ErrUnavailable},
+		{"internal error", apierrors.NewInternalError(errors.New("panic")), ErrUnavailable},
+		{"context canceled", context.Canceled, ae.ClientClosedRequest},
+		{"deadline", context.DeadlineExceeded, ErrTimeout},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrUnavailable},
+		{"other error", errors.New("boom"), ErrUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromK8sError(t *testing.T) {
+	existing := ae.GetAppErr(context.Background(), errors.New("gone"), ErrNotFound, 0)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+		wantAfter     time.Duration
+		wantData      interface{}
+	}{
+		{"not found", apierrors.NewNotFound(deployments, "web"), "ERR_K8S_NOT_FOUND", http.StatusNotFound, false, 0,
+			map[string]interface{}{"reason": "NotFound", "kind": "deployments", "name": "web", "group": "apps"}},
+		{"conflict", apierrors.NewConflict(deployments, "web", errors.New("object was modified")), "ERR_K8S_CONFLICT", http.StatusConflict, true, 0,
+			map[string]interface{}{"reason": "Conflict", "kind": "deployments", "name": "web", "group": "apps"}},
+		{"too many requests", apierrors.NewTooManyRequests("slow down", 5), "ERR_K8S_TOO_MANY_REQUESTS", http.StatusTooManyRequests, true, 5 * time.Second,
+			map[string]interface{}{"reason": "TooManyRequests"}},
+		{"forbidden", apierrors.NewForbidden(deployments, "web", errors.New("rbac")), "ERR_K8S_FORBIDDEN", http.StatusForbidden, false, 0,
+			map[string]interface{}{"reason": "Forbidden", "kind": "deployments", "name": "web", "group": "apps"}},
+		{"invalid", invalid, "ERR_K8S_INVALID", http.StatusUnprocessableEntity, false, 0,
+			map[string]interface{}{"reason": "Invalid", "kind": "Deployment", "name": "web", "group": "apps",
+				"causes": []ae.FieldViolation{{Field: "spec.replicas", Message: "Required value", Tag: "FieldValueRequired"}}}},
+		{"no status", errors.New("boom"), "ERR_K8S_UNKNOWN", http.StatusBadGateway, false, 0, nil},
+		{"AppError", existing, "ERR_K8S_NOT_FOUND", http.StatusNotFound, false, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromK8sError(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("FromK8sError = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromK8sError = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+			if appErr.GetRetryAfter() != tt.wantAfter {
+				t.Errorf("GetRetryAfter = %v, want %v", appErr.GetRetryAfter(), tt.wantAfter)
+			}
+			if !reflect.DeepEqual(appErr.GetData(), tt.wantData) {
+				t.Errorf("GetData = %#v, want %#v", appErr.GetData(), tt.wantData)
+			}
+		})
+	}
+
+	if err := FromK8sError(context.Background(), nil); err != nil {
+		t.Errorf("FromK8sError(nil) = %v, want nil", err)
+	}
+}
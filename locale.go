@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// locales holds the registered message bundles keyed by normalized locale, then by code
+var locales = struct {
+	sync.RWMutex
+	bundles map[string]map[string]string
+}{bundles: map[string]map[string]string{}}
+
+// RegisterLocaleBundle adds messages keyed by error code for a locale such as "de" or "pt-BR",
+// merging with messages registered earlier for the same locale
+func RegisterLocaleBundle(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+
+	locales.Lock()
+	defer locales.Unlock()
+
+	bundle, ok := locales.bundles[locale]
+	if !ok {
+		bundle = make(map[string]string, len(messages))
+		locales.bundles[locale] = bundle
+	}
+	for code, msg := range messages {
+		bundle[code] = msg
+	}
+}
+
+// LocaleMessage returns the message registered for a code in the given locale
+func LocaleMessage(locale, code string) (string, bool) {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	msg, ok := locales.bundles[normalizeLocale(locale)][code]
+	return msg, ok
+}
+
+// LocalizedMsg returns the message of the error in the given language, falling back from a regional
+// locale such as "pt-BR" to its base language "pt" and then to the default message
+func (e *AppError) LocalizedMsg(lang string) string {
+	if msg, _, ok := e.localize(lang); ok {
+		return msg
+	}
+	if e.CustomErr == nil {
+		return ""
+	}
+	return e.CustomErr.Message
+}
+
+// localize returns the registered message of the error for lang or its base language, along with
+// the locale it was found in
+func (e *AppError) localize(lang string) (string, string, bool) {
+	if e.CustomErr == nil || lang == "" {
+		return "", "", false
+	}
+	lang = normalizeLocale(lang)
+	if msg, ok := LocaleMessage(lang, e.CustomErr.Code); ok {
+		return msg, lang, true
+	}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		if msg, ok := LocaleMessage(base, e.CustomErr.Code); ok {
+			return msg, base, true
+		}
+	}
+	return "", "", false
+}
+
+// localizeRequest picks the message for the most preferred language of the request's
+// Accept-Language header that has one registered
+func (e *AppError) localizeRequest(r *http.Request) (string, string, bool) {
+	if r == nil {
+		return "", "", false
+	}
+	for _, lang := range acceptedLanguages(r.Header.Get(c.HeaderAcceptLanguage)) {
+		if msg, locale, ok := e.localize(lang); ok {
+			return msg, locale, true
+		}
+	}
+	return "", "", false
+}
+
+// acceptedLanguages parses an Accept-Language header into its languages, most preferred first;
+// wildcards and languages with q=0 are left out
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			langs = append(langs, weighted{lang: lang, q: q})
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	result := make([]string, len(langs))
+	for i, l := range langs {
+		result[i] = l.lang
+	}
+	return result
+}
+
+// writeLocalized replaces msg with the translation for the request's Accept-Language, setting
+// Content-Language, and marks the response as varying by language
+func (e *AppError) writeLocalized(w http.ResponseWriter, r *http.Request, msg *string) {
+	if r == nil || r.Header.Get(c.HeaderAcceptLanguage) == "" {
+		return
+	}
+	w.Header().Add(c.HeaderVary, c.HeaderAcceptLanguage)
+	if localized, locale, ok := e.localizeRequest(r); ok {
+		*msg = scrub(localized)
+		w.Header().Set(c.HeaderContentLanguage, locale)
+	}
+}
+
+// normalizeLocale lower-cases a locale and uses "-" as separator, e.g. "pt_BR" becomes "pt-br"
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
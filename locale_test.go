@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestAcceptedLanguages(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{"empty", "", []string{}},
+		{"single", "de", []string{"de"}},
+		{"weighted", "en;q=0.5, de, fr;q=0.8", []string{"de", "fr", "en"}},
+		{"stable for equal weights", "pt-BR, pt, en;q=0.1", []string{"pt-BR", "pt", "en"}},
+		{"wildcard and q=0 dropped", "*, de;q=0, fr", []string{"fr"}},
+		{"malformed weight dropped", "de;q=high, fr;q=0.3", []string{"fr"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptedLanguages(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("acceptedLanguages(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizedMsg(t *testing.T) {
+	RegisterLocaleBundle("de", map[string]string{"ERR_LOC_1": "Bestellung nicht gefunden"})
+	RegisterLocaleBundle("pt_BR", map[string]string{"ERR_LOC_1": "Pedido não encontrado"})
+	RegisterLocaleBundle("pt", map[string]string{"ERR_LOC_1": "Encomenda não encontrada"})
+	appErr := GetAppErr(context.Background(), errors.New("no rows"), GetCustomErr("ERR_LOC_1", "order not found", false), 404)
+
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"de", "Bestellung nicht gefunden"},
+		{"DE", "Bestellung nicht gefunden"},
+		{"de-AT", "Bestellung nicht gefunden"},
+		{"pt-BR", "Pedido não encontrado"},
+		{"pt_br", "Pedido não encontrado"},
+		{"pt-PT", "Encomenda não encontrada"},
+		{"fr", "order not found"},
+		{"", "order not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := appErr.LocalizedMsg(tt.lang); got != tt.want {
+				t.Errorf("LocalizedMsg(%q) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteHTTPLocalized(t *testing.T) {
+	RegisterLocaleBundle("es", map[string]string{"ERR_LOC_2": "Pedido no encontrado"})
+
+	tests := []struct {
+		name         string
+		header       string
+		wantMsg      string
+		wantLanguage string
+		wantVary     bool
+	}{
+		{"no header", "", "order not found", "", false},
+		{"registered", "fr;q=0.9, es", "Pedido no encontrado", "es", true},
+		{"regional", "es-MX", "Pedido no encontrado", "es", true},
+		{"unregistered", "fr", "order not found", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+			if tt.header != "" {
+				req.Header.Set(c.HeaderAcceptLanguage, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			GetAppErr(context.Background(), errors.New("no rows"), GetCustomErr("ERR_LOC_2", "order not found", false), 404).WriteHTTP(rec, req)
+
+			var body struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding body %q: %v", rec.Body.String(), err)
+			}
+			if body.Message != tt.wantMsg {
+				t.Errorf("message = %q, want %q", body.Message, tt.wantMsg)
+			}
+			if got := rec.Header().Get(c.HeaderContentLanguage); got != tt.wantLanguage {
+				t.Errorf("Content-Language = %q, want %q", got, tt.wantLanguage)
+			}
+			if got := rec.Header().Get(c.HeaderVary) == c.HeaderAcceptLanguage; got != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Language %v", rec.Header().Get(c.HeaderVary), tt.wantVary)
+			}
+		})
+	}
+}
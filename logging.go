@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync/atomic"
+)
+
+// logger is the logger used by the package's own helpers, slog.Default() when unset
+var logger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the logger used by the package's own helpers (e.g. Go); nil restores slog.Default()
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// getLogger returns the logger used by the package's own helpers
+func getLogger() *slog.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// logError logs an AppError together with the trace identifiers stored in ctx
+func logError(ctx context.Context, msg string, appErr *AppError) {
+	attrs := appErrorAttrs(appErr)
+	if identifiers, ok := identifierAttr(ctx, appErr); ok {
+		attrs = append(attrs, identifiers)
+	}
+	getLogger().LogAttrs(ctx, appErr.GetSeverity().Level(), msg, attrs...)
+}
+
+// appErrorAttrs returns the structured attributes describing an AppError
+func appErrorAttrs(appErr *AppError) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("code", primaryCode(appErr)),
+		slog.String("error_id", appErr.GetID()),
+		slog.Int("http_code", appErr.httpCode),
+		slog.String("severity", appErr.GetSeverity().String()),
+		slog.String("error", scrub(appErr.Error())),
+	}
+	if appErr.CustomErr != nil {
+		attrs = append(attrs,
+			slog.String("message", scrub(appErr.CustomErr.Message)),
+			slog.Bool("retryable", appErr.CustomErr.Retryable))
+	}
+	attrs = append(attrs, slog.String("fingerprint", appErr.Fingerprint()))
+	if appErr.traceID != "" {
+		attrs = append(attrs, slog.String("trace_id", appErr.traceID), slog.String("span_id", appErr.spanID))
+	}
+	if appErr.internalMsg != "" {
+		attrs = append(attrs, slog.String("internal_message", scrub(appErr.internalMsg)))
+	}
+	if category := appErr.GetCategory(); category != "" {
+		attrs = append(attrs, slog.String("category", string(category)))
+	}
+	if len(appErr.ErrorCodes) > 0 {
+		attrs = append(attrs, slog.Any("error_codes", appErr.ErrorCodes))
+	}
+	return attrs
+}
+
+// SlogAttrs returns the structured attributes describing err: for an AppError in the chain its
+// code, error ID, HTTP code, severity, message, retryability, error codes, data and stack trace,
+// and the error text for any other error
+func SlogAttrs(err error) []slog.Attr {
+	if err == nil {
+		return nil
+	}
+	appErr, ok := asAppError(err)
+	if !ok {
+		return []slog.Attr{slog.String("error", scrub(err.Error()))}
+	}
+
+	attrs := appErrorAttrs(appErr)
+	if appErr.data != nil {
+		attrs = append(attrs, slog.Any("data", encodeData(context.Background(), appErr.data, false)))
+	}
+	if frames := appErr.GetStackTrace(); len(frames) > 0 {
+		stack := make([]string, len(frames))
+		for i, frame := range frames {
+			stack[i] = frame.String()
+		}
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+	return attrs
+}
+
+// LogValue implements slog.LogValuer, so slog.Error("failed", "err", appErr) logs the error as a
+// group of the attributes returned by SlogAttrs
+func (e *AppError) LogValue() slog.Value {
+	return slog.GroupValue(SlogAttrs(e)...)
+}
+
+// identifierAttr returns the trace identifiers stored in ctx as an "identifiers" group, falling
+// back to the identifiers the AppError was created with when ctx carries none
+func identifierAttr(ctx context.Context, appErr *AppError) (slog.Attr, bool) {
+	ids := GetIdentifiers(ctx)
+	if len(ids) == 0 && appErr != nil {
+		ids = appErr.identifiers
+	}
+	if len(ids) == 0 {
+		return slog.Attr{}, false
+	}
+
+	keys := make([]string, 0, len(ids))
+	for k := range ids {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	identifiers := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		identifiers = append(identifiers, slog.Any(k, ids[k]))
+	}
+	return slog.Group("identifiers", identifiers...), true
+}
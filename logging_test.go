@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// attrKeys returns the sorted keys of attrs
+func attrKeys(attrs []slog.Attr) []string {
+	keys := make([]string, len(attrs))
+	for i, attr := range attrs {
+		keys[i] = attr.Key
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSlogAttrs(t *testing.T) {
+	ctx := context.Background()
+	busy := GetCustomErr("ERR_SLG_1", "try again", true, WithCategory(CategoryUnavailable))
+	base := []string{"category", "code", "error", "error_codes", "error_id", "fingerprint", "http_code", "message", "retryable", "severity", "stack"}
+
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"plain error", errors.New("boom"), []string{"error"}},
+		{"AppError", GetAppErr(ctx, errors.New("busy"), busy, 503), base},
+		{"wrapped AppError", fmt.Errorf("handler: %w", GetAppErr(ctx, errors.New("busy"), busy, 503)), base},
+		{"data", GetAppErr(ctx, errors.New("busy"), busy, 503, map[string]string{"order": "o1"}),
+			append([]string{"data"}, base...)},
+		{"internal message and trace IDs",
+			GetAppErr(ctx, errors.New("busy"), busy, 503).SetInternalMsg("shard 7").SetTraceIDs("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"),
+			append([]string{"internal_message", "span_id", "trace_id"}, base...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := SlogAttrs(tt.err)
+			if tt.want == nil {
+				if attrs != nil {
+					t.Errorf("SlogAttrs = %v, want nil", attrs)
+				}
+				return
+			}
+			want := append([]string{}, tt.want...)
+			sort.Strings(want)
+			if got := attrKeys(attrs); !reflect.DeepEqual(got, want) {
+				t.Errorf("SlogAttrs keys = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("busy"), GetCustomErr("ERR_SLG_2", "try again", true), 503,
+		map[string]string{"password": "hunter2"})
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", appErr)
+	var record struct {
+		Err map[string]interface{} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decoding %q: %v", buf.String(), err)
+	}
+
+	tests := []struct {
+		key  string
+		want interface{}
+	}{
+		{"code", "ERR_SLG_2"},
+		{"http_code", float64(503)},
+		{"retryable", true},
+		{"message", "try again"},
+		{"error_codes", []interface{}{"ERR_SLG_2"}},
+		{"data", map[string]interface{}{"password": redacted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := record.Err[tt.key]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("err.%s = %#v, want %#v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package logrusae
+
+import (
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Fields returns logrus fields describing err with the attributes of ae.SlogAttrs (code,
+// error_id, http_code, error_codes, data, stack, ...), e.g.
+// log.WithFields(logrusae.Fields(err)).Error("order failed")
+func Fields(err error) logrus.Fields {
+	return logrus.Fields(attrMap(ae.SlogAttrs(err)))
+}
+
+// attrMap converts slog attributes into a map, nesting groups
+func attrMap(attrs []slog.Attr) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+	for _, a := range attrs {
+		value := a.Value.Resolve()
+		if value.Kind() == slog.KindGroup {
+			m[a.Key] = attrMap(value.Group())
+			continue
+		}
+		m[a.Key] = value.Any()
+	}
+	return m
+}
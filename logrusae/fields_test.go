@@ -0,0 +1,57 @@
+package logrusae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+func TestFields(t *testing.T) {
+	ctx := context.Background()
+	busy := ae.GetCustomErr("ERR_LGR_1", "try again", true)
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]interface{} // Subset of the fields
+	}{
+		{"nil", nil, map[string]interface{}{}},
+		{"plain error", errors.New("boom"), map[string]interface{}{"error": "boom"}},
+		{"AppError", ae.GetAppErr(ctx, errors.New("busy"), busy, 503),
+			map[string]interface{}{"code": "ERR_LGR_1", "http_code": int64(503), "retryable": true, "error_codes": []string{"ERR_LGR_1"}}},
+		{"wrapped AppError", fmt.Errorf("handler: %w", ae.GetAppErr(ctx, errors.New("busy"), busy, 503)),
+			map[string]interface{}{"code": "ERR_LGR_1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := Fields(tt.err)
+			if len(tt.want) == 0 && len(fields) != 0 {
+				t.Errorf("Fields = %v, want none", fields)
+			}
+			for key, want := range tt.want {
+				if got := fields[key]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAttrMapNestsGroups(t *testing.T) {
+	got := attrMap([]slog.Attr{
+		slog.String("code", "ERR_LGR_2"),
+		slog.Group("identifiers", slog.String("user_id", "u1"), slog.Group("order", slog.Int("id", 7))),
+	})
+	want := map[string]interface{}{
+		"code":        "ERR_LGR_2",
+		"identifiers": map[string]interface{}{"user_id": "u1", "order": map[string]interface{}{"id": int64(7)}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attrMap = %#v, want %#v", got, want)
+	}
+}
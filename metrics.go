@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ErrorMetric describes a created AppError with the dimensions used by error-rate metrics
+type ErrorMetric struct {
+	Code      string            // Primary error code
+	Category  Category          // Category of the error, empty when unset
+	HTTPCode  int               // HTTP status code of the error
+	Retryable bool              // Whether the error condition can be retried
+	Labels    map[string]string // Labels of the error, e.g. the tenant
+}
+
+// MetricsHook is invoked once for every AppError created by GetAppErr, New and Wrap
+type MetricsHook func(ctx context.Context, metric ErrorMetric)
+
+// metricsHook receives the metrics of created AppErrors when set
+var metricsHook atomic.Pointer[MetricsHook]
+
+// SetMetricsHook installs the hook receiving the metrics of every created AppError, e.g. the one
+// of promae.Collector; nil disables it
+func SetMetricsHook(hook MetricsHook) {
+	if hook == nil {
+		metricsHook.Store(nil)
+		return
+	}
+	metricsHook.Store(&hook)
+}
+
+// recordMetrics passes the metrics of a newly created AppError to the installed hook
+func recordMetrics(ctx context.Context, appErr *AppError) {
+	hook := metricsHook.Load()
+	if hook == nil {
+		return
+	}
+	(*hook)(ctx, ErrorMetric{
+		Code:      primaryCode(appErr),
+		Category:  appErr.GetCategory(),
+		HTTPCode:  appErr.httpCode,
+		Retryable: appErr.CustomErr != nil && appErr.CustomErr.Retryable,
+		Labels:    appErr.GetLabels(),
+	})
+}
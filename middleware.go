@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"bufio"
+	"log/slog"
+	"net"
+	"net/http"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// TraceMiddleware seeds a TraceMeta into every request context so AddTraceLog and GetAppErr record
+// the request's errors out of the box. The request ID is taken from the X-Request-ID header, or
+// generated when absent, stored as the "request_id" identifier and echoed in the response header.
+// Requests whose context already carries a TraceMeta are passed through unchanged
+func TraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := TraceFromContext(r.Context()); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestID := r.Header.Get(c.HeaderRequestID)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newErrorID()
+		}
+		w.Header().Set(c.HeaderRequestID, requestID)
+
+		ctx := ContextWithTrace(r.Context())
+		if traceID, spanID, ok := parseTraceParent(r.Header.Get(c.HeaderTraceParent)); ok {
+			ctx = ContextWithTraceContext(ctx, traceID, spanID)
+		}
+		traceMeta, _ := TraceFromContext(ctx)
+		traceMeta.Trace = append(traceMeta.Trace, newTraceEntry(slog.LevelInfo, r.Method+" "+r.URL.Path, 0))
+		traceMeta.IdentifierMappings[c.RequestIDIdentifier] = requestID
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Recoverer converts panics in next into AppErrors (see FromPanic), logs them with the request's
+// trace identifiers and writes the 500 JSON response unless the handler already started one.
+// http.ErrAbortHandler is re-panicked so net/http can abort the response
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &trackingWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			appErr := FromPanic(r.Context(), recovered)
+			logError(r.Context(), "panic recovered", appErr)
+			if !rw.wroteHeader {
+				appErr.WriteHTTP(w, r)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// trackingWriter records whether a response has been started
+type trackingWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *trackingWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client, so streaming handlers can still type assert http.Flusher
+func (w *trackingWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack lets the handler take over the connection, e.g. for websockets; a hijacked response counts
+// as started since no error response can be written anymore
+func (w *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, buf, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, buf, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
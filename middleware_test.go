@@ -0,0 +1,142 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestTraceMiddleware(t *testing.T) {
+	existing := NewTraceMeta()
+
+	tests := []struct {
+		name          string
+		requestID     string
+		traceParent   string
+		ctx           context.Context
+		wantRequestID string // Empty when a generated ID is expected
+		wantTraceID   string
+		wantExisting  bool
+	}{
+		{"generated request ID", "", "", context.Background(), "", "", false},
+		{"given request ID", "req-1", "", context.Background(), "req-1", "", false},
+		{"oversized request ID", strings.Repeat("x", 129), "", context.Background(), "", "", false},
+		{"traceparent", "req-2", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", context.Background(),
+			"req-2", "4bf92f3577b34da6a3ce929d0e0e4736", false},
+		{"malformed traceparent", "req-3", "00-xyz-01", context.Background(), "req-3", "", false},
+		{"existing trace", "req-4", "", context.WithValue(context.Background(), c.TraceMetaKey, existing), "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var traceMeta *TraceMeta
+			var traceID string
+			handler := TraceMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				traceMeta, _ = TraceFromContext(r.Context())
+				traceID, _, _ = TraceContextFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil).WithContext(tt.ctx)
+			if tt.requestID != "" {
+				req.Header.Set(c.HeaderRequestID, tt.requestID)
+			}
+			if tt.traceParent != "" {
+				req.Header.Set(c.HeaderTraceParent, tt.traceParent)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.wantExisting {
+				if traceMeta != existing || rec.Header().Get(c.HeaderRequestID) != "" {
+					t.Error("a request already carrying a TraceMeta must pass through unchanged")
+				}
+				return
+			}
+			echoed := rec.Header().Get(c.HeaderRequestID)
+			if echoed == "" || (tt.wantRequestID != "" && echoed != tt.wantRequestID) || (tt.wantRequestID == "" && echoed == tt.requestID) {
+				t.Errorf("echoed request ID %q, want %q", echoed, tt.wantRequestID)
+			}
+			if traceMeta == nil || traceMeta.IdentifierMappings[c.RequestIDIdentifier] != echoed {
+				t.Fatalf("TraceMeta %+v lacks the request ID %q", traceMeta, echoed)
+			}
+			if got := traceMeta.TraceLines(); !reflect.DeepEqual(got, []string{"GET /orders"}) {
+				t.Errorf("trace = %q, want the request line", got)
+			}
+			if traceID != tt.wantTraceID {
+				t.Errorf("trace ID = %q, want %q", traceID, tt.wantTraceID)
+			}
+		})
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer SetLogger(nil)
+	custom := GetCustomErr("ERR_RCV_1", "crashed", false)
+
+	tests := []struct {
+		name       string
+		customErr  *CustomErr
+		handler    http.HandlerFunc
+		wantStatus int
+		wantCode   string // Code of the JSON error body, empty when the handler's own response is kept
+	}{
+		{"no panic", nil, func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusAccepted) }, http.StatusAccepted, ""},
+		{"panic", nil, func(http.ResponseWriter, *http.Request) { panic("boom") }, http.StatusInternalServerError, PanicRecovered.Code},
+		{"configured custom error", custom, func(http.ResponseWriter, *http.Request) { panic("boom") }, http.StatusInternalServerError, "ERR_RCV_1"},
+		{"response already started", nil, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("boom")
+		}, http.StatusOK, ""},
+		{"flushed response", nil, func(w http.ResponseWriter, _ *http.Request) {
+			w.(http.Flusher).Flush()
+			panic("boom")
+		}, http.StatusOK, ""},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.PanicCustomErr = tt.customErr
+			SetConfig(cfg)
+
+			rec := httptest.NewRecorder()
+			Recoverer(tt.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantCode == "" {
+				if rec.Body.Len() != 0 {
+					t.Errorf("body = %q, want the handler's response only", rec.Body.String())
+				}
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+			}
+			if body["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s", body["code"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRecovererAbortHandler(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler re-panicked", recovered)
+		}
+	}()
+	Recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
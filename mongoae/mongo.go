@@ -0,0 +1,81 @@
+package mongoae
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/piyushkumar96/app-error/sqlmap"
+)
+
+// Error labels the server attaches to errors that may succeed when retried
+const (
+	LabelTransientTransaction = "TransientTransactionError"
+	LabelRetryableWrite       = "RetryableWriteError"
+)
+
+// codeDocumentValidationFailure is the server error code of a schema validation failure
+const codeDocumentValidationFailure = 121
+
+// Dialect classifies the errors of the MongoDB driver
+var Dialect sqlmap.Dialect = sqlmap.DialectFunc(Classify)
+
+// mapper is a sqlmap.Mapper with the MongoDB dialect, so MongoDB errors share the database codes
+var mapper = sqlmap.New(sqlmap.WithDialect(Dialect))
+
+// FromMongoError converts a MongoDB driver error into an AppError: mongo.ErrNoDocuments becomes a
+// 404 sqlmap.ErrNotFound, duplicate keys a 409 sqlmap.ErrConflict, transient transaction, retryable
+// write and write concern errors a retryable 503 sqlmap.ErrTransient, timeouts a retryable 504 and
+// network errors a retryable 503 sqlmap.ErrUnavailable; nil stays nil and AppErrors are returned
+// unchanged
+func FromMongoError(ctx context.Context, err error) error {
+	return mapper.MapSkip(ctx, err, 1)
+}
+
+// Classify returns the kind of a MongoDB driver error; ok is false for errors of other libraries
+func Classify(err error) (sqlmap.Kind, bool) {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return sqlmap.KindNotFound, true
+	case mongo.IsDuplicateKeyError(err):
+		return sqlmap.KindConflict, true
+	case hasLabel(err, LabelTransientTransaction), hasLabel(err, LabelRetryableWrite):
+		return sqlmap.KindTransient, true
+	case mongo.IsTimeout(err):
+		return sqlmap.KindTimeout, true
+	case mongo.IsNetworkError(err):
+		return sqlmap.KindUnavailable, true
+	case hasWriteConcernError(err):
+		return sqlmap.KindTransient, true
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) {
+		if serverErr.HasErrorCode(codeDocumentValidationFailure) {
+			return sqlmap.KindInvalidData, true
+		}
+		return sqlmap.KindUnknown, true
+	}
+	return sqlmap.KindUnknown, false
+}
+
+// hasLabel reports whether an error of the chain carries the given label
+func hasLabel(err error, label string) bool {
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel(label)
+}
+
+// hasWriteConcernError reports whether a write failed because its write concern was not satisfied,
+// e.g. replication timed out
+func hasWriteConcernError(err error) bool {
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		return writeErr.WriteConcernError != nil
+	}
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		return bulkErr.WriteConcernError != nil
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package mongoae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	ae "github.com/piyushkumar96/app-error"
+	"github.com/piyushkumar96/app-error/sqlmap"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   sqlmap.Kind
+		wantOK bool
+	}{
+		{"no documents", mongo.ErrNoDocuments, sqlmap.KindNotFound, true},
+		{"wrapped no documents", fmt.Errorf("find order: %w", mongo.ErrNoDocuments), sqlmap.KindNotFound, true},
+		{"duplicate key", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, sqlmap.KindConflict, true},
+		{"transient transaction", mongo.CommandError{Code: 251, Labels: []string{LabelTransientTransaction}}, sqlmap.KindTransient, true},
+		{"retryable write", mongo.CommandError{Code: 91, Labels: []string{LabelRetryableWrite}}, sqlmap.KindTransient, true},
+		{"network error", mongo.CommandError{Labels: []string{"NetworkError"}}, sqlmap.KindUnavailable, true},
+		{"write concern", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}, sqlmap.KindTransient, true},
+		{"bulk write concern", mongo.BulkWriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}, sqlmap.KindTransient, true},
+		{"validation failure", mongo.CommandError{Code: 121}, sqlmap.KindInvalidData, true},
+		{"other server error", mongo.CommandError{Code: 2}, sqlmap.KindUnknown, true},
+		{"other library", errors.New("boom"), sqlmap.KindUnknown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if kind, ok := Classify(tt.err); kind != tt.want || ok != tt.wantOK {
+				t.Errorf("Classify = %v, %v, want %v, %v", kind, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFromMongoError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{"no documents", mongo.ErrNoDocuments, "ERR_DB_NOT_FOUND", http.StatusNotFound, false},
+		{"duplicate key", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, "ERR_DB_CONFLICT", http.StatusConflict, false},
+		{"transient transaction", mongo.CommandError{Labels: []string{LabelTransientTransaction}}, "ERR_DB_TRANSIENT", http.StatusServiceUnavailable, true},
+		{"deadline", context.DeadlineExceeded, "ERR_DB_TIMEOUT", http.StatusGatewayTimeout, true},
+		{"other library", errors.New("boom"), ae.InternalError.Code, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromMongoError(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("FromMongoError = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromMongoError = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+		})
+	}
+
+	if err := FromMongoError(context.Background(), nil); err != nil {
+		t.Errorf("FromMongoError(nil) = %v, want nil", err)
+	}
+}
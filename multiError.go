@@ -0,0 +1,187 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// HTTPCodePolicy combines the HTTP codes of aggregated errors into the code of the response
+type HTTPCodePolicy func(codes []int) int
+
+// CombineHTTPCodes is the default HTTPCodePolicy: the shared code when every error has the same
+// one, 500 when any error is a server error, and 400 for a mix of client errors
+func CombineHTTPCodes(codes []int) int {
+	if len(codes) == 0 {
+		return http.StatusInternalServerError
+	}
+	combined := codes[0]
+	for _, code := range codes {
+		if code >= http.StatusInternalServerError {
+			return http.StatusInternalServerError
+		}
+		if code != combined {
+			combined = http.StatusBadRequest
+		}
+	}
+	return combined
+}
+
+// AppErrors aggregates several AppErrors, e.g. the failed items of a batch, keeping the code and
+// data of each
+type AppErrors struct {
+	errs   []*AppError
+	policy HTTPCodePolicy // Combines the member HTTP codes, CombineHTTPCodes when nil
+}
+
+// Join aggregates errs into an AppErrors, skipping nil errors and flattening nested AppErrors;
+// errors without an AppError in their chain become InternalError members. It returns nil when
+// errs holds no error, like errors.Join. The result is a concrete *AppErrors, so a nil result
+// returned as an error is a non-nil interface holding a nil pointer; check it before converting:
+//
+//	if joined := ae.Join(errs...); joined != nil {
+//		return joined
+//	}
+//	return nil
+//
+// Error, Unwrap, Len, Errors, GetHTTPCode, MarshalJSON and WriteHTTP treat a nil AppErrors as an
+// empty aggregate
+func Join(errs ...error) *AppErrors {
+	joined := &AppErrors{}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if nested, ok := err.(*AppErrors); ok {
+			if nested != nil {
+				joined.errs = append(joined.errs, nested.errs...)
+			}
+			continue
+		}
+		appErr, ok := asAppError(err)
+		if !ok {
+			appErr = newAppErr(context.Background(), err, InternalError, http.StatusInternalServerError, 2)
+		}
+		joined.errs = append(joined.errs, appErr)
+	}
+	if len(joined.errs) == 0 {
+		return nil
+	}
+	return joined
+}
+
+// Error joins the messages of the aggregated errors
+func (e *AppErrors) Error() string {
+	members := e.members()
+	msgs := make([]string, len(members))
+	for i, appErr := range members {
+		msgs[i] = appErr.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the aggregated errors, letting errors.Is and errors.As match any of them
+func (e *AppErrors) Unwrap() []error {
+	members := e.members()
+	if len(members) == 0 {
+		return nil
+	}
+	errs := make([]error, len(members))
+	for i, appErr := range members {
+		errs[i] = appErr
+	}
+	return errs
+}
+
+// Errors returns the aggregated AppErrors in the order they were joined
+func (e *AppErrors) Errors() []*AppError {
+	return append([]*AppError(nil), e.members()...)
+}
+
+// Len returns the number of aggregated errors
+func (e *AppErrors) Len() int {
+	return len(e.members())
+}
+
+// members returns the aggregated errors, none for a nil AppErrors
+func (e *AppErrors) members() []*AppError {
+	if e == nil {
+		return nil
+	}
+	return e.errs
+}
+
+// SetHTTPCodePolicy sets how the member HTTP codes are combined, e.g. a policy returning 207 for
+// partially failed batches, and returns the AppErrors
+func (e *AppErrors) SetHTTPCodePolicy(policy HTTPCodePolicy) *AppErrors {
+	e.policy = policy
+	return e
+}
+
+// GetHTTPCode returns the HTTP code of the response, combined from the member codes by the policy
+func (e *AppErrors) GetHTTPCode() int {
+	members := e.members()
+	codes := make([]int, len(members))
+	for i, appErr := range members {
+		codes[i] = appErr.httpCode
+		if codes[i] == 0 {
+			codes[i] = http.StatusInternalServerError
+		}
+	}
+	var policy HTTPCodePolicy
+	if e != nil {
+		policy = e.policy
+	}
+	if policy == nil {
+		policy = CombineHTTPCodes
+	}
+	return policy(codes)
+}
+
+// MarshalJSON encodes the aggregated errors as an array of client-facing error objects
+func (e *AppErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.envelopes(context.Background()))
+}
+
+// WriteHTTP writes the aggregated errors as a JSON array of error objects with the combined HTTP
+// code; every member is recorded and localized like with AppError.WriteHTTP. r may be nil
+func (e *AppErrors) WriteHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	if r != nil {
+		ctx = r.Context()
+	}
+	envs := e.envelopes(ctx)
+	for i, appErr := range e.members() {
+		appErr.recordResponse(r)
+		appErr.writeHeaders(w)
+		appErr.localizeEnvelope(r, &envs[i])
+	}
+	if r != nil && r.Header.Get(c.HeaderAcceptLanguage) != "" {
+		w.Header().Add(c.HeaderVary, c.HeaderAcceptLanguage)
+	}
+
+	w.Header().Set(c.HeaderContentType, c.ContentTypeJSON)
+	w.WriteHeader(e.GetHTTPCode())
+	_ = json.NewEncoder(w).Encode(envs)
+}
+
+// envelopes returns the client-facing envelopes of the aggregated errors
+func (e *AppErrors) envelopes(ctx context.Context) []errorEnvelope {
+	members := e.members()
+	envs := make([]errorEnvelope, len(members))
+	for i, appErr := range members {
+		envs[i] = appErr.envelope(ctx, true)
+	}
+	return envs
+}
+
+// localizeEnvelope replaces the message of env with the translation for the request's
+// Accept-Language, leaving the response headers to the caller
+func (e *AppError) localizeEnvelope(r *http.Request, env *errorEnvelope) {
+	if localized, _, ok := e.localizeRequest(r); ok {
+		env.Message = scrub(localized)
+	}
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// appErrWithCode creates an AppError with the given HTTP code
+func appErrWithCode(code int) *AppError {
+	return GetAppErr(context.Background(), errors.New("failed"), GetCustomErr("ERR_JOIN_1", "failed", false), code)
+}
+
+func TestJoinHTTPCodePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		errs   []error
+		policy HTTPCodePolicy
+		want   int
+	}{
+		{"single", []error{appErrWithCode(http.StatusNotFound)}, nil, http.StatusNotFound},
+		{"shared code", []error{appErrWithCode(http.StatusConflict), appErrWithCode(http.StatusConflict)}, nil, http.StatusConflict},
+		{"mixed client codes", []error{appErrWithCode(http.StatusNotFound), appErrWithCode(http.StatusConflict)}, nil, http.StatusBadRequest},
+		{"any server error", []error{appErrWithCode(http.StatusNotFound), appErrWithCode(http.StatusBadGateway)}, nil, http.StatusInternalServerError},
+		{"plain error", []error{appErrWithCode(http.StatusNotFound), errors.New("boom")}, nil, http.StatusInternalServerError},
+		{"nested aggregate", []error{Join(appErrWithCode(http.StatusConflict)), appErrWithCode(http.StatusConflict)}, nil, http.StatusConflict},
+		{
+			name:   "custom policy",
+			errs:   []error{appErrWithCode(http.StatusNotFound), appErrWithCode(http.StatusConflict)},
+			policy: func([]int) int { return http.StatusMultiStatus },
+			want:   http.StatusMultiStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined := Join(tt.errs...)
+			if tt.policy != nil {
+				joined.SetHTTPCodePolicy(tt.policy)
+			}
+			if got := joined.GetHTTPCode(); got != tt.want {
+				t.Errorf("GetHTTPCode() = %d, want %d", got, tt.want)
+			}
+
+			rec := httptest.NewRecorder()
+			joined.WriteHTTP(rec, nil)
+			if rec.Code != tt.want {
+				t.Errorf("WriteHTTP status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinSkipsNil(t *testing.T) {
+	tests := []struct {
+		name string
+		errs []error
+		want int
+	}{
+		{"no errors", nil, 0},
+		{"only nil", []error{nil, nil}, 0},
+		{"nil aggregate", []error{(*AppErrors)(nil), nil}, 0},
+		{"mixed", []error{nil, appErrWithCode(http.StatusNotFound), nil}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined := Join(tt.errs...)
+			if (joined == nil) != (tt.want == 0) || joined.Len() != tt.want {
+				t.Errorf("Join = %v with %d members, want %d", joined, joined.Len(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAppErrorsNilReceiver(t *testing.T) {
+	var joined *AppErrors
+	var err error = joined
+
+	if got := err.Error(); got != "" {
+		t.Errorf("Error() = %q, want empty", got)
+	}
+	if got := errors.Unwrap(err); got != nil {
+		t.Errorf("Unwrap() = %v, want nil", got)
+	}
+	if joined.Len() != 0 || len(joined.Errors()) != 0 {
+		t.Errorf("Len() = %d, Errors() = %v, want none", joined.Len(), joined.Errors())
+	}
+	if got := joined.GetHTTPCode(); got != http.StatusInternalServerError {
+		t.Errorf("GetHTTPCode() = %d, want 500", got)
+	}
+	if raw, err := joined.MarshalJSON(); err != nil || string(raw) != "[]" {
+		t.Errorf("MarshalJSON() = %s, %v, want []", raw, err)
+	}
+
+	rec := httptest.NewRecorder()
+	joined.WriteHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "[]\n" {
+		t.Errorf("WriteHTTP = %d %q, want 500 []", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures an AppError created by New
+type Option func(*options)
+
+// options holds the settings collected from the Options passed to New
+type options struct {
+	customErr   CustomErr
+	httpCode    int
+	data        []interface{}
+	retryAfter  time.Duration
+	maxAttempts int
+	backoff     BackoffStrategy
+	internalMsg string
+	fingerprint string
+	errorCodes  []string
+	skip        int
+}
+
+// WithCustomErr takes the code, message and retryability from a custom error definition; later
+// options override single fields
+func WithCustomErr(customErr *CustomErr) Option {
+	return func(o *options) {
+		if customErr != nil {
+			o.customErr = *customErr
+		}
+	}
+}
+
+// WithCode sets the primary error code
+func WithCode(code string) Option {
+	return func(o *options) {
+		o.customErr.Code = code
+	}
+}
+
+// WithMessage sets the client-facing message
+func WithMessage(msg string) Option {
+	return func(o *options) {
+		o.customErr.Message = msg
+	}
+}
+
+// WithInternalMsg sets the operator-facing message, which is logged but never serialized
+func WithInternalMsg(msg string) Option {
+	return func(o *options) {
+		o.internalMsg = msg
+	}
+}
+
+// WithFingerprint overrides the fingerprint computed for grouping and deduplication
+func WithFingerprint(fingerprint string) Option {
+	return func(o *options) {
+		o.fingerprint = fingerprint
+	}
+}
+
+// WithRetryable marks whether the error condition can be retried
+func WithRetryable(retryable bool) Option {
+	return func(o *options) {
+		o.customErr.Retryable = retryable
+	}
+}
+
+// WithHTTPCode sets the HTTP status code, overriding the default status of the custom error
+func WithHTTPCode(httpCode int) Option {
+	return func(o *options) {
+		o.httpCode = httpCode
+	}
+}
+
+// WithData attaches additional data to the error response
+func WithData(data interface{}) Option {
+	return func(o *options) {
+		o.data = []interface{}{data}
+	}
+}
+
+// WithRetryAfter sets how long clients should wait before retrying
+func WithRetryAfter(d time.Duration) Option {
+	return func(o *options) {
+		o.retryAfter = d
+	}
+}
+
+// WithRetryMaxAttempts sets how many attempts in total clients should make
+func WithRetryMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithRetryBackoff sets the backoff strategy clients should use between retries
+func WithRetryBackoff(strategy BackoffStrategy) Option {
+	return func(o *options) {
+		o.backoff = strategy
+	}
+}
+
+// WithErrorCodes replaces the error codes of the chain, e.g. with the codes received from a
+// downstream service; the primary code stays the one of the custom error and empty codes are skipped
+func WithErrorCodes(codes ...string) Option {
+	return func(o *options) {
+		o.errorCodes = nil
+		for _, code := range codes {
+			o.errorCodes = appendCode(o.errorCodes, code)
+		}
+	}
+}
+
+// WithStackSkip skips n additional frames when capturing the stack, so helpers wrapping New can
+// start the trace at their own caller
+func WithStackSkip(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.skip = n
+		}
+	}
+}
+
+// New creates an AppError from functional options, e.g.
+// ae.New(ctx, err, ae.WithCode("ERR_X"), ae.WithHTTPCode(500), ae.WithData(m), ae.WithRetryable(true)),
+// so new settings can be added without breaking callers. It behaves like GetAppErr otherwise
+func New(ctx context.Context, err error, opts ...Option) *AppError {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var customErr *CustomErr
+	if o.customErr != (CustomErr{}) {
+		customErr = &o.customErr
+	}
+
+	appErr := buildAppErr(ctx, err, customErr, o.httpCode, 2+o.skip, o.data...)
+	appErr.retryAfter = o.retryAfter
+	appErr.maxAttempts = o.maxAttempts
+	appErr.backoff = o.backoff
+	appErr.internalMsg = o.internalMsg
+	appErr.fingerprint = o.fingerprint
+	if len(o.errorCodes) > 0 {
+		appErr.ErrorCodes = o.errorCodes
+	}
+	finishAppErr(ctx, appErr)
+	return appErr
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewOptions(t *testing.T) {
+	catalog := GetCustomErr("ERR_OPT_1", "not found", false, WithDefaultHTTPCode(404))
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		want      CustomErr
+		wantHTTP  int
+		wantCodes []string
+		check     func(t *testing.T, appErr *AppError)
+	}{
+		{"fields", []Option{WithCode("ERR_OPT_2"), WithMessage("failed"), WithRetryable(true), WithHTTPCode(503)},
+			CustomErr{Code: "ERR_OPT_2", Message: "failed", Retryable: true}, 503, []string{"ERR_OPT_2"}, nil},
+		{"custom error default status", []Option{WithCustomErr(catalog)},
+			*catalog, 404, []string{"ERR_OPT_1"}, nil},
+		{"later options override fields", []Option{WithCustomErr(catalog), WithMessage("order not found"), WithHTTPCode(410)},
+			CustomErr{Code: "ERR_OPT_1", Message: "order not found", HTTPCode: 404}, 410, []string{"ERR_OPT_1"}, nil},
+		{"nil custom error", []Option{WithCustomErr(nil), WithCode("ERR_OPT_3")},
+			CustomErr{Code: "ERR_OPT_3"}, 0, []string{"ERR_OPT_3"}, nil},
+		{"error codes", []Option{WithCode("ERR_OPT_4"), WithErrorCodes("DOWN_1", "", "DOWN_2")},
+			CustomErr{Code: "ERR_OPT_4"}, 0, []string{"DOWN_1", "DOWN_2"}, nil},
+		{"details", []Option{WithCode("ERR_OPT_5"), WithData(map[string]int{"id": 1}), WithInternalMsg("cache miss"),
+			WithFingerprint("fp-1"), WithRetryAfter(time.Second), WithRetryMaxAttempts(3), WithRetryBackoff(BackoffLinear)},
+			CustomErr{Code: "ERR_OPT_5"}, 0, []string{"ERR_OPT_5"}, func(t *testing.T, appErr *AppError) {
+				if !reflect.DeepEqual(appErr.GetData(), map[string]int{"id": 1}) || appErr.GetInternalMsg() != "cache miss" ||
+					appErr.Fingerprint() != "fp-1" || appErr.GetRetryAfter() != time.Second ||
+					appErr.GetMaxAttempts() != 3 || appErr.GetBackoff() != BackoffLinear {
+					t.Errorf("details not applied: %+v", appErr)
+				}
+			}},
+		{"stack skip", []Option{WithCode("ERR_OPT_6"), WithStackSkip(1)},
+			CustomErr{Code: "ERR_OPT_6"}, 0, []string{"ERR_OPT_6"}, func(t *testing.T, appErr *AppError) {
+				if frames := appErr.GetStackTrace(); len(frames) > 0 && strings.Contains(frames[0].Function, "TestNewOptions") {
+					t.Errorf("stack starts at %s, want the caller of the test function", frames[0].Function)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := New(context.Background(), errors.New("cause"), tt.opts...)
+			got := CustomErr{}
+			if appErr.CustomErr != nil {
+				got = *appErr.CustomErr
+			}
+			if got != tt.want || appErr.GetHTTPCode() != tt.wantHTTP || !reflect.DeepEqual(appErr.GetErrCodes(), tt.wantCodes) {
+				t.Errorf("New = %+v, %d, %v, want %+v, %d, %v", got, appErr.GetHTTPCode(), appErr.GetErrCodes(), tt.want, tt.wantHTTP, tt.wantCodes)
+			}
+			if tt.check != nil {
+				tt.check(t, appErr)
+			}
+		})
+	}
+
+	if catalog.Message != "not found" {
+		t.Error("options must not modify the custom error passed to WithCustomErr")
+	}
+}
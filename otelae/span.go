@@ -0,0 +1,69 @@
+package otelae
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Attribute keys set on the exception event
+const (
+	AttrErrorCode    = attribute.Key("error.code")
+	AttrErrorMessage = attribute.Key("error.message")
+	AttrErrorID      = attribute.Key("error.id")
+	AttrHTTPCode     = attribute.Key("http.response.status_code")
+)
+
+// RecordSpanError records appErr on the span found in ctx: it sets the span status to Error,
+// records an exception event with the code, message, error ID and HTTP code, and attaches the
+// trace and span IDs to the AppError for correlation. Without a recording span it does nothing
+func RecordSpanError(ctx context.Context, appErr *ae.AppError) {
+	if appErr == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	code, msg := appErr.GetErrCode(), ""
+	if appErr.CustomErr != nil {
+		msg = appErr.CustomErr.Message
+	}
+	span.SetStatus(codes.Error, code)
+	span.RecordError(appErr, trace.WithAttributes(
+		AttrErrorCode.String(code),
+		AttrErrorMessage.String(ae.Scrub(msg)),
+		AttrErrorID.String(appErr.GetID()),
+		AttrHTTPCode.Int(appErr.GetHTTPCode()),
+	))
+
+	sc := span.SpanContext()
+	appErr.SetTraceIDs(sc.TraceID().String(), sc.SpanID().String())
+}
+
+// TraceContext returns the trace and span IDs of the valid span context in ctx; it is the
+// ae.TraceContextExtractor installed by EnableTraceContext
+func TraceContext(ctx context.Context) (traceID, spanID string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
+
+// EnableTraceContext makes every AppError capture the trace and span IDs of the OpenTelemetry span
+// in its context, so responses include the trace ID as a correlation ID
+func EnableTraceContext() {
+	ae.SetTraceContextExtractor(TraceContext)
+}
+
+// EnableAutoRecord records every AppError created by ae.GetAppErr, ae.New and ae.Wrap on the span
+// of its context
+func EnableAutoRecord() {
+	ae.SetSpanRecorder(RecordSpanError)
+}
@@ -0,0 +1,144 @@
+package otelae
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// recordingSpan is a span capturing what is recorded on it
+type recordingSpan struct {
+	noop.Span
+	sc         trace.SpanContext
+	recording  bool
+	statusCode codes.Code
+	statusDesc string
+	recorded   error
+	attrs      map[attribute.Key]attribute.Value
+}
+
+func (s *recordingSpan) IsRecording() bool              { return s.recording }
+func (s *recordingSpan) SpanContext() trace.SpanContext { return s.sc }
+func (s *recordingSpan) SetStatus(code codes.Code, desc string) {
+	s.statusCode, s.statusDesc = code, desc
+}
+
+func (s *recordingSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.recorded = err
+	s.attrs = map[attribute.Key]attribute.Value{}
+	cfg := trace.NewEventConfig(opts...)
+	for _, attr := range cfg.Attributes() {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+// spanContext returns a valid sampled span context
+func spanContext() trace.SpanContext {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+}
+
+func TestRecordSpanError(t *testing.T) {
+	customErr := ae.GetCustomErr("ERR_OTL_1", "order not found", false)
+
+	tests := []struct {
+		name      string
+		recording bool
+		nilErr    bool
+		wantRec   bool
+	}{
+		{"recording span", true, false, true},
+		{"non-recording span", false, false, false},
+		{"nil AppError", true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := &recordingSpan{sc: spanContext(), recording: tt.recording}
+			ctx := trace.ContextWithSpan(context.Background(), span)
+			var appErr *ae.AppError
+			if !tt.nilErr {
+				appErr = ae.GetAppErr(context.Background(), errors.New("no rows"), customErr, 404)
+			}
+
+			RecordSpanError(ctx, appErr)
+			if (span.recorded != nil) != tt.wantRec {
+				t.Fatalf("recorded = %v, want recorded %v", span.recorded, tt.wantRec)
+			}
+			if !tt.wantRec {
+				if appErr != nil && appErr.GetTraceID() != "" {
+					t.Errorf("trace ID = %q, want none", appErr.GetTraceID())
+				}
+				return
+			}
+
+			if span.statusCode != codes.Error || span.statusDesc != "ERR_OTL_1" {
+				t.Errorf("status = %v %q, want Error ERR_OTL_1", span.statusCode, span.statusDesc)
+			}
+			if span.attrs[AttrErrorCode].AsString() != "ERR_OTL_1" || span.attrs[AttrErrorMessage].AsString() != "order not found" ||
+				span.attrs[AttrErrorID].AsString() != appErr.GetID() || span.attrs[AttrHTTPCode].AsInt64() != 404 {
+				t.Errorf("event attributes = %v", span.attrs)
+			}
+			if appErr.GetTraceID() != "4bf92f3577b34da6a3ce929d0e0e4736" || appErr.GetSpanID() != "00f067aa0ba902b7" {
+				t.Errorf("trace IDs = %q %q, want the span's", appErr.GetTraceID(), appErr.GetSpanID())
+			}
+		})
+	}
+}
+
+func TestTraceContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantTraceID string
+		wantOK      bool
+	}{
+		{"valid span", trace.ContextWithSpanContext(context.Background(), spanContext()), "4bf92f3577b34da6a3ce929d0e0e4736", true},
+		{"no span", context.Background(), "", false},
+		{"invalid span", trace.ContextWithSpanContext(context.Background(), trace.SpanContext{}), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceID, spanID, ok := TraceContext(tt.ctx)
+			if traceID != tt.wantTraceID || ok != tt.wantOK || (ok && spanID != "00f067aa0ba902b7") {
+				t.Errorf("TraceContext = %q, %q, %v, want %q, %v", traceID, spanID, ok, tt.wantTraceID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEnable(t *testing.T) {
+	tests := []struct {
+		name       string
+		enable     func()
+		reset      func()
+		wantRecord bool
+	}{
+		{"trace context", EnableTraceContext, func() { ae.SetTraceContextExtractor(nil) }, false},
+		{"auto record", EnableAutoRecord, func() { ae.SetSpanRecorder(nil) }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.enable()
+			defer tt.reset()
+
+			span := &recordingSpan{sc: spanContext(), recording: true}
+			ctx := trace.ContextWithSpan(context.Background(), span)
+			appErr := ae.GetAppErr(ctx, errors.New("no rows"), ae.GetCustomErr("ERR_OTL_2", "order not found", false), 404)
+
+			if appErr.GetTraceID() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("trace ID = %q, want the span's", appErr.GetTraceID())
+			}
+			if (span.recorded != nil) != tt.wantRecord {
+				t.Errorf("recorded = %v, want recorded %v", span.recorded, tt.wantRecord)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"os"
+)
+
+// PagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty event severities
+const (
+	PagerDutyCritical = "critical"
+	PagerDutyError    = "error"
+	PagerDutyWarning  = "warning"
+	PagerDutyInfo     = "info"
+)
+
+// PagerDutyOption configures a PagerDutyReporter
+type PagerDutyOption func(*PagerDutyReporter)
+
+// WithPagerDutyFilter sets which AppErrors trigger incidents, 5xx errors by default
+func WithPagerDutyFilter(filter ErrorFilter) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.filter = filter
+	}
+}
+
+// WithPagerDutyRoutingKeys routes code families to other services' integration keys
+func WithPagerDutyRoutingKeys(keys CodeFamilies) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.routingKeys = keys
+	}
+}
+
+// WithPagerDutySeverities overrides the event severity per code family; by default the severity
+// of the AppError is used when set, otherwise 5xx errors map to "error" and everything else to
+// "warning"
+func WithPagerDutySeverities(severities CodeFamilies) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.severities = severities
+	}
+}
+
+// WithPagerDutyRunbooks sets the runbook linked from incidents, per code family
+func WithPagerDutyRunbooks(runbooks CodeFamilies) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.runbooks = runbooks
+	}
+}
+
+// WithPagerDutySource sets the event source, the hostname by default
+func WithPagerDutySource(source string) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.source = source
+	}
+}
+
+// WithPagerDutyEndpoint sets the Events API URL, e.g. for an EU account or a test server
+func WithPagerDutyEndpoint(url string) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.endpoint = url
+	}
+}
+
+// WithPagerDutyClient sets the HTTP client used to send events
+func WithPagerDutyClient(client *http.Client) PagerDutyOption {
+	return func(r *PagerDutyReporter) {
+		r.client = client
+	}
+}
+
+// PagerDutyReporter is a Reporter triggering PagerDuty incidents through the Events API v2;
+// occurrences sharing a group key (see WithGrouping, GroupByFingerprint by default) are deduplicated
+// into one incident
+type PagerDutyReporter struct {
+	routingKey  string
+	routingKeys CodeFamilies
+	severities  CodeFamilies
+	runbooks    CodeFamilies
+	filter      ErrorFilter
+	source      string
+	endpoint    string
+	client      *http.Client
+}
+
+// NewPagerDutyReporter creates a PagerDutyReporter sending to the given integration key
+func NewPagerDutyReporter(routingKey string, opts ...PagerDutyOption) *PagerDutyReporter {
+	hostname, _ := os.Hostname()
+	r := &PagerDutyReporter{
+		routingKey: routingKey,
+		filter:     MinStatus(http.StatusInternalServerError),
+		source:     hostname,
+		endpoint:   PagerDutyEventsURL,
+		client:     defaultAlertClient,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Report triggers an incident for the AppError when it matches the filter
+func (r *PagerDutyReporter) Report(ctx context.Context, appErr *AppError) error {
+	if appErr == nil || !r.filter(appErr) {
+		return nil
+	}
+	return postJSON(ctx, r.client, r.endpoint, r.event(ctx, appErr))
+}
+
+// event builds the Events API v2 trigger event for the AppError
+func (r *PagerDutyReporter) event(ctx context.Context, appErr *AppError) map[string]interface{} {
+	code := primaryCode(appErr)
+
+	routingKey := r.routingKey
+	if key, ok := r.routingKeys.Lookup(code); ok {
+		routingKey = key
+	}
+	severity, ok := r.severities.Lookup(code)
+	if !ok && appErr.CustomErr != nil && appErr.CustomErr.Severity != 0 {
+		severity, ok = pagerDutySeverity(appErr.CustomErr.Severity), true
+	}
+	if !ok {
+		severity = PagerDutyWarning
+		if MinStatus(http.StatusInternalServerError)(appErr) {
+			severity = PagerDutyError
+		}
+	}
+
+	details := map[string]interface{}{
+		"error_id":    appErr.GetID(),
+		"http_code":   appErr.httpCode,
+		"error_codes": appErr.ErrorCodes,
+		"error":       scrub(appErr.Error()),
+	}
+	for key, value := range appErr.labels {
+		details["label."+key] = value
+	}
+
+	event := map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"dedup_key":    GroupKey(ctx, appErr, GroupByFingerprint),
+		"payload": map[string]interface{}{
+			"summary":        truncateSummary(code + ": " + scrub(appErr.GetMsg())),
+			"source":         r.source,
+			"severity":       severity,
+			"class":          code,
+			"custom_details": details,
+		},
+	}
+	if runbook, ok := r.runbooks.Lookup(code); ok {
+		event["links"] = []interface{}{map[string]interface{}{"href": runbook, "text": "Runbook"}}
+	}
+	return event
+}
+
+// truncateSummary keeps a summary within the 1024 characters PagerDuty accepts
+func truncateSummary(s string) string {
+	if len(s) > 1024 {
+		return s[:1021] + "..."
+	}
+	return s
+}
+
+// pagerDutySeverity maps an AppError severity to the PagerDuty event severity
+func pagerDutySeverity(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return PagerDutyCritical
+	case SeverityError:
+		return PagerDutyError
+	case SeverityWarn:
+		return PagerDutyWarning
+	}
+	return PagerDutyInfo
+}
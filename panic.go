@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+)
+
+// defaultGoroutineDumpBytes bounds the goroutine dump when Config.GoroutineDumpBytes is unset
+const defaultGoroutineDumpBytes = 64 << 10
+
+// FromPanic converts a recovered panic value into an Internal AppError using Config.PanicCustomErr
+// (PanicRecovered when unset); the stack captured points
+// at the panic site and, when Config.CapturePanicGoroutines is set, a bounded dump of all
+// goroutines is attached to the debug data under "goroutines"
+func FromPanic(ctx context.Context, recovered interface{}) *AppError {
+	var err error
+	if e, ok := recovered.(error); ok {
+		err = fmt.Errorf("panic: %w", e)
+	} else {
+		err = fmt.Errorf("panic: %v", recovered)
+	}
+
+	cfg := currentConfig()
+	customErr := cfg.PanicCustomErr
+	if customErr == nil {
+		customErr = PanicRecovered
+	}
+
+	// Start the stack at the recovering function so the panic site is in it
+	appErr := buildAppErr(ctx, err, customErr, http.StatusInternalServerError, 2)
+	if cfg.CapturePanicGoroutines {
+		appErr.SetDebug("goroutines", goroutineDump(cfg.GoroutineDumpBytes))
+	}
+	finishAppErr(ctx, appErr)
+	return appErr
+}
+
+// Recover converts a panic into an AppError stored in *errp; it must be deferred directly:
+//
+//	defer ae.Recover(ctx, &err)
+func Recover(ctx context.Context, errp *error) {
+	if recovered := recover(); recovered != nil {
+		appErr := FromPanic(ctx, recovered)
+		if errp != nil {
+			*errp = appErr
+		}
+	}
+}
+
+// goroutineDump returns the stacks of all goroutines, truncated to maxBytes
+func goroutineDump(maxBytes int) string {
+	if maxBytes <= 0 {
+		maxBytes = defaultGoroutineDumpBytes
+	}
+
+	buf := make([]byte, maxBytes)
+	n := runtime.Stack(buf, true)
+	dump := string(buf[:n])
+	if n == maxBytes {
+		dump += "\n...[goroutine dump truncated]"
+	}
+	return dump
+}
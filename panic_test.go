@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	custom := GetCustomErr("ERR_PNC_1", "crashed", false)
+
+	tests := []struct {
+		name           string
+		recovered      interface{}
+		customErr      *CustomErr
+		goroutines     bool
+		dumpBytes      int
+		wantErr        string
+		wantCode       string
+		wantGoroutines bool
+	}{
+		{"string value", "boom", nil, false, 0, "panic: boom", PanicRecovered.Code, false},
+		{"error value", sentinel, nil, false, 0, "panic: sentinel", PanicRecovered.Code, false},
+		{"custom error", "boom", custom, false, 0, "panic: boom", "ERR_PNC_1", false},
+		{"goroutine dump", "boom", nil, true, 0, "panic: boom", PanicRecovered.Code, true},
+		{"truncated dump", "boom", nil, true, 64, "panic: boom", PanicRecovered.Code, true},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.PanicCustomErr = tt.customErr
+			cfg.CapturePanicGoroutines = tt.goroutines
+			cfg.GoroutineDumpBytes = tt.dumpBytes
+			SetConfig(cfg)
+
+			err := func() (err error) {
+				defer Recover(context.Background(), &err)
+				panic(tt.recovered)
+			}()
+
+			appErr, ok := asAppError(err)
+			if !ok {
+				t.Fatalf("Recover stored %T, want *AppError", err)
+			}
+			if appErr.Error() != tt.wantErr || appErr.CustomErr.Code != tt.wantCode || appErr.GetHTTPCode() != 500 {
+				t.Errorf("got %q, %s, %d, want %q, %s, 500", appErr.Error(), appErr.CustomErr.Code, appErr.GetHTTPCode(), tt.wantErr, tt.wantCode)
+			}
+			if tt.recovered == sentinel && !errors.Is(err, sentinel) {
+				t.Error("a recovered error must stay in the chain")
+			}
+			dump, hasDump := appErr.GetDebug()["goroutines"].(string)
+			if hasDump != tt.wantGoroutines {
+				t.Fatalf("goroutine dump attached = %v, want %v", hasDump, tt.wantGoroutines)
+			}
+			if tt.dumpBytes > 0 && (len(dump) > tt.dumpBytes+64 || !strings.HasSuffix(dump, "[goroutine dump truncated]")) {
+				t.Errorf("dump of %d bytes not truncated to %d", len(dump), tt.dumpBytes)
+			}
+		})
+	}
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	err := func() (err error) {
+		defer Recover(context.Background(), &err)
+		return nil
+	}()
+	if err != nil {
+		t.Errorf("Recover without a panic stored %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// ProblemDetails is an RFC 9457 (formerly RFC 7807) problem document
+type ProblemDetails struct {
+	Type       string                 // URI reference identifying the problem type
+	Title      string                 // Short summary of the problem type
+	Status     int                    // HTTP status code
+	Detail     string                 // Explanation specific to this occurrence
+	Instance   string                 // URI reference identifying this occurrence
+	Extensions map[string]interface{} // Extension members, serialized next to the standard ones
+}
+
+// problemMembers are the standard members extensions may not override
+var problemMembers = map[string]struct{}{"type": {}, "title": {}, "status": {}, "detail": {}, "instance": {}}
+
+// MarshalJSON flattens the extension members into the problem document
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]interface{}, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		if _, reserved := problemMembers[key]; !reserved {
+			doc[key] = value
+		}
+	}
+	doc["type"] = p.Type
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// ToProblemDetails converts the AppError into a Problem Details document: the type is
+// Config.ProblemTypeBaseURI followed by the primary code ("about:blank" without a base URI), the
+// title is the HTTP status text and the detail is the client-facing message. The code, error codes,
+// retryability and error ID become extension members, as do the keys of map-shaped client-facing
+// data; other data is kept under "data"
+func (e *AppError) ToProblemDetails() *ProblemDetails {
+	status := e.httpCode
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	env := e.envelope(context.Background(), true)
+	problem := &ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: env.Message,
+		Extensions: map[string]interface{}{
+			"code":        env.Code,
+			"error_codes": env.ErrorCodes,
+			"retryable":   env.Retryable,
+			"error_id":    e.GetID(),
+		},
+	}
+	if env.Retry != nil {
+		problem.Extensions["retry"] = env.Retry
+	}
+	if env.TraceID != "" {
+		problem.Extensions["trace_id"] = env.TraceID
+	}
+	if base := currentConfig().ProblemTypeBaseURI; base != "" && env.Code != "" {
+		problem.Type = base + env.Code
+	}
+
+	switch data := env.Data.(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, value := range data {
+			if _, exists := problem.Extensions[key]; !exists {
+				problem.Extensions[key] = value
+			}
+		}
+	default:
+		problem.Extensions["data"] = data
+	}
+	return problem
+}
+
+// WriteProblem writes the AppError as an application/problem+json response whose instance is the
+// request path, with the same headers and recording as WriteHTTP. r may be nil
+func (e *AppError) WriteProblem(w http.ResponseWriter, r *http.Request) {
+	e.recordResponse(r)
+	problem := e.ToProblemDetails()
+	if r != nil {
+		problem.Instance = r.URL.RequestURI()
+	}
+	e.writeLocalized(w, r, &problem.Detail)
+
+	e.writeHeaders(w)
+	w.Header().Set(c.HeaderContentType, c.ContentTypeProblemJSON)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
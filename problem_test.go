@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestToProblemDetails(t *testing.T) {
+	ctx := context.Background()
+	notFound := GetCustomErr("ERR_PRB_1", "order not found", false)
+
+	tests := []struct {
+		name    string
+		baseURI string
+		err     *AppError
+		want    string // Document without error_id, which is random
+	}{
+		{"about blank", "", GetAppErr(ctx, errors.New("no rows"), notFound, 404),
+			`{"code":"ERR_PRB_1","detail":"order not found","error_codes":["ERR_PRB_1"],"retryable":false,"status":404,"title":"Not Found","type":"about:blank"}`},
+		{"type base URI", "https://errors.example.com/", GetAppErr(ctx, errors.New("no rows"), notFound, 404),
+			`{"code":"ERR_PRB_1","detail":"order not found","error_codes":["ERR_PRB_1"],"retryable":false,"status":404,"title":"Not Found","type":"https://errors.example.com/ERR_PRB_1"}`},
+		{"unset status", "", GetAppErr(ctx, errors.New("boom"), notFound, 0),
+			`{"code":"ERR_PRB_1","detail":"order not found","error_codes":["ERR_PRB_1"],"retryable":false,"status":500,"title":"Internal Server Error","type":"about:blank"}`},
+		{"map data flattened", "", GetAppErr(ctx, errors.New("no rows"), notFound, 404, map[string]interface{}{"order": "o1", "code": "spoofed", "status": 200}),
+			`{"code":"ERR_PRB_1","detail":"order not found","error_codes":["ERR_PRB_1"],"order":"o1","retryable":false,"status":404,"title":"Not Found","type":"about:blank"}`},
+		{"other data kept under data", "", GetAppErr(ctx, errors.New("no rows"), notFound, 404, []string{"o1", "o2"}),
+			`{"code":"ERR_PRB_1","data":["o1","o2"],"detail":"order not found","error_codes":["ERR_PRB_1"],"retryable":false,"status":404,"title":"Not Found","type":"about:blank"}`},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ProblemTypeBaseURI = tt.baseURI
+			SetConfig(cfg)
+
+			problem := tt.err.ToProblemDetails()
+			if problem.Extensions["error_id"] != tt.err.GetID() {
+				t.Errorf("error_id = %v, want %s", problem.Extensions["error_id"], tt.err.GetID())
+			}
+			delete(problem.Extensions, "error_id")
+			raw, err := json.Marshal(problem)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("ToProblemDetails =\n%s\nwant\n%s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("no rows"), GetCustomErr("ERR_PRB_2", "order not found", false), 404)
+	rec := httptest.NewRecorder()
+	appErr.WriteProblem(rec, httptest.NewRequest(http.MethodGet, "/orders/o1?x=1", nil))
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if rec.Code != 404 || rec.Header().Get(c.HeaderContentType) != c.ContentTypeProblemJSON || doc["instance"] != "/orders/o1?x=1" {
+		t.Errorf("WriteProblem = %d %s %v", rec.Code, rec.Header().Get(c.HeaderContentType), doc)
+	}
+}
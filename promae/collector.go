@@ -0,0 +1,106 @@
+package promae
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// Collector counts created AppErrors by code, category, HTTP code and retryability, and by tenant
+// when WithTenantLabel is set
+type Collector struct {
+	errors *prometheus.CounterVec
+	tenant bool // Label the counter with the tenant
+}
+
+// collectorOptions holds the settings of a Collector
+type collectorOptions struct {
+	counter prometheus.CounterOpts
+	tenant  bool
+}
+
+// CollectorOption configures a Collector
+type CollectorOption func(*collectorOptions)
+
+// WithNamespace prefixes the metric name with a namespace, e.g. "orders_app_errors_total"
+func WithNamespace(namespace string) CollectorOption {
+	return func(o *collectorOptions) {
+		o.counter.Namespace = namespace
+	}
+}
+
+// WithConstLabels adds labels with fixed values, e.g. the service name, to the metric
+func WithConstLabels(labels prometheus.Labels) CollectorOption {
+	return func(o *collectorOptions) {
+		o.counter.ConstLabels = labels
+	}
+}
+
+// WithTenantLabel adds a "tenant" label holding the tenant set with ae.WithTenant, empty for errors
+// without one. Every tenant adds its own series, so only enable it with a bounded set of tenants
+func WithTenantLabel() CollectorOption {
+	return func(o *collectorOptions) {
+		o.tenant = true
+	}
+}
+
+// NewCollector creates a Collector exposing the app_errors_total counter
+func NewCollector(opts ...CollectorOption) *Collector {
+	o := &collectorOptions{counter: prometheus.CounterOpts{
+		Name: "app_errors_total",
+		Help: "Number of application errors created, by code, category, HTTP code and retryability.",
+	}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	labels := []string{"code", "category", "http_code", "retryable"}
+	if o.tenant {
+		labels = append(labels, c.TenantLabel)
+	}
+	return &Collector{
+		errors: prometheus.NewCounterVec(o.counter, labels),
+		tenant: o.tenant,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (col *Collector) Describe(ch chan<- *prometheus.Desc) {
+	col.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (col *Collector) Collect(ch chan<- prometheus.Metric) {
+	col.errors.Collect(ch)
+}
+
+// Observe counts a created AppError; it is the ae.MetricsHook installed by Install
+func (col *Collector) Observe(_ context.Context, metric ae.ErrorMetric) {
+	values := []string{
+		metric.Code,
+		string(metric.Category),
+		strconv.Itoa(metric.HTTPCode),
+		strconv.FormatBool(metric.Retryable),
+	}
+	if col.tenant {
+		values = append(values, metric.Labels[c.TenantLabel])
+	}
+	col.errors.WithLabelValues(values...).Inc()
+}
+
+// Install creates a Collector, registers it with reg (prometheus.DefaultRegisterer when nil) and
+// installs it as the metrics hook, so every created AppError is counted
+func Install(reg prometheus.Registerer, opts ...CollectorOption) (*Collector, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	collector := NewCollector(opts...)
+	if err := reg.Register(collector); err != nil {
+		return nil, err
+	}
+	ae.SetMetricsHook(collector.Observe)
+	return collector, nil
+}
@@ -0,0 +1,91 @@
+package promae
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// counts returns the value of every app_errors_total series gathered from reg, keyed by its labels
+func counts(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	got := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range metric.GetLabel() {
+				key += " " + label.GetName() + "=" + label.GetValue()
+			}
+			got[key] = metric.GetCounter().GetValue()
+		}
+	}
+	return got
+}
+
+func TestCollector(t *testing.T) {
+	defer ae.SetMetricsHook(nil)
+	busy := ae.GetCustomErr("ERR_PRM_1", "try again", true, ae.WithCategory(ae.CategoryUnavailable))
+	missing := ae.GetCustomErr("ERR_PRM_2", "not found", false)
+
+	tests := []struct {
+		name string
+		opts []CollectorOption
+		ctx  context.Context
+		want map[string]float64
+	}{
+		{"default", nil, context.Background(), map[string]float64{
+			"app_errors_total category=unavailable code=ERR_PRM_1 http_code=503 retryable=true": 2,
+			"app_errors_total category= code=ERR_PRM_2 http_code=404 retryable=false":           1,
+		}},
+		{"namespace and const labels", []CollectorOption{WithNamespace("orders"), WithConstLabels(prometheus.Labels{"service": "api"})},
+			context.Background(), map[string]float64{
+				"orders_app_errors_total category=unavailable code=ERR_PRM_1 http_code=503 retryable=true service=api": 2,
+				"orders_app_errors_total category= code=ERR_PRM_2 http_code=404 retryable=false service=api":           1,
+			}},
+		{"tenant label", []CollectorOption{WithTenantLabel()}, ae.WithTenant(context.Background(), "acme"), map[string]float64{
+			"app_errors_total category=unavailable code=ERR_PRM_1 http_code=503 retryable=true " + c.TenantLabel + "=acme": 2,
+			"app_errors_total category= code=ERR_PRM_2 http_code=404 retryable=false " + c.TenantLabel + "=acme":           1,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			if _, err := Install(reg, tt.opts...); err != nil {
+				t.Fatalf("Install: %v", err)
+			}
+			ae.GetAppErr(tt.ctx, errors.New("busy"), busy, 503)
+			ae.GetAppErr(tt.ctx, errors.New("busy"), busy, 503)
+			ae.GetAppErr(tt.ctx, errors.New("no rows"), missing, 404)
+
+			got := counts(t, reg)
+			if len(got) != len(tt.want) {
+				t.Errorf("series = %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %v, want %v (series %v)", key, got[key], want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallTwice(t *testing.T) {
+	defer ae.SetMetricsHook(nil)
+	reg := prometheus.NewRegistry()
+	if _, err := Install(reg); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if _, err := Install(reg); err == nil {
+		t.Error("registering a second collector with the same metric succeeded")
+	}
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client went away before the
+// upstream answered
+const StatusClientClosedRequest = 499
+
+// ProxyErrorHandler returns an httputil.ReverseProxy ErrorHandler converting transport failures into
+// AppErrors written with WriteHTTP: timeouts become retryable 504s, the client cancelling becomes
+// a 499 and other failures become 502s. A 502 is retryable when the upstream connection could not
+// be established or the request method is idempotent, since the request may otherwise have been
+// processed
+func ProxyErrorHandler() func(w http.ResponseWriter, r *http.Request, err error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		ProxyError(r, err).WriteHTTP(w, r)
+	}
+}
+
+// ProxyError converts a reverse proxy transport failure into an AppError, see ProxyErrorHandler
+func ProxyError(r *http.Request, err error) *AppError {
+	ctx := r.Context()
+
+	var customErr *CustomErr
+	var status int
+	var retryable bool
+	switch {
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		customErr, status = ClientClosedRequest, StatusClientClosedRequest
+	case isTimeout(err):
+		customErr, status, retryable = GatewayTimeout, http.StatusGatewayTimeout, true
+	default:
+		customErr, status = BadGateway, http.StatusBadGateway
+		retryable = isDialError(err) || isIdempotent(r.Method)
+	}
+
+	customErr = customErr.Clone()
+	customErr.Retryable = retryable
+	return GetAppErr(ctx, err, customErr, status)
+}
+
+// isTimeout reports whether err is a deadline or network timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isDialError reports whether err happened while connecting, before the request was sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// isIdempotent reports whether requests with the method may safely be repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	timeoutErr := &net.DNSError{Err: "i/o timeout", Name: "upstream", IsTimeout: true}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		method        string
+		err           error
+		wantStatus    int
+		wantCode      string
+		wantRetryable bool
+	}{
+		{"client went away", cancelled, http.MethodPost, context.Canceled, StatusClientClosedRequest, ClientClosedRequest.Code, false},
+		{"upstream cancelled", context.Background(), http.MethodPost, context.Canceled, http.StatusBadGateway, BadGateway.Code, false},
+		{"deadline", context.Background(), http.MethodPost, context.DeadlineExceeded, http.StatusGatewayTimeout, GatewayTimeout.Code, true},
+		{"network timeout", context.Background(), http.MethodPost, timeoutErr, http.StatusGatewayTimeout, GatewayTimeout.Code, true},
+		{"dial error", context.Background(), http.MethodPost, dialErr, http.StatusBadGateway, BadGateway.Code, true},
+		{"reset on POST", context.Background(), http.MethodPost, readErr, http.StatusBadGateway, BadGateway.Code, false},
+		{"reset on GET", context.Background(), http.MethodGet, readErr, http.StatusBadGateway, BadGateway.Code, true},
+		{"reset on PUT", context.Background(), http.MethodPut, readErr, http.StatusBadGateway, BadGateway.Code, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil).WithContext(tt.ctx)
+			appErr := ProxyError(r, tt.err)
+			if appErr.GetHTTPCode() != tt.wantStatus || appErr.CustomErr.Code != tt.wantCode || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("ProxyError = %d %s retryable %v, want %d %s retryable %v",
+					appErr.GetHTTPCode(), appErr.CustomErr.Code, appErr.IsRetryable(), tt.wantStatus, tt.wantCode, tt.wantRetryable)
+			}
+			if !errors.Is(appErr, tt.err) {
+				t.Errorf("ProxyError lost the transport error %v", tt.err)
+			}
+		})
+	}
+
+	if !BadGateway.Retryable || !GatewayTimeout.Retryable {
+		t.Error("ProxyError must not modify the shared custom errors")
+	}
+}
+
+func TestProxyErrorHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ProxyErrorHandler()(rec, httptest.NewRequest(http.MethodGet, "/", nil), context.DeadlineExceeded)
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+}
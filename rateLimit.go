@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// RateLimited creates a retryable 429 AppError carrying the rate limit state; WriteHTTP emits it as
+// X-RateLimit-* and Retry-After headers
+func RateLimited(ctx context.Context, limit, remaining int, resetAt time.Time) *AppError {
+	err := fmt.Errorf("rate limit of %d exceeded, resets at %s", limit, resetAt.UTC().Format(time.RFC3339))
+	appErr := buildAppErr(ctx, err, RateLimitExceeded, http.StatusTooManyRequests, 2, map[string]interface{}{
+		"limit":     limit,
+		"remaining": remaining,
+		"reset_at":  resetAt.UTC().Format(time.RFC3339),
+	})
+
+	wait := time.Until(resetAt)
+	if wait < 0 {
+		wait = 0
+	}
+	appErr.SetRetryAfter(wait).
+		SetHeader(c.HeaderRateLimitLimit, strconv.Itoa(limit)).
+		SetHeader(c.HeaderRateLimitRemaining, strconv.Itoa(remaining)).
+		SetHeader(c.HeaderRateLimitReset, strconv.FormatInt(resetAt.Unix(), 10))
+	finishAppErr(ctx, appErr)
+	return appErr
+}
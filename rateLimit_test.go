@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestRateLimited(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		limit, remain  int
+		resetAt        time.Time
+		wantRetryAfter string
+	}{
+		{"future reset", 100, 0, now.Add(30 * time.Second), "30"},
+		{"partial second rounds up", 10, 0, now.Add(1500 * time.Millisecond), "2"},
+		{"past reset", 5, 1, now.Add(-time.Minute), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := RateLimited(context.Background(), tt.limit, tt.remain, tt.resetAt)
+			if appErr.GetHTTPCode() != http.StatusTooManyRequests || !appErr.IsRetryable() || appErr.GetErrCode() != RateLimitExceeded.Code {
+				t.Errorf("got %d retryable=%v code %s", appErr.GetHTTPCode(), appErr.IsRetryable(), appErr.GetErrCode())
+			}
+
+			rec := httptest.NewRecorder()
+			appErr.WriteHTTP(rec, nil)
+			headers := map[string]string{
+				c.HeaderRateLimitLimit:     strconv.Itoa(tt.limit),
+				c.HeaderRateLimitRemaining: strconv.Itoa(tt.remain),
+				c.HeaderRateLimitReset:     strconv.FormatInt(tt.resetAt.Unix(), 10),
+				c.HeaderRetryAfter:         tt.wantRetryAfter,
+			}
+			if rec.Code != http.StatusTooManyRequests {
+				t.Errorf("status = %d, want 429", rec.Code)
+			}
+			for key, want := range headers {
+				if got := rec.Header().Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRecordingNotFound is returned when replaying an unknown recording
+var ErrRecordingNotFound = errors.New("error recording not found")
+
+// Recording is a full snapshot of an AppError and its trace at the time it was recorded
+type Recording struct {
+	ID             string                 `json:"id"`
+	RecordedAt     time.Time              `json:"recorded_at"`
+	Err            string                 `json:"err"`                // Text of the underlying error, without the contexts
+	Contexts       []string               `json:"contexts,omitempty"` // Annotations added by WrapMsg, oldest first
+	Code           string                 `json:"code"`
+	Message        string                 `json:"message"`
+	InternalMsg    string                 `json:"internal_message,omitempty"`
+	Retryable      bool                   `json:"retryable"`
+	Severity       Severity               `json:"severity,omitempty"`
+	Category       Category               `json:"category,omitempty"`
+	ErrorCodes     []string               `json:"error_codes"`
+	HTTPCode       int                    `json:"http_code"`
+	CustomHTTPCode int                    `json:"custom_http_code,omitempty"` // Default HTTP status of the custom error
+	Headers        http.Header            `json:"headers,omitempty"`          // Extra response headers, e.g. X-RateLimit-*
+	Data           json.RawMessage        `json:"data,omitempty"`
+	RetryAfterMs   int64                  `json:"retry_after_ms,omitempty"` // Retry delay in milliseconds
+	MaxAttempts    int                    `json:"max_attempts,omitempty"`
+	Backoff        BackoffStrategy        `json:"backoff,omitempty"`
+	TraceID        string                 `json:"trace_id,omitempty"`
+	SpanID         string                 `json:"span_id,omitempty"`
+	Fingerprint    string                 `json:"fingerprint,omitempty"`
+	Trace          []TraceEntry           `json:"trace,omitempty"`
+	TraceErrors    []TraceEntry           `json:"trace_errors,omitempty"`
+	Identifiers    map[string]interface{} `json:"identifiers,omitempty"`
+	Stack          []Frame                `json:"stack,omitempty"`
+	WrapSites      []Frame                `json:"wrap_sites,omitempty"`
+	Labels         map[string]string      `json:"labels,omitempty"`
+	Debug          json.RawMessage        `json:"debug,omitempty"`
+}
+
+// AppError rehydrates the recording into a new AppError carrying everything recorded; the
+// underlying error only keeps its text, and data and debug data are decoded into generic JSON values
+func (r *Recording) AppError() *AppError {
+	appErr := &AppError{
+		CustomErr: &CustomErr{
+			Code:      r.Code,
+			Message:   r.Message,
+			Retryable: r.Retryable,
+			Severity:  r.Severity,
+			Category:  r.Category,
+			HTTPCode:  r.CustomHTTPCode,
+		},
+		ErrorCodes:      append([]string{}, r.ErrorCodes...),
+		httpCode:        r.HTTPCode,
+		id:              r.ID,
+		retryAfter:      time.Duration(r.RetryAfterMs) * time.Millisecond,
+		maxAttempts:     r.MaxAttempts,
+		backoff:         r.Backoff,
+		internalMsg:     r.InternalMsg,
+		traceID:         r.TraceID,
+		spanID:          r.SpanID,
+		fingerprint:     r.Fingerprint,
+		contexts:        append([]string(nil), r.Contexts...),
+		replayStack:     append([]Frame(nil), r.Stack...),
+		replayWrapSites: append([]Frame(nil), r.WrapSites...),
+	}
+	for key, value := range r.Labels {
+		appErr.SetLabel(key, value)
+	}
+	if len(r.Headers) > 0 {
+		appErr.headers = r.Headers.Clone()
+	}
+	if len(r.Identifiers) > 0 {
+		appErr.identifiers = make(map[string]interface{}, len(r.Identifiers))
+		for key, value := range r.Identifiers {
+			appErr.identifiers[key] = value
+		}
+	}
+	if r.Err != "" {
+		appErr.ActualErr = errors.New(r.Err)
+	}
+	if len(r.Data) > 0 {
+		var data interface{}
+		if err := json.Unmarshal(r.Data, &data); err == nil {
+			appErr.data = data
+		}
+	}
+	if len(r.Debug) > 0 {
+		var debug map[string]interface{}
+		if err := json.Unmarshal(r.Debug, &debug); err == nil {
+			appErr.debug = debug
+		}
+	}
+	return appErr
+}
+
+// TraceMeta rehydrates the trace captured with the recording
+func (r *Recording) TraceMeta() *TraceMeta {
+	traceMeta := &TraceMeta{
+		Trace:              append([]TraceEntry{}, r.Trace...),
+		Error:              append([]TraceEntry{}, r.TraceErrors...),
+		IdentifierMappings: map[string]interface{}{},
+	}
+	for k, v := range r.Identifiers {
+		traceMeta.IdentifierMappings[k] = v
+	}
+	return traceMeta
+}
+
+// Recorder keeps the most recent recordings in a fixed size ring
+type Recorder struct {
+	mu   sync.RWMutex
+	ring []*Recording
+	next int
+	size int
+}
+
+// NewRecorder creates a Recorder retaining up to capacity recordings
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &Recorder{ring: make([]*Recording, capacity)}
+}
+
+// Record snapshots the AppError together with the trace stored in ctx and returns its ID
+func (rec *Recorder) Record(ctx context.Context, appErr *AppError) string {
+	if appErr == nil {
+		return ""
+	}
+
+	traceMeta, _ := TraceFromContext(ctx)
+	r := newRecording(appErr, traceMeta)
+	rec.add(r)
+	return r.ID
+}
+
+// newRecording snapshots the AppError together with its trace, which may be nil
+func newRecording(appErr *AppError, traceMeta *TraceMeta) *Recording {
+	r := &Recording{
+		ID:           appErr.GetID(),
+		RecordedAt:   time.Now(),
+		ErrorCodes:   append([]string{}, appErr.ErrorCodes...),
+		HTTPCode:     appErr.httpCode,
+		RetryAfterMs: appErr.retryAfter.Milliseconds(),
+		MaxAttempts:  appErr.maxAttempts,
+		Backoff:      appErr.backoff,
+		TraceID:      appErr.traceID,
+		SpanID:       appErr.spanID,
+		Fingerprint:  appErr.Fingerprint(),
+	}
+	if appErr.ActualErr != nil {
+		r.Err = scrub(appErr.ActualErr.Error())
+	}
+	for _, msg := range appErr.contexts {
+		r.Contexts = append(r.Contexts, scrub(msg))
+	}
+	if !currentConfig().ComplianceMode {
+		r.Stack = appErr.GetStackTrace()
+		r.WrapSites = appErr.GetWrapSites()
+		r.InternalMsg = scrub(appErr.internalMsg)
+	}
+	for key, value := range appErr.labels {
+		if r.Labels == nil {
+			r.Labels = make(map[string]string, len(appErr.labels))
+		}
+		r.Labels[key] = value
+	}
+	if appErr.CustomErr != nil {
+		r.Code = appErr.CustomErr.Code
+		r.Message = scrub(appErr.CustomErr.Message)
+		r.Retryable = appErr.CustomErr.Retryable
+		r.CustomHTTPCode = appErr.CustomErr.HTTPCode
+	}
+	if len(appErr.headers) > 0 {
+		r.Headers = appErr.headers.Clone()
+	}
+	r.Severity = appErr.GetSeverity()
+	r.Category = appErr.GetCategory()
+	if appErr.data != nil {
+		if raw, err := json.Marshal(encodeData(context.Background(), appErr.data, false)); err == nil {
+			r.Data = raw
+		} else {
+			r.Data, _ = json.Marshal(fmt.Sprintf("unencodable data: %v", err))
+		}
+	}
+	if appErr.debug != nil {
+		r.Debug, _ = json.Marshal(encodeData(context.Background(), appErr.debug, false))
+	}
+	if traceMeta != nil {
+		traceMeta = traceMeta.Snapshot()
+		r.Trace = traceMeta.Trace
+		r.TraceErrors = traceMeta.Error
+		if currentConfig().ComplianceMode {
+			stripTraceCallers(r.Trace)
+			stripTraceCallers(r.TraceErrors)
+		}
+		if len(traceMeta.IdentifierMappings) > 0 {
+			r.Identifiers = traceMeta.IdentifierMappings
+		}
+	}
+	if r.Identifiers == nil {
+		r.Identifiers = appErr.GetIdentifiers()
+	}
+	return r
+}
+
+// add stores a recording, overwriting the oldest one when the ring is full
+func (rec *Recorder) add(r *Recording) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.ring[rec.next] = r
+	rec.next = (rec.next + 1) % len(rec.ring)
+	if rec.size < len(rec.ring) {
+		rec.size++
+	}
+}
+
+// Get returns the most recent recording with the given ID, e.g. after an error was recorded again
+func (rec *Recorder) Get(id string) (*Recording, bool) {
+	rec.mu.RLock()
+	defer rec.mu.RUnlock()
+
+	for i := 1; i <= rec.size; i++ {
+		r := rec.ring[(rec.next-i+len(rec.ring))%len(rec.ring)]
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Recent returns up to n recordings, newest first; n <= 0 returns all of them
+func (rec *Recorder) Recent(n int) []*Recording {
+	rec.mu.RLock()
+	defer rec.mu.RUnlock()
+
+	if n <= 0 || n > rec.size {
+		n = rec.size
+	}
+	recent := make([]*Recording, 0, n)
+	for i := 1; i <= n; i++ {
+		idx := (rec.next - i + len(rec.ring)) % len(rec.ring)
+		recent = append(recent, rec.ring[idx])
+	}
+	return recent
+}
+
+// Dump writes every retained recording as JSON lines, oldest first
+func (rec *Recorder) Dump(w io.Writer) error {
+	recent := rec.Recent(0)
+	enc := json.NewEncoder(w)
+	for i := len(recent) - 1; i >= 0; i-- {
+		if err := enc.Encode(recent[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads recordings written by Dump into the recorder
+func (rec *Recorder) Load(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for {
+		recording := &Recording{}
+		if err := dec.Decode(recording); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		rec.add(recording)
+	}
+}
+
+// Replay rehydrates the recording with the given ID into a new AppError
+func (rec *Recorder) Replay(id string) (*AppError, error) {
+	r, ok := rec.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRecordingNotFound, id)
+	}
+	return r.AppError(), nil
+}
+
+// recorder is the optional package level recorder, nil when recording is disabled
+var recorder struct {
+	sync.RWMutex
+	rec *Recorder
+}
+
+// SetRecorder installs the package level recorder; nil disables recording
+func SetRecorder(rec *Recorder) {
+	recorder.Lock()
+	recorder.rec = rec
+	recorder.Unlock()
+}
+
+// GetRecorder returns the package level recorder, nil when recording is disabled
+func GetRecorder() *Recorder {
+	recorder.RLock()
+	defer recorder.RUnlock()
+	return recorder.rec
+}
+
+// RecordError snapshots err with the package level recorder when err is an AppError and a
+// recorder is installed, returning the recording ID
+func RecordError(ctx context.Context, err error) string {
+	rec := GetRecorder()
+	appErr, ok := asAppError(err)
+	if rec == nil || !ok {
+		return ""
+	}
+	return rec.Record(ctx, appErr)
+}
+
+// Replay rehydrates a recording of the package level recorder into a new AppError
+func Replay(id string) (*AppError, error) {
+	rec := GetRecorder()
+	if rec == nil {
+		return nil, fmt.Errorf("%w: %s (no recorder installed)", ErrRecordingNotFound, id)
+	}
+	return rec.Replay(id)
+}
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestRecordingReplaysResponse(t *testing.T) {
+	resetAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	tests := []struct {
+		name   string
+		appErr *AppError
+	}{
+		{"rate limited", RateLimited(context.Background(), 100, 0, resetAt)},
+		{
+			name: "custom http code",
+			appErr: GetAppErr(context.Background(), errors.New("locked"),
+				GetCustomErr("ERR_REC_1", "locked", false, WithDefaultHTTPCode(http.StatusLocked)), 0).
+				SetHeader("X-Lock-Owner", "job-7"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder(4)
+			replayed, err := rec.Replay(rec.Record(context.Background(), tt.appErr))
+			if err != nil {
+				t.Fatalf("Replay: %v", err)
+			}
+
+			if !reflect.DeepEqual(replayed.GetHeaders(), tt.appErr.GetHeaders()) {
+				t.Errorf("headers = %v, want %v", replayed.GetHeaders(), tt.appErr.GetHeaders())
+			}
+			if replayed.CustomErr.HTTPCode != tt.appErr.CustomErr.HTTPCode {
+				t.Errorf("custom http code = %d, want %d", replayed.CustomErr.HTTPCode, tt.appErr.CustomErr.HTTPCode)
+			}
+
+			want, got := httptest.NewRecorder(), httptest.NewRecorder()
+			tt.appErr.WriteHTTP(want, nil)
+			replayed.WriteHTTP(got, nil)
+			if got.Code != want.Code {
+				t.Errorf("status = %d, want %d", got.Code, want.Code)
+			}
+			for _, key := range []string{c.HeaderRateLimitLimit, c.HeaderRateLimitReset, c.HeaderRetryAfter, "X-Lock-Owner"} {
+				if got.Header().Get(key) != want.Header().Get(key) {
+					t.Errorf("%s = %q, want %q", key, got.Header().Get(key), want.Header().Get(key))
+				}
+			}
+		})
+	}
+}
+
+func TestRecorderGetNewest(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("boom"), GetCustomErr("ERR_REC_2", "failed", false), 0)
+	other := GetAppErr(context.Background(), errors.New("other"), nil, 0)
+
+	tests := []struct {
+		name      string
+		capacity  int
+		record    []*AppError
+		wantFound bool
+		wantLabel string
+	}{
+		{"single", 4, []*AppError{appErr}, true, "1"},
+		{"recorded again", 4, []*AppError{appErr, other, appErr}, true, "3"},
+		{"older copy evicted", 2, []*AppError{appErr, appErr, other}, true, "2"},
+		{"evicted", 2, []*AppError{appErr, other, other}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder(tt.capacity)
+			for i, e := range tt.record {
+				e.SetLabel("n", string(rune('1'+i)))
+				rec.Record(context.Background(), e)
+			}
+			r, ok := rec.Get(appErr.GetID())
+			if ok != tt.wantFound {
+				t.Fatalf("Get found = %v, want %v", ok, tt.wantFound)
+			}
+			if ok && r.Labels["n"] != tt.wantLabel {
+				t.Errorf("Get returned recording %s, want %s", r.Labels["n"], tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestRecorderDumpLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		count    int
+		want     int
+	}{
+		{"empty", 4, 0, 0},
+		{"partial", 4, 2, 2},
+		{"wrapped", 3, 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := NewRecorder(tt.capacity)
+			for i := 0; i < tt.count; i++ {
+				rec.Record(context.Background(), GetAppErr(context.Background(), errors.New("boom"), nil, 0))
+			}
+
+			var buf bytes.Buffer
+			if err := rec.Dump(&buf); err != nil {
+				t.Fatalf("Dump: %v", err)
+			}
+			loaded := NewRecorder(tt.capacity)
+			if err := loaded.Load(&buf); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			want, got := rec.Recent(0), loaded.Recent(0)
+			if len(got) != tt.want {
+				t.Fatalf("loaded %d recordings, want %d", len(got), tt.want)
+			}
+			for i := range want {
+				if got[i].ID != want[i].ID {
+					t.Errorf("recording %d = %s, want %s", i, got[i].ID, want[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SensitiveValue is a data field whose value is redacted in every output: client responses,
+// logs, recordings and exports. Only the Value field itself gives access to the raw value
+type SensitiveValue struct {
+	Key   string
+	Value interface{}
+}
+
+// Sensitive marks a data field as sensitive, e.g. SetData(ae.Sensitive("password", v)); it is
+// rendered as {"password": "[REDACTED]"}, also when the data is marshalled, printed or logged directly
+func Sensitive(key string, value interface{}) SensitiveValue {
+	return SensitiveValue{Key: key, Value: value}
+}
+
+// MarshalJSON encodes the field with its value redacted
+func (v SensitiveValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{v.Key: redacted})
+}
+
+// String renders the field with its value redacted
+func (v SensitiveValue) String() string {
+	return v.Key + "=" + redacted
+}
+
+// Format renders the field with its value redacted for every verb, so %v and %+v never print it
+func (v SensitiveValue) Format(s fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprintf(s, "%q", v.String())
+		return
+	}
+	fmt.Fprint(s, v.String())
+}
+
+// LogValue renders the field with its value redacted when it is logged with slog
+func (v SensitiveValue) LogValue() slog.Value {
+	return slog.GroupValue(slog.String(v.Key, redacted))
+}
+
+// sensitiveKeys holds the data keys redacted at any depth, lower-cased
+var sensitiveKeys = struct {
+	sync.RWMutex
+	keys map[string]struct{}
+}{keys: map[string]struct{}{}}
+
+// defaultSensitiveKeys are the data keys redacted without registration
+var defaultSensitiveKeys = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token", "authorization", "api_key",
+}
+
+func init() {
+	RedactKeys(defaultSensitiveKeys...)
+}
+
+// RedactKeys marks data keys (case-insensitive) as sensitive at any depth; their values are
+// replaced by "[REDACTED]" in every output while the key itself is kept
+func RedactKeys(keys ...string) {
+	sensitiveKeys.Lock()
+	defer sensitiveKeys.Unlock()
+	for _, key := range keys {
+		sensitiveKeys.keys[strings.ToLower(key)] = struct{}{}
+	}
+}
+
+// isSensitiveKey reports whether values of a data key must be redacted
+func isSensitiveKey(key string) bool {
+	sensitiveKeys.RLock()
+	defer sensitiveKeys.RUnlock()
+	_, ok := sensitiveKeys.keys[strings.ToLower(key)]
+	return ok
+}
+
+// EmailSecretPattern returns a pattern scrubbing e-mail addresses; it is not active by default,
+// enable it with RegisterSecretPattern(ae.EmailSecretPattern())
+func EmailSecretPattern() SecretPattern {
+	return SecretPattern{
+		Name:        "email",
+		Regexp:      regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+		Replacement: redacted,
+	}
+}
+
+// sensitiveValueType is the reflected type of SensitiveValue
+var sensitiveValueType = reflect.TypeOf(SensitiveValue{})
+
+// asSensitiveValue returns the SensitiveValue held by v, looking through interfaces
+func asSensitiveValue(v reflect.Value) (SensitiveValue, bool) {
+	for v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Type() != sensitiveValueType {
+		return SensitiveValue{}, false
+	}
+	return v.Interface().(SensitiveValue), true
+}
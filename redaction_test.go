@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveValueRendering(t *testing.T) {
+	secret := Sensitive("password", "hunter2")
+
+	tests := []struct {
+		name   string
+		render func() string
+		want   string
+	}{
+		{"String", secret.String, "password=[REDACTED]"},
+		{"%v", func() string { return fmt.Sprintf("%v", secret) }, "password=[REDACTED]"},
+		{"%+v", func() string { return fmt.Sprintf("%+v", secret) }, "password=[REDACTED]"},
+		{"%#v", func() string { return fmt.Sprintf("%#v", secret) }, "password=[REDACTED]"},
+		{"%q", func() string { return fmt.Sprintf("%q", secret) }, `"password=[REDACTED]"`},
+		{"JSON", func() string { b, _ := json.Marshal(secret); return string(b) }, `{"password":"[REDACTED]"}`},
+		{"slog", func() string {
+			var buf bytes.Buffer
+			slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})).Info("login", "data", secret)
+			return strings.TrimSpace(buf.String())
+		}, `{"data":{"password":"[REDACTED]"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.render(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactKeys(t *testing.T) {
+	RedactKeys("X-Card-Number")
+
+	type credentials struct {
+		User  string `json:"user"`
+		Token string `json:"token"`
+	}
+
+	tests := []struct {
+		name string
+		data interface{}
+		want interface{}
+	}{
+		{"default key", map[string]interface{}{"password": "hunter2", "user": "ada"},
+			map[string]interface{}{"password": redacted, "user": "ada"}},
+		{"case insensitive", map[string]interface{}{"Authorization": "Bearer abc"},
+			map[string]interface{}{"Authorization": redacted}},
+		{"registered key", map[string]interface{}{"x-card-number": "4111"},
+			map[string]interface{}{"x-card-number": redacted}},
+		{"nested", map[string]interface{}{"login": map[string]interface{}{"api_key": "k1", "id": 7}},
+			map[string]interface{}{"login": map[string]interface{}{"api_key": redacted, "id": 7}}},
+		{"struct field", credentials{User: "ada", Token: "t1"},
+			map[string]interface{}{"user": "ada", "token": redacted}},
+		{"sensitive value", map[string]interface{}{"pin": Sensitive("pin", 1234)},
+			map[string]interface{}{"pin": map[string]interface{}{"pin": redacted}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeData(context.Background(), tt.data, true); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("encodeData = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
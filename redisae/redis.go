@@ -0,0 +1,63 @@
+package redisae
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Custom errors Redis failures map to
+var (
+	ErrCacheMiss = ae.GetCustomErr("ERR_CACHE_MISS", "key not found", false,
+		ae.WithCategory(ae.CategoryNotFound))
+	ErrCacheUnavailable = ae.GetCustomErr("ERR_CACHE_UNAVAILABLE", "cache is unavailable", true,
+		ae.WithCategory(ae.CategoryUnavailable))
+	ErrCacheCommand = ae.GetCustomErr("ERR_CACHE_COMMAND", "cache command failed", false,
+		ae.WithCategory(ae.CategoryInternal))
+)
+
+// transientPrefixes are the prefixes of server errors that go away on their own, e.g. while a
+// replica loads its dataset or a cluster fails over
+var transientPrefixes = []string{"LOADING", "READONLY", "MASTERDOWN", "CLUSTERDOWN", "TRYAGAIN", "max number of clients"}
+
+// FromRedisError converts a go-redis error into an AppError so cache layers fail consistently:
+// redis.Nil becomes a 404 ErrCacheMiss, connection failures, pool and network timeouts and
+// transient server states a retryable 503 ErrCacheUnavailable, canceled requests a 499 and
+// other command errors a 500 ErrCacheCommand; nil stays nil and AppErrors are returned unchanged
+func FromRedisError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	return ae.New(ctx, err, ae.WithCustomErr(Classify(err)), ae.WithStackSkip(1))
+}
+
+// Classify returns the custom error a Redis error maps to
+func Classify(err error) *ae.CustomErr {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, redis.Nil):
+		return ErrCacheMiss
+	case errors.Is(err, context.Canceled):
+		return ae.ClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, redis.ErrPoolTimeout),
+		errors.Is(err, redis.ErrPoolExhausted), errors.Is(err, redis.ErrClosed),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &netErr):
+		return ErrCacheUnavailable
+	}
+	for _, prefix := range transientPrefixes {
+		if redis.HasErrorPrefix(err, prefix) {
+			return ErrCacheUnavailable
+		}
+	}
+	return ErrCacheCommand
+}
@@ -0,0 +1,89 @@
+package redisae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// serverErr is an error reply of the Redis server
+type serverErr string
+
+func (e serverErr) Error() string { return string(e) }
+func (serverErr) RedisError()     {}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *ae.CustomErr
+	}{
+		{"nil reply", redis.Nil, ErrCacheMiss},
+		{"wrapped nil reply", fmt.Errorf("get session: %w", redis.Nil), ErrCacheMiss},
+		{"canceled", context.Canceled, ae.ClientClosedRequest},
+		{"deadline", context.DeadlineExceeded, ErrCacheUnavailable},
+		{"pool timeout", redis.ErrPoolTimeout, ErrCacheUnavailable},
+		{"pool exhausted", redis.ErrPoolExhausted, ErrCacheUnavailable},
+		{"closed client", redis.ErrClosed, ErrCacheUnavailable},
+		{"connection dropped", io.EOF, ErrCacheUnavailable},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrCacheUnavailable},
+		{"loading", serverErr("LOADING Redis is loading the dataset in memory"), ErrCacheUnavailable},
+		{"read-only replica", serverErr("READONLY You can't write against a read only replica."), ErrCacheUnavailable},
+		{"cluster down", serverErr("CLUSTERDOWN The cluster is down"), ErrCacheUnavailable},
+		{"wrong type", serverErr("WRONGTYPE Operation against a key holding the wrong kind of value"), ErrCacheCommand},
+		{"other error", errors.New("boom"), ErrCacheCommand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromRedisError(t *testing.T) {
+	existing := ae.GetAppErr(context.Background(), redis.Nil, ErrCacheMiss, 0)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{"cache miss", redis.Nil, "ERR_CACHE_MISS", http.StatusNotFound, false},
+		{"unavailable", redis.ErrPoolTimeout, "ERR_CACHE_UNAVAILABLE", http.StatusServiceUnavailable, true},
+		{"canceled", context.Canceled, ae.ClientClosedRequest.Code, ae.StatusClientClosedRequest, false},
+		{"command", serverErr("ERR unknown command"), "ERR_CACHE_COMMAND", http.StatusInternalServerError, false},
+		{"AppError", existing, "ERR_CACHE_MISS", http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromRedisError(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("FromRedisError = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromRedisError = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("FromRedisError lost the underlying error %v", tt.err)
+			}
+		})
+	}
+
+	if err := FromRedisError(context.Background(), nil); err != nil {
+		t.Errorf("FromRedisError(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,261 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrInvalidCustomErr is returned when registering a nil CustomErr or one without a code
+	ErrInvalidCustomErr = errors.New("custom error must have a non-empty code")
+	// ErrDuplicateCode is returned when a code is registered more than once
+	ErrDuplicateCode = errors.New("error code already registered")
+	// ErrCodeReserved is returned when a code falls inside a range reserved by another owner
+	ErrCodeReserved = errors.New("error code falls inside a range reserved by another owner")
+	// ErrInvalidRange is returned when a range is malformed or overlaps an existing reservation
+	ErrInvalidRange = errors.New("invalid code range")
+)
+
+// CodeRange represents a block of numeric error codes reserved for an owner (team or module)
+type CodeRange struct {
+	Owner string // Team or module owning the range
+	From  int    // First code number of the range (inclusive)
+	To    int    // Last code number of the range (inclusive)
+}
+
+// Contains reports whether the given code number lies within the range
+func (r CodeRange) Contains(n int) bool {
+	return n >= r.From && n <= r.To
+}
+
+// RegistryEntry describes a registered custom error
+type RegistryEntry struct {
+	Code      string   `json:"code"`
+	Message   string   `json:"message"`
+	Retryable bool     `json:"retryable"`
+	Category  Category `json:"category,omitempty"`
+	HTTPCode  int      `json:"http_code,omitempty"` // Default HTTP status of the error, 0 when it has none
+	Owner     string   `json:"owner,omitempty"`
+}
+
+// RangeUsage reports the utilization of a reserved code range
+type RangeUsage struct {
+	CodeRange
+	Capacity int      // Number of codes the range can hold
+	Used     int      // Number of registered codes inside the range
+	Codes    []string // Registered codes inside the range, sorted
+}
+
+// Registry holds registered custom errors keyed by code along with reserved code ranges
+type Registry struct {
+	mu     sync.RWMutex
+	errs   map[string]*CustomErr // Registered custom errors keyed by code
+	owners map[string]string     // Owner of each registered code, empty when anonymous
+	ranges []CodeRange           // Reserved ranges, kept sorted by From
+}
+
+// NewRegistry creates a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		errs:   map[string]*CustomErr{},
+		owners: map[string]string{},
+	}
+}
+
+// defaultRegistry backs the package level registry functions
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the package level registry
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// ReserveRange reserves the numeric codes from..to (inclusive) for the given owner
+func (r *Registry) ReserveRange(owner string, from, to int) error {
+	if owner == "" || from < 0 || from > to {
+		return fmt.Errorf("%w: %q %d-%d", ErrInvalidRange, owner, from, to)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	newRange := CodeRange{Owner: owner, From: from, To: to}
+	for _, existing := range r.ranges {
+		if from <= existing.To && existing.From <= to {
+			return fmt.Errorf("%w: %d-%d overlaps %d-%d reserved by %q",
+				ErrInvalidRange, from, to, existing.From, existing.To, existing.Owner)
+		}
+	}
+
+	// Codes already registered by someone else must not end up inside the new range
+	for code, codeOwner := range r.owners {
+		if n, ok := codeNumber(code); ok && newRange.Contains(n) && codeOwner != owner {
+			return fmt.Errorf("%w: %d-%d contains %s already registered", ErrInvalidRange, from, to, code)
+		}
+	}
+
+	r.ranges = append(r.ranges, newRange)
+	sort.Slice(r.ranges, func(i, j int) bool { return r.ranges[i].From < r.ranges[j].From })
+	return nil
+}
+
+// Register adds a custom error without an owner; its code must not fall inside a reserved range
+func (r *Registry) Register(customErr *CustomErr) error {
+	return r.RegisterFor("", customErr)
+}
+
+// RegisterFor adds a custom error on behalf of an owner; when the owner has reserved ranges the
+// code must fall inside one of them, and it may never fall inside a range reserved by someone else
+func (r *Registry) RegisterFor(owner string, customErr *CustomErr) error {
+	if customErr == nil || customErr.Code == "" {
+		return ErrInvalidCustomErr
+	}
+	if err := checkCode(customErr.Code); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code := customErr.Code
+	if _, exists := r.errs[code]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateCode, code)
+	}
+
+	if err := r.checkRange(owner, code); err != nil {
+		return err
+	}
+
+	r.errs[code] = customErr
+	r.owners[code] = owner
+	return nil
+}
+
+// Lookup returns the custom error registered under code
+func (r *Registry) Lookup(code string) (*CustomErr, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	customErr, ok := r.errs[code]
+	return customErr, ok
+}
+
+// Entries returns every registered custom error, ordered by code
+func (r *Registry) Entries() []RegistryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]RegistryEntry, 0, len(r.errs))
+	for code, customErr := range r.errs {
+		entries = append(entries, RegistryEntry{
+			Code:      code,
+			Message:   customErr.Message,
+			Retryable: customErr.Retryable,
+			Category:  customErr.Category,
+			HTTPCode:  customErr.DefaultHTTPCode(),
+			Owner:     r.owners[code],
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// RangeReport returns the utilization of every reserved range, ordered by range start
+func (r *Registry) RangeReport() []RangeUsage {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	report := make([]RangeUsage, 0, len(r.ranges))
+	for _, cr := range r.ranges {
+		usage := RangeUsage{CodeRange: cr, Capacity: cr.To - cr.From + 1, Codes: []string{}}
+		for code := range r.errs {
+			if n, ok := codeNumber(code); ok && cr.Contains(n) {
+				usage.Codes = append(usage.Codes, code)
+			}
+		}
+		sort.Strings(usage.Codes)
+		usage.Used = len(usage.Codes)
+		report = append(report, usage)
+	}
+	return report
+}
+
+// checkRange validates a code against the reserved ranges; callers must hold the lock
+func (r *Registry) checkRange(owner, code string) error {
+	n, numeric := codeNumber(code)
+	ownsRange := false
+
+	for _, cr := range r.ranges {
+		if cr.Owner == owner {
+			ownsRange = true
+		}
+		if !numeric || !cr.Contains(n) {
+			continue
+		}
+		if cr.Owner != owner {
+			return fmt.Errorf("%w: %s belongs to %q (%d-%d)", ErrCodeReserved, code, cr.Owner, cr.From, cr.To)
+		}
+		return nil
+	}
+
+	// An owner with reservations must stay inside them
+	if owner != "" && ownsRange {
+		return fmt.Errorf("%w: %s is outside the ranges reserved by %q", ErrCodeReserved, code, owner)
+	}
+	return nil
+}
+
+// codeNumber extracts the trailing numeric part of an error code, e.g. 1001 for "ERR_SVC_1001"
+func codeNumber(code string) (int, bool) {
+	i := len(code)
+	for i > 0 && code[i-1] >= '0' && code[i-1] <= '9' {
+		i--
+	}
+	if i == len(code) {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(code[i:])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// ReserveRange reserves a code range in the default registry
+func ReserveRange(owner string, from, to int) error {
+	return defaultRegistry.ReserveRange(owner, from, to)
+}
+
+// Register adds a custom error to the default registry
+func Register(customErr *CustomErr) error {
+	return defaultRegistry.Register(customErr)
+}
+
+// MustRegister adds custom errors to the default registry and panics when one cannot be registered,
+// e.g. because its code is already taken; it is meant for package level var blocks and init
+// functions, so reused codes fail at startup
+func MustRegister(customErrs ...*CustomErr) {
+	for _, customErr := range customErrs {
+		if err := defaultRegistry.Register(customErr); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Lookup returns the custom error registered under code in the default registry
+func Lookup(code string) (*CustomErr, bool) {
+	return defaultRegistry.Lookup(code)
+}
+
+// RegisterFor adds a custom error on behalf of an owner to the default registry
+func RegisterFor(owner string, customErr *CustomErr) error {
+	return defaultRegistry.RegisterFor(owner, customErr)
+}
+
+// RangeReport returns the range utilization of the default registry
+func RangeReport() []RangeUsage {
+	return defaultRegistry.RangeReport()
+}
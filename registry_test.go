@@ -0,0 +1,153 @@
+package errors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReserveRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    string
+		from, to int
+		want     error
+	}{
+		{"valid", "billing", 3000, 3999, nil},
+		{"adjacent", "search", 4000, 4999, nil},
+		{"no owner", "", 5000, 5999, ErrInvalidRange},
+		{"negative", "shipping", -1, 10, ErrInvalidRange},
+		{"reversed", "shipping", 20, 10, ErrInvalidRange},
+		{"overlapping", "shipping", 3500, 4500, ErrInvalidRange},
+		{"contains a foreign code", "shipping", 1000, 1999, ErrInvalidRange},
+	}
+
+	r := NewRegistry()
+	if err := r.Register(&CustomErr{Code: "ERR_ANON_1001"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.ReserveRange(tt.owner, tt.from, tt.to); !errors.Is(err, tt.want) {
+				t.Errorf("ReserveRange(%q, %d, %d) = %v, want %v", tt.owner, tt.from, tt.to, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterForRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		owner string
+		code  string
+		want  error
+	}{
+		{"inside own range", "billing", "ERR_BILLING_3001", nil},
+		{"outside own range", "billing", "ERR_BILLING_5001", ErrCodeReserved},
+		{"inside foreign range", "search", "ERR_SEARCH_3002", ErrCodeReserved},
+		{"anonymous inside a range", "", "ERR_ANON_3003", ErrCodeReserved},
+		{"anonymous outside ranges", "", "ERR_ANON_5001", nil},
+		{"owner without ranges", "search", "ERR_SEARCH_6001", nil},
+		{"non numeric code", "billing", "ERR_BILLING", ErrCodeReserved},
+		{"duplicate", "billing", "ERR_BILLING_3001", ErrDuplicateCode},
+		{"empty code", "billing", "", ErrInvalidCustomErr},
+	}
+
+	r := NewRegistry()
+	if err := r.ReserveRange("billing", 3000, 3999); err != nil {
+		t.Fatalf("ReserveRange: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.RegisterFor(tt.owner, &CustomErr{Code: tt.code}); !errors.Is(err, tt.want) {
+				t.Errorf("RegisterFor(%q, %s) = %v, want %v", tt.owner, tt.code, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRangeReport(t *testing.T) {
+	r := NewRegistry()
+	for _, reservation := range []CodeRange{{"billing", 3000, 3009}, {"search", 100, 199}} {
+		if err := r.ReserveRange(reservation.Owner, reservation.From, reservation.To); err != nil {
+			t.Fatalf("ReserveRange: %v", err)
+		}
+	}
+	for _, code := range []string{"ERR_BILLING_3002", "ERR_BILLING_3001"} {
+		if err := r.RegisterFor("billing", &CustomErr{Code: code}); err != nil {
+			t.Fatalf("RegisterFor: %v", err)
+		}
+	}
+
+	want := []RangeUsage{
+		{CodeRange: CodeRange{"search", 100, 199}, Capacity: 100, Codes: []string{}},
+		{CodeRange: CodeRange{"billing", 3000, 3009}, Capacity: 10, Used: 2, Codes: []string{"ERR_BILLING_3001", "ERR_BILLING_3002"}},
+	}
+	if got := r.RangeReport(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RangeReport() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRegisterLookup(t *testing.T) {
+	first := &CustomErr{Code: "ERR_REG_1001", Message: "first"}
+
+	tests := []struct {
+		name      string
+		customErr *CustomErr
+		want      error
+		lookup    *CustomErr // Custom error registered under the code afterwards
+	}{
+		{"new code", first, nil, first},
+		{"duplicate code", &CustomErr{Code: "ERR_REG_1001", Message: "second"}, ErrDuplicateCode, first},
+		{"nil", nil, ErrInvalidCustomErr, nil},
+		{"empty code", &CustomErr{Message: "anonymous"}, ErrInvalidCustomErr, nil},
+	}
+
+	r := NewRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Register(tt.customErr); !errors.Is(err, tt.want) {
+				t.Errorf("Register = %v, want %v", err, tt.want)
+			}
+			if tt.customErr == nil {
+				return
+			}
+			got, ok := r.Lookup(tt.customErr.Code)
+			if got != tt.lookup || ok != (tt.lookup != nil) {
+				t.Errorf("Lookup(%q) = %v, %v, want %v", tt.customErr.Code, got, ok, tt.lookup)
+			}
+		})
+	}
+}
+
+func TestMustRegister(t *testing.T) {
+	registered := &CustomErr{Code: "ERR_REG_2001", Message: "registered"}
+	MustRegister(registered)
+
+	tests := []struct {
+		name       string
+		customErrs []*CustomErr
+		wantPanic  bool
+	}{
+		{"new codes", []*CustomErr{{Code: "ERR_REG_2002"}, {Code: "ERR_REG_2003"}}, false},
+		{"reused code", []*CustomErr{{Code: "ERR_REG_2001", Message: "reused"}}, true},
+		{"reused within the call", []*CustomErr{{Code: "ERR_REG_2004"}, {Code: "ERR_REG_2004"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("MustRegister panic = %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+			MustRegister(tt.customErrs...)
+		})
+	}
+
+	if got, ok := Lookup("ERR_REG_2001"); !ok || got != registered {
+		t.Errorf("Lookup = %v, %v, want the first registration", got, ok)
+	}
+	if _, ok := Lookup("ERR_REG_9999"); ok {
+		t.Error("Lookup found an unregistered code")
+	}
+}
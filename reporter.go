@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"context"
+	"regexp"
+)
+
+// Reporter delivers AppErrors to an external system (error tracker, alerting, chat)
+type Reporter interface {
+	Report(ctx context.Context, appErr *AppError) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface
+type ReporterFunc func(ctx context.Context, appErr *AppError) error
+
+// Report implements Reporter
+func (f ReporterFunc) Report(ctx context.Context, appErr *AppError) error {
+	return f(ctx, appErr)
+}
+
+// GroupingStrategy computes the key under which a backend groups occurrences of an error
+type GroupingStrategy interface {
+	GroupKey(appErr *AppError) string
+}
+
+// GroupingFunc adapts a function to the GroupingStrategy interface
+type GroupingFunc func(appErr *AppError) string
+
+// GroupKey implements GroupingStrategy
+func (f GroupingFunc) GroupKey(appErr *AppError) string {
+	return f(appErr)
+}
+
+// Built-in grouping strategies
+var (
+	// GroupByCode groups every occurrence of a primary code together
+	GroupByCode GroupingStrategy = GroupingFunc(func(appErr *AppError) string {
+		return primaryCode(appErr)
+	})
+
+	// GroupByCodeAndTopFrame separates occurrences of a code by the function that created them
+	GroupByCodeAndTopFrame GroupingStrategy = GroupingFunc(func(appErr *AppError) string {
+		frame, ok := appErr.topFrame()
+		if !ok {
+			return primaryCode(appErr)
+		}
+		return primaryCode(appErr) + "|" + frame.Function
+	})
+
+	// GroupByNormalizedMessage separates occurrences of a code by their underlying error text with
+	// volatile parts (numbers, hex identifiers, UUIDs, quoted values) replaced by placeholders
+	GroupByNormalizedMessage GroupingStrategy = GroupingFunc(func(appErr *AppError) string {
+		return primaryCode(appErr) + "|" + NormalizeMessage(appErr.Error())
+	})
+)
+
+// Patterns of volatile message parts, applied in order
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexPattern    = regexp.MustCompile(`\b0x[0-9a-fA-F]+\b|\b[0-9a-fA-F]{8,}\b`)
+	quotedPattern = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	numberPattern = regexp.MustCompile(`\d+`)
+)
+
+// NormalizeMessage replaces the volatile parts of an error message with placeholders
+func NormalizeMessage(msg string) string {
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = hexPattern.ReplaceAllString(msg, "<hex>")
+	msg = quotedPattern.ReplaceAllString(msg, "<str>")
+	return numberPattern.ReplaceAllString(msg, "<n>")
+}
+
+// primaryCode returns the primary code of the AppError, empty when it has none
+func primaryCode(appErr *AppError) string {
+	if appErr.CustomErr == nil {
+		return ""
+	}
+	return appErr.CustomErr.Code
+}
+
+// groupKeyCtxKey is the context key carrying the group key computed for a reporter
+type groupKeyCtxKey struct{}
+
+// WithGrouping wraps a reporter so every report carries a group key computed by the given strategy,
+// retrievable by the reporter through GroupKeyFromContext
+func WithGrouping(reporter Reporter, strategy GroupingStrategy) Reporter {
+	return ReporterFunc(func(ctx context.Context, appErr *AppError) error {
+		return reporter.Report(context.WithValue(ctx, groupKeyCtxKey{}, strategy.GroupKey(appErr)), appErr)
+	})
+}
+
+// GroupKeyFromContext returns the group key attached by WithGrouping
+func GroupKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(groupKeyCtxKey{}).(string)
+	return key, ok
+}
+
+// GroupKey returns the key attached by WithGrouping, falling back to the given strategy
+func GroupKey(ctx context.Context, appErr *AppError, fallback GroupingStrategy) string {
+	if key, ok := GroupKeyFromContext(ctx); ok {
+		return key
+	}
+	return fallback.GroupKey(appErr)
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"order not found", "order not found"},
+		{"order 42 not found", "order <n> not found"},
+		{"user 3f2b8c1e-9d4a-4c1b-8e2f-1a2b3c4d5e6f missing", "user <uuid> missing"},
+		{"object deadbeefcafe missing at 0x1f", "object <hex> missing at <hex>"},
+		{`key "tenant-7" and 'x' rejected`, "key <str> and <str> rejected"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeMessage(tt.in); got != tt.want {
+			t.Errorf("NormalizeMessage(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// groupingSite creates an AppError from a call site distinct from the test function
+func groupingSite(msg string) *AppError {
+	return GetAppErr(context.Background(), errors.New(msg), GetCustomErr("ERR_GRP_1", "failed", false), 500)
+}
+
+func TestGroupingStrategies(t *testing.T) {
+	a := GetAppErr(context.Background(), errors.New("order 1 failed"), GetCustomErr("ERR_GRP_1", "failed", false), 500)
+	b := GetAppErr(context.Background(), errors.New("order 2 failed"), GetCustomErr("ERR_GRP_1", "failed", false), 500)
+	elsewhere := groupingSite("order 3 failed")
+	otherText := GetAppErr(context.Background(), errors.New("payment declined"), GetCustomErr("ERR_GRP_1", "failed", false), 500)
+
+	tests := []struct {
+		name      string
+		strategy  GroupingStrategy
+		x, y      *AppError
+		wantEqual bool
+	}{
+		{"code groups everything", GroupByCode, a, elsewhere, true},
+		{"top frame splits call sites", GroupByCodeAndTopFrame, a, elsewhere, false},
+		{"top frame keeps one call site", GroupByCodeAndTopFrame, a, b, true},
+		{"normalized message ignores numbers", GroupByNormalizedMessage, a, elsewhere, true},
+		{"normalized message splits texts", GroupByNormalizedMessage, a, otherText, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := tt.strategy.GroupKey(tt.x), tt.strategy.GroupKey(tt.y)
+			if (x == y) != tt.wantEqual {
+				t.Errorf("keys %q and %q, want equal %v", x, y, tt.wantEqual)
+			}
+			if !strings.HasPrefix(x, "ERR_GRP_1") {
+				t.Errorf("key %q does not start with the code", x)
+			}
+		})
+	}
+}
+
+func TestWithGrouping(t *testing.T) {
+	appErr := groupingSite("order 7 failed")
+	tests := []struct {
+		name     string
+		strategy GroupingStrategy
+		want     string
+	}{
+		{"fallback", nil, GroupByCode.GroupKey(appErr)},
+		{"normalized message", GroupByNormalizedMessage, "ERR_GRP_1|order <n> failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var reporter Reporter = ReporterFunc(func(ctx context.Context, appErr *AppError) error {
+				got = GroupKey(ctx, appErr, GroupByCode)
+				return nil
+			})
+			if tt.strategy != nil {
+				reporter = WithGrouping(reporter, tt.strategy)
+			}
+			if err := reporter.Report(context.Background(), appErr); err != nil {
+				t.Fatalf("Report: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GroupKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
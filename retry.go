@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Backoff returns how long to wait before the given retry, counted from 1
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits the same delay before every retry
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay with every retry, starting at base and capped at max
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		delay := base
+		for i := 1; i < retry && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay
+	}
+}
+
+// RetryOption configures Retry
+type RetryOption func(*retryOptions)
+
+// retryOptions holds the settings of a Retry call
+type retryOptions struct {
+	maxAttempts int
+	backoff     Backoff
+}
+
+// WithMaxAttempts sets how many times fn is called at most, retries included (3 by default)
+func WithMaxAttempts(n int) RetryOption {
+	return func(o *retryOptions) {
+		if n > 0 {
+			o.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the delay between attempts (exponential from 100ms up to 5s by default)
+func WithBackoff(backoff Backoff) RetryOption {
+	return func(o *retryOptions) {
+		if backoff != nil {
+			o.backoff = backoff
+		}
+	}
+}
+
+// Retry calls fn until it succeeds, fails with an error that is not retryable or the attempts are
+// used up, and returns the last error. An error is retryable when it is a retryable AppError or
+// wraps a retryable CustomErr; a Retry-After hint on the AppError extends the backoff delay. Every
+// failed attempt is added to the trace of ctx, and waiting stops with ctx.Err() once ctx is done
+func Retry(ctx context.Context, fn func(ctx context.Context) error, opts ...RetryOption) error {
+	o := &retryOptions{
+		maxAttempts: 3,
+		backoff:     ExponentialBackoff(100*time.Millisecond, 5*time.Second),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		AddTraceLogf(ctx, "attempt %d/%d failed: %v", attempt, o.maxAttempts, err)
+		if attempt >= o.maxAttempts || !retryable(err) {
+			return err
+		}
+
+		delay := o.backoff(attempt)
+		if appErr, ok := asAppError(err); ok && appErr.retryAfter > delay {
+			delay = appErr.retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// retryable reports whether err is a retryable AppError or wraps a retryable CustomErr
+func retryable(err error) bool {
+	if appErr, ok := asAppError(err); ok {
+		return appErr.IsRetryable()
+	}
+	var customErr *CustomErr
+	return errors.As(err, &customErr) && customErr.Retryable
+}
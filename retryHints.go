@@ -0,0 +1,59 @@
+package errors
+
+import "math"
+
+// BackoffStrategy tells clients how to space out their retries
+type BackoffStrategy string
+
+// Backoff strategies advertised in retry hints
+const (
+	BackoffConstant    BackoffStrategy = "constant"    // Wait the same delay between attempts
+	BackoffLinear      BackoffStrategy = "linear"      // Grow the delay linearly with each attempt
+	BackoffExponential BackoffStrategy = "exponential" // Double the delay with each attempt
+)
+
+// retryHint is the "retry" object of the JSON body, present when retry hints are set
+type retryHint struct {
+	AfterSeconds int64           `json:"after_seconds,omitempty"`
+	MaxAttempts  int             `json:"max_attempts,omitempty"`
+	Backoff      BackoffStrategy `json:"backoff,omitempty"`
+}
+
+// IsRetryable reports whether the error condition can be retried
+func (e *AppError) IsRetryable() bool {
+	return e.CustomErr != nil && e.CustomErr.Retryable
+}
+
+// GetMaxAttempts retrieves how many attempts in total clients should make, 0 when unset
+func (e *AppError) GetMaxAttempts() int {
+	return e.maxAttempts
+}
+
+// SetMaxAttempts updates how many attempts in total clients should make and returns the AppError
+func (e *AppError) SetMaxAttempts(n int) *AppError {
+	e.maxAttempts = n
+	return e
+}
+
+// GetBackoff retrieves the backoff strategy clients should use between retries, empty when unset
+func (e *AppError) GetBackoff() BackoffStrategy {
+	return e.backoff
+}
+
+// SetBackoff updates the backoff strategy clients should use between retries and returns the AppError
+func (e *AppError) SetBackoff(strategy BackoffStrategy) *AppError {
+	e.backoff = strategy
+	return e
+}
+
+// retryHint returns the retry hints of a retryable AppError, nil when there are none
+func (e *AppError) retryHint() *retryHint {
+	if !e.IsRetryable() || (e.retryAfter <= 0 && e.maxAttempts <= 0 && e.backoff == "") {
+		return nil
+	}
+	hint := &retryHint{MaxAttempts: e.maxAttempts, Backoff: e.backoff}
+	if e.retryAfter > 0 {
+		hint.AfterSeconds = int64(math.Ceil(e.retryAfter.Seconds()))
+	}
+	return hint
+}
@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+func TestRetryHint(t *testing.T) {
+	ctx := context.Background()
+	busy := GetCustomErr("ERR_RTH_1", "try again", true)
+	gone := GetCustomErr("ERR_RTH_2", "gone", false)
+
+	tests := []struct {
+		name       string
+		err        *AppError
+		want       *retryHint
+		wantHeader string
+	}{
+		{"no hints", GetAppErr(ctx, errors.New("busy"), busy, 503), nil, ""},
+		{"retry after rounded up", GetAppErr(ctx, errors.New("busy"), busy, 503).SetRetryAfter(1100 * time.Millisecond),
+			&retryHint{AfterSeconds: 2}, "2"},
+		{"all hints", GetAppErr(ctx, errors.New("busy"), busy, 503).SetRetryAfter(3 * time.Second).SetMaxAttempts(5).SetBackoff(BackoffExponential),
+			&retryHint{AfterSeconds: 3, MaxAttempts: 5, Backoff: BackoffExponential}, "3"},
+		{"attempts only", GetAppErr(ctx, errors.New("busy"), busy, 503).SetMaxAttempts(2),
+			&retryHint{MaxAttempts: 2}, ""},
+		{"permanent error", GetAppErr(ctx, errors.New("gone"), gone, 410).SetRetryAfter(time.Second).SetBackoff(BackoffLinear),
+			nil, "1"},
+		{"without custom error", (&AppError{ActualErr: errors.New("bare")}).SetMaxAttempts(3), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.retryHint(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("retryHint = %+v, want %+v", got, tt.want)
+			}
+
+			rec := httptest.NewRecorder()
+			tt.err.writeHeaders(rec)
+			if got := rec.Header().Get(c.HeaderRetryAfter); got != tt.wantHeader {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *AppError
+		want bool
+	}{
+		{"retryable", GetAppErr(context.Background(), errors.New("busy"), GetCustomErr("ERR_RTH_3", "busy", true), 503), true},
+		{"permanent", GetAppErr(context.Background(), errors.New("gone"), GetCustomErr("ERR_RTH_4", "gone", false), 410), false},
+		{"without custom error", &AppError{ActualErr: errors.New("bare")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.IsRetryable(); got != tt.want {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", "120", 2 * time.Minute, true},
+		{"padded", " 5 ", 5 * time.Second, true},
+		{"HTTP date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"past HTTP date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"negative", "-1", 0, false},
+		{"empty", "", 0, false},
+		{"malformed", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
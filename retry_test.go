@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	busy := GetCustomErr("ERR_RTY_1", "try again", true)
+	invalid := GetCustomErr("ERR_RTY_2", "invalid input", false)
+	noWait := WithBackoff(ConstantBackoff(0))
+
+	tests := []struct {
+		name      string
+		errs      []error // Errors returned by successive attempts, success once they run out
+		opts      []RetryOption
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success", nil, []RetryOption{noWait}, 1, false},
+		{"succeeds after a retry", []error{GetAppErr(context.Background(), errors.New("busy"), busy, 503)},
+			[]RetryOption{noWait}, 2, false},
+		{"not retryable", []error{GetAppErr(context.Background(), errors.New("bad"), invalid, 400)},
+			[]RetryOption{noWait}, 1, true},
+		{"plain error", []error{errors.New("boom")}, []RetryOption{noWait}, 1, true},
+		{"wrapped CustomErr", []error{fmt.Errorf("call: %w", busy), fmt.Errorf("call: %w", busy)},
+			[]RetryOption{noWait}, 3, false},
+		{"attempts used up", []error{busy, busy, busy}, []RetryOption{noWait, WithMaxAttempts(2)}, 2, true},
+		{"ignores invalid options", []error{busy, busy, busy, busy},
+			[]RetryOption{noWait, WithMaxAttempts(0), WithBackoff(nil), noWait}, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextWithTrace(context.Background())
+			calls := 0
+			err := Retry(ctx, func(context.Context) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			}, tt.opts...)
+
+			if calls != tt.wantCalls {
+				t.Errorf("fn called %d times, want %d", calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr || (err != nil && err != tt.errs[calls-1]) {
+				t.Errorf("Retry = %v, want the last error %v", err, tt.wantErr)
+			}
+
+			// Every failed attempt is traced
+			failed := calls
+			if !tt.wantErr {
+				failed--
+			}
+			traceMeta, _ := TraceFromContext(ctx)
+			if lines := traceMeta.ErrorLines(); len(lines) != failed {
+				t.Errorf("trace = %v, want %d lines", lines, failed)
+			}
+		})
+	}
+}
+
+func TestRetryWaits(t *testing.T) {
+	busy := GetCustomErr("ERR_RTY_3", "try again", true)
+
+	tests := []struct {
+		name     string
+		err      error
+		backoff  time.Duration
+		cancel   bool
+		minWait  time.Duration
+		wantErr  error
+		maxCalls int
+	}{
+		{"backoff", busy, 20 * time.Millisecond, false, 20 * time.Millisecond, busy, 2},
+		{"Retry-After extends the backoff",
+			GetAppErr(context.Background(), errors.New("busy"), busy, 503).SetRetryAfter(30 * time.Millisecond),
+			time.Millisecond, false, 30 * time.Millisecond, nil, 2},
+		{"cancelled", busy, time.Hour, true, 0, context.Canceled, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			calls := 0
+			start := time.Now()
+			err := Retry(ctx, func(context.Context) error {
+				calls++
+				if tt.cancel {
+					cancel()
+				}
+				return tt.err
+			}, WithBackoff(ConstantBackoff(tt.backoff)), WithMaxAttempts(2))
+
+			wantErr := tt.wantErr
+			if wantErr == nil {
+				wantErr = tt.err
+			}
+			if err != wantErr {
+				t.Errorf("Retry = %v, want %v", err, wantErr)
+			}
+			if calls != tt.maxCalls {
+				t.Errorf("fn called %d times, want %d", calls, tt.maxCalls)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minWait {
+				t.Errorf("Retry returned after %v, want at least %v", elapsed, tt.minWait)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{50, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.retry), func(t *testing.T) {
+			if got := backoff(tt.retry); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
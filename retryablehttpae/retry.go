@@ -0,0 +1,83 @@
+package retryablehttpae
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// maxPeekBytes bounds how much of a response body is read to find the retryable flag
+const maxPeekBytes = 64 << 10
+
+// CheckRetry is a retryablehttp.CheckRetry policy driven by the error catalog: transport errors
+// that are AppErrors retry when ae.IsRetryable, and JSON error responses written by WriteHTTP retry
+// when their "retryable" flag is set. Anything else falls back to retryablehttp.DefaultRetryPolicy
+func CheckRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return ae.IsRetryable(err), nil
+	}
+
+	if err == nil && resp != nil && resp.StatusCode >= http.StatusBadRequest {
+		if retryable, ok := peekRetryable(resp); ok {
+			return retryable, nil
+		}
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// Backoff is a retryablehttp.Backoff honoring the Retry-After header of any response, given as
+// delay seconds or HTTP date; without one it uses retryablehttp.DefaultBackoff
+func Backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := ae.ParseRetryAfter(resp.Header.Get(c.HeaderRetryAfter), time.Now()); ok {
+			return wait
+		}
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+}
+
+// peekRetryable reads the "retryable" flag of a JSON error body, restoring the body for the caller
+func peekRetryable(resp *http.Response) (bool, bool) {
+	if resp.Body == nil {
+		return false, false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get(c.HeaderContentType))
+	if err != nil || mediaType != c.ContentTypeJSON {
+		return false, false
+	}
+
+	peeked, err := io.ReadAll(io.LimitReader(resp.Body, maxPeekBytes))
+	resp.Body = &restoredBody{Reader: io.MultiReader(bytes.NewReader(peeked), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return false, false
+	}
+
+	var envelope struct {
+		Retryable *bool `json:"retryable"`
+	}
+	if json.Unmarshal(peeked, &envelope) != nil || envelope.Retryable == nil {
+		return false, false
+	}
+	return *envelope.Retryable, true
+}
+
+// restoredBody replays the peeked bytes before the rest of the original body
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
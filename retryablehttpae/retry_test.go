@@ -0,0 +1,94 @@
+package retryablehttpae
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ae "github.com/piyushkumar96/app-error"
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// response builds a response with the given status, content type and body
+func response(status int, contentType, body string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+	if contentType != "" {
+		resp.Header.Set(c.HeaderContentType, contentType)
+	}
+	return resp
+}
+
+func TestCheckRetry(t *testing.T) {
+	retryable := ae.GetAppErr(context.Background(), errors.New("down"), ae.GetCustomErr("ERR_RHT_1", "down", true), 503)
+	permanent := ae.GetAppErr(context.Background(), errors.New("bad"), ae.GetCustomErr("ERR_RHT_2", "bad", false), 400)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		resp    *http.Response
+		err     error
+		want    bool
+		wantErr error
+	}{
+		{"retryable transport error", context.Background(), nil, retryable, true, nil},
+		{"permanent transport error", context.Background(), nil, permanent, false, nil},
+		{"retryable client error body", context.Background(), response(409, "application/json", `{"code":"ERR_X","retryable":true}`), nil, true, nil},
+		{"permanent server error body", context.Background(), response(503, "application/json; charset=utf-8", `{"retryable":false}`), nil, false, nil},
+		{"body without flag", context.Background(), response(503, "application/json", `{"code":"ERR_X"}`), nil, true, nil},
+		{"non JSON server error", context.Background(), response(503, "text/plain", `{"retryable":false}`), nil, true, nil},
+		{"success", context.Background(), response(200, "application/json", `{"retryable":true}`), nil, false, nil},
+		{"cancelled", cancelled, response(503, "", ""), nil, false, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before string
+			if tt.resp != nil {
+				raw, _ := io.ReadAll(tt.resp.Body)
+				before = string(raw)
+				tt.resp.Body = io.NopCloser(strings.NewReader(before))
+			}
+
+			got, err := CheckRetry(tt.ctx, tt.resp, tt.err)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckRetry = %v, %v, want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+			if tt.resp != nil {
+				if after, _ := io.ReadAll(tt.resp.Body); string(after) != before {
+					t.Errorf("body after CheckRetry = %q, want %q", after, before)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	withRetryAfter := func(value string) *http.Response {
+		resp := response(503, "", "")
+		resp.Header.Set(c.HeaderRetryAfter, value)
+		return resp
+	}
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		want time.Duration
+	}{
+		{"no response", nil, time.Second},
+		{"no header", response(503, "", ""), time.Second},
+		{"delay seconds", withRetryAfter("7"), 7 * time.Second},
+		{"malformed header", withRetryAfter("soon"), time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Backoff(time.Second, time.Minute, 0, tt.resp); got != tt.want {
+				t.Errorf("Backoff = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
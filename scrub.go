@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// redacted replaces secrets found by the scrubber
+const redacted = "[REDACTED]"
+
+// SecretPattern describes a secret to scrub; Replacement may reference capture groups ($1)
+type SecretPattern struct {
+	Name        string
+	Regexp      *regexp.Regexp
+	Replacement string
+}
+
+// DefaultSecretPatterns returns the built-in patterns for obvious secrets
+func DefaultSecretPatterns() []SecretPattern {
+	return []SecretPattern{
+		{
+			Name:        "bearer_token",
+			Regexp:      regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`),
+			Replacement: "$1 " + redacted,
+		},
+		{
+			Name:        "jwt",
+			Regexp:      regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+			Replacement: redacted,
+		},
+		{
+			Name:        "aws_access_key_id",
+			Regexp:      regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+			Replacement: redacted,
+		},
+		{
+			Name:        "aws_secret_access_key",
+			Regexp:      regexp.MustCompile(`(?i)(aws_secret_access_key\s*[=:]\s*)[A-Za-z0-9/+=]{40}`),
+			Replacement: "${1}" + redacted,
+		},
+		{
+			Name:        "dsn_password",
+			Regexp:      regexp.MustCompile(`(\b[A-Za-z][A-Za-z0-9+.-]*://[^:/\s@]+:)[^@\s]+@`),
+			Replacement: "${1}" + redacted + "@",
+		},
+		{
+			Name:        "password_param",
+			Regexp:      regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|api_key|apikey)=[^\s&;]+`),
+			Replacement: "$1=" + redacted,
+		},
+	}
+}
+
+// secretPatterns holds the active patterns; writers are serialized by secretPatternsMu
+var (
+	secretPatterns   atomic.Pointer[[]SecretPattern]
+	secretPatternsMu sync.Mutex
+)
+
+func init() {
+	patterns := DefaultSecretPatterns()
+	secretPatterns.Store(&patterns)
+}
+
+// RegisterSecretPattern adds a pattern to the active scrubbing list
+func RegisterSecretPattern(pattern SecretPattern) {
+	secretPatternsMu.Lock()
+	defer secretPatternsMu.Unlock()
+
+	patterns := append(append([]SecretPattern{}, *secretPatterns.Load()...), pattern)
+	secretPatterns.Store(&patterns)
+}
+
+// SetSecretPatterns replaces the active scrubbing list
+func SetSecretPatterns(patterns []SecretPattern) {
+	secretPatternsMu.Lock()
+	defer secretPatternsMu.Unlock()
+
+	patterns = append([]SecretPattern{}, patterns...)
+	secretPatterns.Store(&patterns)
+}
+
+// GetSecretPatterns returns a copy of the active scrubbing list
+func GetSecretPatterns() []SecretPattern {
+	return append([]SecretPattern{}, *secretPatterns.Load()...)
+}
+
+// Scrub replaces every secret matched by the active patterns in s
+func Scrub(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, pattern := range *secretPatterns.Load() {
+		s = pattern.Regexp.ReplaceAllString(s, pattern.Replacement)
+	}
+	return s
+}
+
+// scrub applies Scrub when secret scrubbing is enabled
+func scrub(s string) string {
+	if !currentConfig().ScrubSecrets {
+		return s
+	}
+	return Scrub(s)
+}
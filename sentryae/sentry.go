@@ -0,0 +1,119 @@
+package sentryae
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/getsentry/sentry-go"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Capture sends appErr to Sentry through the hub of ctx (sentry.CurrentHub() when ctx has none)
+// and returns the ID of the event, nil when it was not sent
+func Capture(ctx context.Context, appErr *ae.AppError) *sentry.EventID {
+	if appErr == nil {
+		return nil
+	}
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return hub.CaptureEvent(Event(ctx, appErr))
+}
+
+// Hook returns an ae.Hook capturing every created AppError selected by filter, 5xx errors when
+// filter is nil; install it with ae.RegisterHook(sentryae.Hook(nil))
+func Hook(filter ae.ErrorFilter) ae.Hook {
+	if filter == nil {
+		filter = ae.MinStatus(500)
+	}
+	return func(ctx context.Context, appErr *ae.AppError) {
+		if filter(appErr) {
+			Capture(ctx, appErr)
+		}
+	}
+}
+
+// Reporter returns an ae.Reporter capturing the errors it is given, for use with the package's
+// reporting helpers and ae.WithGrouping
+func Reporter() ae.Reporter {
+	return ae.ReporterFunc(func(ctx context.Context, appErr *ae.AppError) error {
+		Capture(ctx, appErr)
+		return nil
+	})
+}
+
+// Event maps appErr to a Sentry event: the fingerprint is the group key of ctx (appErr.Fingerprint()
+// unless the reporter is wrapped with ae.WithGrouping), tags come from the code, category, labels
+// and trace identifiers, extra from the data, and the stack trace from the frames captured where
+// the error was created
+func Event(ctx context.Context, appErr *ae.AppError) *sentry.Event {
+	event := sentry.NewEvent()
+	event.Level = level(appErr.GetSeverity())
+	event.Message = ae.Scrub(appErr.Error())
+	event.Fingerprint = []string{ae.GroupKey(ctx, appErr, ae.GroupByFingerprint)}
+
+	code := appErr.GetErrCode()
+	event.Tags["code"] = code
+	event.Tags["http_code"] = strconv.Itoa(appErr.GetHTTPCode())
+	event.Tags["error_id"] = appErr.GetID()
+	if category := appErr.GetCategory(); category != "" {
+		event.Tags["category"] = string(category)
+	}
+	if traceID := appErr.GetTraceID(); traceID != "" {
+		event.Tags["trace_id"] = traceID
+	}
+	for k, v := range appErr.GetLabels() {
+		event.Tags[k] = v
+	}
+	for k, v := range appErr.GetIdentifiers() {
+		event.Tags[k] = fmt.Sprint(v)
+	}
+
+	for _, a := range ae.SlogAttrs(appErr) {
+		switch a.Key {
+		case "data", "error_codes", "message", "internal_message":
+			event.Extra[a.Key] = a.Value.Resolve().Any()
+		}
+	}
+
+	event.Exception = []sentry.Exception{{
+		Type:       code,
+		Value:      ae.Scrub(appErr.Error()),
+		Stacktrace: stacktrace(appErr.GetStackTrace()),
+	}}
+	return event
+}
+
+// stacktrace converts frames, innermost first, into a Sentry stack trace, which lists the
+// outermost frame first
+func stacktrace(frames []ae.Frame) *sentry.Stacktrace {
+	if len(frames) == 0 {
+		return nil
+	}
+	st := &sentry.Stacktrace{Frames: make([]sentry.Frame, 0, len(frames))}
+	for i := len(frames) - 1; i >= 0; i-- {
+		f := frames[i]
+		st.Frames = append(st.Frames, sentry.NewFrame(runtime.Frame{Function: f.Function, File: f.File, Line: f.Line}))
+	}
+	return st
+}
+
+// level maps a severity to the Sentry level, unset severities counting as errors
+func level(severity ae.Severity) sentry.Level {
+	switch severity {
+	case ae.SeverityDebug:
+		return sentry.LevelDebug
+	case ae.SeverityInfo:
+		return sentry.LevelInfo
+	case ae.SeverityWarn:
+		return sentry.LevelWarning
+	case ae.SeverityCritical:
+		return sentry.LevelFatal
+	default:
+		return sentry.LevelError
+	}
+}
@@ -0,0 +1,118 @@
+package sentryae
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// captureHub returns a context carrying a hub whose client hands captured events to events
+// instead of sending them
+func captureHub(t *testing.T, events *[]*sentry.Event) context.Context {
+	t.Helper()
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			*events = append(*events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return sentry.SetHubOnContext(context.Background(), sentry.NewHub(client, sentry.NewScope()))
+}
+
+func TestEvent(t *testing.T) {
+	ctx := ae.ContextWithTrace(context.Background())
+	ae.AddIdentifier(ctx, "user_id", 42)
+	appErr := ae.GetAppErr(ctx, errors.New("no rows"), ae.GetCustomErr("ERR_STY_1", "order not found", false,
+		ae.WithCategory(ae.CategoryNotFound), ae.WithSeverity(ae.SeverityWarn)), 0, map[string]string{"order": "o1"}).
+		SetLabel("team", "orders").SetTraceIDs("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	event := Event(context.Background(), appErr)
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"level", event.Level, sentry.LevelWarning},
+		{"fingerprint", event.Fingerprint[0], appErr.Fingerprint()},
+		{"code tag", event.Tags["code"], "ERR_STY_1"},
+		{"http code tag", event.Tags["http_code"], "404"},
+		{"error ID tag", event.Tags["error_id"], appErr.GetID()},
+		{"category tag", event.Tags["category"], "not_found"},
+		{"trace ID tag", event.Tags["trace_id"], "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"label tag", event.Tags["team"], "orders"},
+		{"identifier tag", event.Tags["user_id"], "42"},
+		{"message extra", event.Extra["message"], "order not found"},
+		{"exception type", event.Exception[0].Type, "ERR_STY_1"},
+		{"stack trace", event.Exception[0].Stacktrace != nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		severity ae.Severity
+		want     sentry.Level
+	}{
+		{0, sentry.LevelError},
+		{ae.SeverityDebug, sentry.LevelDebug},
+		{ae.SeverityInfo, sentry.LevelInfo},
+		{ae.SeverityWarn, sentry.LevelWarning},
+		{ae.SeverityError, sentry.LevelError},
+		{ae.SeverityCritical, sentry.LevelFatal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity.String(), func(t *testing.T) {
+			if got := level(tt.severity); got != tt.want {
+				t.Errorf("level(%v) = %v, want %v", tt.severity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHook(t *testing.T) {
+	defer ae.ClearHooks()
+
+	tests := []struct {
+		name       string
+		filter     ae.ErrorFilter
+		httpCode   int
+		wantEvents int
+	}{
+		{"5xx by default", nil, 503, 1},
+		{"4xx skipped by default", nil, 404, 0},
+		{"custom filter", ae.MinStatus(400), 404, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ae.ClearHooks()
+			ae.RegisterHook(Hook(tt.filter))
+
+			var events []*sentry.Event
+			ctx := captureHub(t, &events)
+			ae.GetAppErr(ctx, errors.New("boom"), ae.GetCustomErr("ERR_STY_2", "failed", false), tt.httpCode)
+			if len(events) != tt.wantEvents {
+				t.Errorf("captured %d events, want %d", len(events), tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestCaptureNil(t *testing.T) {
+	var events []*sentry.Event
+	if id := Capture(captureHub(t, &events), nil); id != nil || len(events) != 0 {
+		t.Errorf("Capture(nil) = %v with %d events, want nothing sent", id, len(events))
+	}
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownSerializer is returned when serializing with a name nobody registered
+	ErrUnknownSerializer = errors.New("unknown serializer")
+	// ErrBuiltinSerializer is returned when registering a serializer under a built-in name
+	ErrBuiltinSerializer = errors.New("built-in serializer cannot be replaced")
+)
+
+// Names of the built-in serializers
+const (
+	SerializerPublic    = "public"    // Client-facing JSON body, as written by WriteHTTP
+	SerializerInternal  = "internal"  // JSON body including data meant for internal consumers only
+	SerializerRecording = "recording" // Full Recording, e.g. for logs or dead-letter queues
+	SerializerWire      = "wire"      // Versioned wire schema decoded by Unmarshal, see Marshal
+)
+
+// SerializerFunc renders an AppError in the shape expected by one output target
+type SerializerFunc func(appErr *AppError) ([]byte, error)
+
+// serializers holds the registered serializers by name
+var serializers = struct {
+	sync.RWMutex
+	byName map[string]SerializerFunc
+}{
+	byName: map[string]SerializerFunc{
+		SerializerPublic: func(appErr *AppError) ([]byte, error) {
+			return json.Marshal(appErr.envelope(context.Background(), true))
+		},
+		SerializerInternal: func(appErr *AppError) ([]byte, error) {
+			return json.Marshal(appErr.envelope(context.Background(), false))
+		},
+		SerializerRecording: func(appErr *AppError) ([]byte, error) {
+			return json.Marshal(newRecording(appErr, nil))
+		},
+		SerializerWire: Marshal,
+	},
+}
+
+// RegisterSerializer registers (or replaces) the serializer used for the named output target,
+// e.g. "public-api", "partner-api" or "dlq". The built-in names are refused with
+// ErrBuiltinSerializer, since other outputs such as gRPC statuses rely on their shape
+func RegisterSerializer(name string, fn SerializerFunc) error {
+	switch name {
+	case SerializerPublic, SerializerInternal, SerializerRecording, SerializerWire:
+		return fmt.Errorf("%w: %q", ErrBuiltinSerializer, name)
+	}
+
+	serializers.Lock()
+	defer serializers.Unlock()
+	serializers.byName[name] = fn
+	return nil
+}
+
+// Serializers returns the names of the registered serializers, sorted
+func Serializers() []string {
+	serializers.RLock()
+	defer serializers.RUnlock()
+
+	names := make([]string, 0, len(serializers.byName))
+	for name := range serializers.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SerializeAs renders the AppError with the serializer registered under name
+func (e *AppError) SerializeAs(name string) ([]byte, error) {
+	serializers.RLock()
+	fn, ok := serializers.byName[name]
+	serializers.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSerializer, name)
+	}
+	return fn(e)
+}
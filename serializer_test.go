@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSerializeAs(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("db down"), GetCustomErr("ERR_SRZ_1", "failed", true), 503,
+		map[string]interface{}{"order": "o1"})
+	if err := RegisterSerializer("test-dlq", func(appErr *AppError) ([]byte, error) {
+		return []byte(`"` + appErr.CustomErr.Code + `"`), nil
+	}); err != nil {
+		t.Fatalf("RegisterSerializer: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		wantKeys []string
+		noKeys   []string
+		want     string
+		wantErr  error
+	}{
+		{SerializerPublic, []string{"code", "message", "retryable"}, []string{"err", "stack"}, "", nil},
+		{SerializerRecording, []string{"id", "code", "http_code", "err", "stack"}, nil, "", nil},
+		{"test-dlq", nil, nil, `"ERR_SRZ_1"`, nil},
+		{"unknown", nil, nil, "", ErrUnknownSerializer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appErr.SerializeAs(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SerializeAs = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != "" && string(got) != tt.want {
+				t.Errorf("SerializeAs = %s, want %s", got, tt.want)
+			}
+			if tt.wantKeys == nil && tt.noKeys == nil {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(got, &body); err != nil {
+				t.Fatalf("decoding %s: %v", got, err)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := body[key]; !ok {
+					t.Errorf("%s output %s lacks %q", tt.name, got, key)
+				}
+			}
+			for _, key := range tt.noKeys {
+				if _, ok := body[key]; ok {
+					t.Errorf("%s output %s has %q", tt.name, got, key)
+				}
+			}
+		})
+	}
+}
+
+func TestRegisterSerializerBuiltins(t *testing.T) {
+	for _, name := range []string{SerializerPublic, SerializerInternal, SerializerRecording, SerializerWire} {
+		if err := RegisterSerializer(name, nil); !errors.Is(err, ErrBuiltinSerializer) {
+			t.Errorf("RegisterSerializer(%q) = %v, want %v", name, err, ErrBuiltinSerializer)
+		}
+	}
+}
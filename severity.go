@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Severity ranks how serious an error is, e.g. to alert only on critical errors; the zero value
+// means unset and is treated as SeverityError
+type Severity int
+
+// Severity levels, from least to most serious
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityCritical
+)
+
+// severityNames holds the text form of each level
+var severityNames = map[Severity]string{
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarn:     "warn",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the lower-case name of the level, "error" when unset
+func (s Severity) String() string {
+	if name, ok := severityNames[s.orDefault()]; ok {
+		return name
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// Level returns the slog level errors of the severity are logged at; critical errors are logged
+// above slog.LevelError
+func (s Severity) Level() slog.Level {
+	switch s.orDefault() {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarn:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	}
+	return slog.LevelError
+}
+
+// MarshalText encodes the severity as its name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name such as "warn"
+func (s *Severity) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+	for level, levelName := range severityNames {
+		if levelName == name {
+			*s = level
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// orDefault returns the severity, SeverityError when unset
+func (s Severity) orDefault() Severity {
+	if s == 0 {
+		return SeverityError
+	}
+	return s
+}
+
+// CustomErrOption configures a CustomErr created by GetCustomErr
+type CustomErrOption func(*CustomErr)
+
+// WithSeverity sets the severity of the custom error
+func WithSeverity(severity Severity) CustomErrOption {
+	return func(ce *CustomErr) {
+		ce.Severity = severity
+	}
+}
+
+// GetSeverity retrieves the severity of the error, SeverityError when unset
+func (e *AppError) GetSeverity() Severity {
+	if e.CustomErr == nil {
+		return SeverityError
+	}
+	return e.CustomErr.Severity.orDefault()
+}
+
+// SetSeverity updates the severity of the error and returns the AppError
+func (e *AppError) SetSeverity(severity Severity) *AppError {
+	if e.CustomErr == nil {
+		e.CustomErr = &CustomErr{}
+	}
+	e.CustomErr.Severity = severity
+	return e
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		severity  Severity
+		wantName  string
+		wantLevel slog.Level
+	}{
+		{0, "error", slog.LevelError},
+		{SeverityDebug, "debug", slog.LevelDebug},
+		{SeverityInfo, "info", slog.LevelInfo},
+		{SeverityWarn, "warn", slog.LevelWarn},
+		{SeverityError, "error", slog.LevelError},
+		{SeverityCritical, "critical", slog.LevelError + 4},
+		{Severity(42), "severity(42)", slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantName, func(t *testing.T) {
+			if got := tt.severity.String(); got != tt.wantName {
+				t.Errorf("String = %q, want %q", got, tt.wantName)
+			}
+			if got := tt.severity.Level(); got != tt.wantLevel {
+				t.Errorf("Level = %v, want %v", got, tt.wantLevel)
+			}
+
+			text, err := tt.severity.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText: %v", err)
+			}
+			var decoded Severity
+			err = decoded.UnmarshalText(text)
+			if known := tt.severity <= SeverityCritical; (err == nil) != known {
+				t.Fatalf("UnmarshalText(%q) = %v, want success %v", text, err, known)
+			}
+			if err == nil && decoded.orDefault() != tt.severity.orDefault() {
+				t.Errorf("UnmarshalText(%q) = %v, want %v", text, decoded, tt.severity)
+			}
+		})
+	}
+}
+
+func TestAppErrorSeverity(t *testing.T) {
+	ctx := context.Background()
+	warn := GetCustomErr("ERR_SEV_1", "degraded", false, WithSeverity(SeverityWarn))
+
+	tests := []struct {
+		name      string
+		err       *AppError
+		want      Severity
+		wantLevel string
+	}{
+		{"default", GetAppErr(ctx, errors.New("boom"), GetCustomErr("ERR_SEV_2", "boom", false), 500), SeverityError, "ERROR"},
+		{"from the definition", GetAppErr(ctx, errors.New("slow"), warn, 500), SeverityWarn, "WARN"},
+		{"overridden", GetAppErr(ctx, errors.New("down"), warn, 500).SetSeverity(SeverityCritical), SeverityCritical, "ERROR+4"},
+		{"without custom error", (&AppError{ActualErr: errors.New("bare")}).SetSeverity(SeverityInfo), SeverityInfo, "INFO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.GetSeverity(); got != tt.want {
+				t.Errorf("GetSeverity = %v, want %v", got, tt.want)
+			}
+			if warn.Severity != SeverityWarn {
+				t.Fatalf("SetSeverity changed the shared definition to %v", warn.Severity)
+			}
+
+			var buf bytes.Buffer
+			SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+			defer SetLogger(nil)
+			logError(ctx, "request failed", tt.err)
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decoding log record %q: %v", buf.String(), err)
+			}
+			if record["level"] != tt.wantLevel || record["severity"] != tt.want.String() {
+				t.Errorf("logged level %v severity %v, want %s %s", record["level"], record["severity"], tt.wantLevel, tt.want)
+			}
+		})
+	}
+}
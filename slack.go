@@ -0,0 +1,117 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// SlackOption configures a SlackReporter
+type SlackOption func(*SlackReporter)
+
+// WithSlackFilter sets which AppErrors are posted, 5xx errors by default
+func WithSlackFilter(filter ErrorFilter) SlackOption {
+	return func(r *SlackReporter) {
+		r.filter = filter
+	}
+}
+
+// WithSlackRunbooks sets the runbook linked from messages, per code family
+func WithSlackRunbooks(runbooks CodeFamilies) SlackOption {
+	return func(r *SlackReporter) {
+		r.runbooks = runbooks
+	}
+}
+
+// WithSlackWebhooks routes code families to other incoming webhooks, e.g. per team channel
+func WithSlackWebhooks(webhooks CodeFamilies) SlackOption {
+	return func(r *SlackReporter) {
+		r.webhooks = webhooks
+	}
+}
+
+// WithSlackClient sets the HTTP client used to post messages
+func WithSlackClient(client *http.Client) SlackOption {
+	return func(r *SlackReporter) {
+		r.client = client
+	}
+}
+
+// SlackReporter is a Reporter posting a formatted block message with the code, message, error ID
+// and runbook link to a Slack incoming webhook
+type SlackReporter struct {
+	webhookURL string
+	webhooks   CodeFamilies
+	runbooks   CodeFamilies
+	filter     ErrorFilter
+	client     *http.Client
+}
+
+// NewSlackReporter creates a SlackReporter posting to the given incoming webhook URL
+func NewSlackReporter(webhookURL string, opts ...SlackOption) *SlackReporter {
+	r := &SlackReporter{
+		webhookURL: webhookURL,
+		filter:     MinStatus(http.StatusInternalServerError),
+		client:     defaultAlertClient,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Report posts the AppError when it matches the filter
+func (r *SlackReporter) Report(ctx context.Context, appErr *AppError) error {
+	if appErr == nil || !r.filter(appErr) {
+		return nil
+	}
+
+	url := r.webhookURL
+	if routed, ok := r.webhooks.Lookup(primaryCode(appErr)); ok {
+		url = routed
+	}
+	return postJSON(ctx, r.client, url, r.message(appErr))
+}
+
+// message builds the Slack block message for the AppError
+func (r *SlackReporter) message(appErr *AppError) map[string]interface{} {
+	code := primaryCode(appErr)
+	msg := scrub(appErr.GetMsg())
+
+	field := func(name, value string) map[string]interface{} {
+		return map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", name, value)}
+	}
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": ":rotating_light: " + code},
+		},
+		map[string]interface{}{
+			"type": "section",
+			"fields": []interface{}{
+				field("Code", "`"+code+"`"),
+				field("Message", msg),
+				field("Error ID", "`"+appErr.GetID()+"`"),
+				field("HTTP", strconv.Itoa(appErr.httpCode)),
+			},
+		},
+		map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": "```" + singleLine(scrub(appErr.Error())) + "```"},
+		},
+	}
+	if runbook, ok := r.runbooks.Lookup(code); ok {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "context",
+			"elements": []interface{}{
+				map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("<%s|Runbook>", runbook)},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"text":   fmt.Sprintf("%s: %s (%s)", code, msg, appErr.GetID()),
+		"blocks": blocks,
+	}
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler expands AppError attribute values into structured groups before delegating
+type slogHandler struct {
+	next slog.Handler
+}
+
+// NewSlogHandler wraps a slog.Handler so that attributes holding an *AppError, e.g.
+// slog.Error("x", "err", err), are logged as the group of attributes returned by SlogAttrs;
+// the trace identifiers found in the record's context are added alongside
+func NewSlogHandler(next slog.Handler) slog.Handler {
+	return &slogHandler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands AppError attributes and passes the record to the wrapped handler
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	var found *AppError
+	r.Attrs(func(a slog.Attr) bool {
+		a, appErr := expandAppErrorAttr(a)
+		if appErr != nil {
+			found = appErr
+		}
+		expanded.AddAttrs(a)
+		return true
+	})
+
+	if found != nil {
+		if identifiers, ok := identifierAttr(ctx, found); ok {
+			expanded.AddAttrs(identifiers)
+		}
+	}
+	return h.next.Handle(ctx, expanded)
+}
+
+// WithAttrs returns a handler whose pre-bound attributes have AppErrors expanded
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i], _ = expandAppErrorAttr(a)
+	}
+	return &slogHandler{next: h.next.WithAttrs(expanded)}
+}
+
+// WithGroup returns a handler that nests subsequent attributes under the group name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{next: h.next.WithGroup(name)}
+}
+
+// expandAppErrorAttr replaces an *AppError value with a group of its attributes, descending into
+// groups; it returns the last AppError found, nil when there was none
+func expandAppErrorAttr(a slog.Attr) (slog.Attr, *AppError) {
+	// AppErrors are LogValuers, so they are looked for before the value is resolved
+	if kind := a.Value.Kind(); kind == slog.KindAny || kind == slog.KindLogValuer {
+		if err, ok := a.Value.Any().(error); ok {
+			if appErr, ok := asAppError(err); ok {
+				return slog.Attr{Key: a.Key, Value: slog.GroupValue(SlogAttrs(appErr)...)}, appErr
+			}
+		}
+	}
+
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		expanded := make([]slog.Attr, len(group))
+		var found *AppError
+		for i, ga := range group {
+			var appErr *AppError
+			expanded[i], appErr = expandAppErrorAttr(ga)
+			if appErr != nil {
+				found = appErr
+			}
+		}
+		if found == nil {
+			return a, nil
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}, found
+	}
+	return a, nil
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("db down"), GetCustomErr("ERR_SLG_1", "failed", false), 500)
+	traced := ContextWithTrace(context.Background())
+	AddIdentifier(traced, "user_id", "u1")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		log     func(ctx context.Context, l *slog.Logger)
+		path    []string // Keys leading to the expanded group, nil when nothing is expanded
+		wantIDs bool
+	}{
+		{"direct", context.Background(), func(ctx context.Context, l *slog.Logger) {
+			l.ErrorContext(ctx, "x", "err", appErr)
+		}, []string{"err"}, false},
+		{"wrapped", context.Background(), func(ctx context.Context, l *slog.Logger) {
+			l.ErrorContext(ctx, "x", "err", fmt.Errorf("handling: %w", appErr))
+		}, []string{"err"}, false},
+		{"nested group", context.Background(), func(ctx context.Context, l *slog.Logger) {
+			l.ErrorContext(ctx, "x", slog.Group("req", slog.String("path", "/"), slog.Any("err", appErr)))
+		}, []string{"req", "err"}, false},
+		{"pre-bound", context.Background(), func(ctx context.Context, l *slog.Logger) {
+			l.With("err", appErr).ErrorContext(ctx, "x")
+		}, []string{"err"}, false},
+		{"plain error", context.Background(), func(ctx context.Context, l *slog.Logger) {
+			l.ErrorContext(ctx, "x", "err", errors.New("boom"))
+		}, nil, false},
+		{"identifiers from context", traced, func(ctx context.Context, l *slog.Logger) {
+			l.ErrorContext(ctx, "x", "err", appErr)
+		}, []string{"err"}, true},
+		{"no error no identifiers", traced, func(ctx context.Context, l *slog.Logger) {
+			l.ErrorContext(ctx, "x", "n", 1)
+		}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(tt.ctx, slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil))))
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decoding %q: %v", buf.String(), err)
+			}
+			if tt.path != nil {
+				group := record
+				for _, key := range tt.path {
+					next, ok := group[key].(map[string]interface{})
+					if !ok {
+						t.Fatalf("record %v has no group at %v", record, tt.path)
+					}
+					group = next
+				}
+				if group["code"] != "ERR_SLG_1" || group["error_id"] != appErr.GetID() {
+					t.Errorf("expanded group = %v", group)
+				}
+			}
+			if _, ok := record["identifiers"]; ok != tt.wantIDs {
+				t.Errorf("record %v has identifiers %v, want %v", record, ok, tt.wantIDs)
+			}
+		})
+	}
+}
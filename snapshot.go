@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// ErrNilSnapshot is returned by Snapshot when given no AppError
+var ErrNilSnapshot = errors.New("snapshot of a nil AppError")
+
+// SnapshotBundle gathers everything support needs to investigate an error in one document
+type SnapshotBundle struct {
+	GeneratedAt time.Time  `json:"generated_at"`
+	Hostname    string     `json:"hostname,omitempty"`
+	GoVersion   string     `json:"go_version"`
+	Module      string     `json:"module,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Error       *Recording `json:"error"`
+	Config      Config     `json:"config"`
+}
+
+// Snapshot returns an indented JSON bundle of the AppError with its trace, identifiers, stack,
+// labels and debug data, the active configuration and the build, ready to attach to a bug report.
+// Secrets are scrubbed and compliance mode still removes stack traces. A nil AppError returns
+// ErrNilSnapshot
+func Snapshot(ctx context.Context, appErr *AppError) ([]byte, error) {
+	if appErr == nil {
+		return nil, ErrNilSnapshot
+	}
+	traceMeta, _ := TraceFromContext(ctx)
+	bundle := SnapshotBundle{
+		GeneratedAt: time.Now().UTC(),
+		GoVersion:   runtime.Version(),
+		Error:       newRecording(appErr, traceMeta),
+		Config:      GetConfig(),
+	}
+	bundle.Hostname, _ = os.Hostname()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		bundle.Module = info.Main.Path
+		bundle.Version = info.Main.Version
+	}
+	return json.MarshalIndent(bundle, "", "  ")
+}
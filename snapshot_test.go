@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("dial: password=hunter2"),
+		GetCustomErr("ERR_SNAP_1", "unavailable", true), http.StatusServiceUnavailable)
+
+	tests := []struct {
+		name    string
+		appErr  *AppError
+		wantErr error
+		wantID  string
+	}{
+		{"nil error", nil, ErrNilSnapshot, ""},
+		{"app error", appErr, nil, appErr.GetID()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Snapshot(context.Background(), tt.appErr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Snapshot error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			var bundle SnapshotBundle
+			if err := json.Unmarshal(raw, &bundle); err != nil {
+				t.Fatalf("decoding bundle: %v", err)
+			}
+			if bundle.Error == nil || bundle.Error.ID != tt.wantID || bundle.GoVersion == "" {
+				t.Errorf("bundle = %+v, want error %s", bundle, tt.wantID)
+			}
+			if strings.Contains(string(raw), "hunter2") {
+				t.Errorf("snapshot leaks a secret: %s", raw)
+			}
+		})
+	}
+}
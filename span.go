@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+)
+
+// SpanRecorder records an AppError on the tracing span found in ctx, e.g. otelae.RecordSpanError
+type SpanRecorder func(ctx context.Context, appErr *AppError)
+
+// spanRecorder is invoked for every created AppError when set
+var spanRecorder atomic.Pointer[SpanRecorder]
+
+// SetSpanRecorder installs a recorder invoked for every AppError created by GetAppErr, New and
+// Wrap, so errors show up on the active span without explicit calls; nil disables it
+func SetSpanRecorder(recorder SpanRecorder) {
+	if recorder == nil {
+		spanRecorder.Store(nil)
+		return
+	}
+	spanRecorder.Store(&recorder)
+}
+
+// recordSpan passes a newly created AppError to the installed span recorder
+func recordSpan(ctx context.Context, appErr *AppError) {
+	if recorder := spanRecorder.Load(); recorder != nil {
+		(*recorder)(ctx, appErr)
+	}
+}
+
+// TraceContextExtractor returns the IDs of the trace and span active in ctx, e.g. from an
+// OpenTelemetry span; ok is false when ctx carries none
+type TraceContextExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+// traceContextExtractor overrides the trace context found by TraceContextFromContext when set
+var traceContextExtractor atomic.Pointer[TraceContextExtractor]
+
+// SetTraceContextExtractor installs an extractor consulted before the W3C trace context stored by
+// TraceMiddleware when AppErrors capture their trace and span IDs; nil removes it
+func SetTraceContextExtractor(extractor TraceContextExtractor) {
+	if extractor == nil {
+		traceContextExtractor.Store(nil)
+		return
+	}
+	traceContextExtractor.Store(&extractor)
+}
+
+// traceContextKey is the context key of the W3C trace context
+type traceContextKey struct{}
+
+// traceContext holds the IDs of a W3C trace context
+type traceContext struct {
+	traceID string
+	spanID  string
+}
+
+// ContextWithTraceContext returns a copy of ctx carrying the given trace and span IDs, which
+// AppErrors created with it capture
+func ContextWithTraceContext(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceContext{traceID: traceID, spanID: spanID})
+}
+
+// TraceContextFromContext returns the trace and span IDs active in ctx, asking the installed
+// TraceContextExtractor first and falling back to the IDs stored with ContextWithTraceContext
+func TraceContextFromContext(ctx context.Context) (traceID, spanID string, ok bool) {
+	if ctx == nil {
+		return "", "", false
+	}
+	if extractor := traceContextExtractor.Load(); extractor != nil {
+		if traceID, spanID, ok = (*extractor)(ctx); ok {
+			return traceID, spanID, true
+		}
+	}
+	tc, ok := ctx.Value(traceContextKey{}).(traceContext)
+	return tc.traceID, tc.spanID, ok
+}
+
+// parseTraceParent parses a W3C traceparent header, "00-<trace-id>-<parent-id>-<flags>"
+func parseTraceParent(header string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[3]) != 2 {
+		return "", "", false
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return "", "", false
+	}
+	traceID, spanID = parts[1], parts[2]
+	if len(traceID) != 32 || len(spanID) != 16 || !isLowerHex(traceID) || !isLowerHex(spanID) ||
+		strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+// isLowerHex reports whether s consists of lower-case hexadecimal digits only
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// GetTraceID retrieves the ID of the distributed trace the error was recorded in
+func (e *AppError) GetTraceID() string {
+	return e.traceID
+}
+
+// GetSpanID retrieves the ID of the span the error was recorded on
+func (e *AppError) GetSpanID() string {
+	return e.spanID
+}
+
+// SetTraceIDs attaches the IDs of the trace and span the error was recorded in, correlating it
+// with the distributed trace, and returns the AppError
+func (e *AppError) SetTraceIDs(traceID, spanID string) *AppError {
+	e.traceID, e.spanID = traceID, spanID
+	return e
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name   string
+		header string
+		wantOK bool
+	}{
+		{"valid", "00-" + traceID + "-" + spanID + "-01", true},
+		{"padded", "  00-" + traceID + "-" + spanID + "-00 ", true},
+		{"future version with extra fields", "01-" + traceID + "-" + spanID + "-01-extra", true},
+		{"version 00 with extra fields", "00-" + traceID + "-" + spanID + "-01-extra", false},
+		{"forbidden version", "ff-" + traceID + "-" + spanID + "-01", false},
+		{"upper-case hex", "00-" + "4BF92F3577B34DA6A3CE929D0E0E4736" + "-" + spanID + "-01", false},
+		{"zero trace ID", "00-00000000000000000000000000000000-" + spanID + "-01", false},
+		{"zero span ID", "00-" + traceID + "-0000000000000000-01", false},
+		{"short trace ID", "00-4bf92f35-" + spanID + "-01", false},
+		{"bad flags", "00-" + traceID + "-" + spanID + "-1", false},
+		{"too few fields", "00-" + traceID, false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTrace, gotSpan, ok := parseTraceParent(tt.header)
+			if ok != tt.wantOK || (ok && (gotTrace != traceID || gotSpan != spanID)) {
+				t.Errorf("parseTraceParent(%q) = %q, %q, %v, want ok %v", tt.header, gotTrace, gotSpan, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAppErrorCapturesTraceIDs(t *testing.T) {
+	defer SetTraceContextExtractor(nil)
+
+	stored := ContextWithTraceContext(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	extracted := func(ctx context.Context) (string, string, bool) {
+		return "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331", ctx.Value(ctxKey(98)) != nil
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		extractor   TraceContextExtractor
+		wantTraceID string
+		wantSpanID  string
+	}{
+		{"none", context.Background(), nil, "", ""},
+		{"stored", stored, nil, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"},
+		{"extractor wins", context.WithValue(stored, ctxKey(98), true), extracted, "0af7651916cd43dd8448eb211c80319c", "b7ad6b7169203331"},
+		{"extractor falls back", stored, extracted, "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTraceContextExtractor(tt.extractor)
+			appErr := GetAppErr(tt.ctx, errors.New("no rows"), GetCustomErr("ERR_TID_1", "order not found", false), 404)
+			if appErr.GetTraceID() != tt.wantTraceID || appErr.GetSpanID() != tt.wantSpanID {
+				t.Errorf("trace IDs = %q %q, want %q %q", appErr.GetTraceID(), appErr.GetSpanID(), tt.wantTraceID, tt.wantSpanID)
+			}
+			if b, _ := appErr.MarshalJSON(); tt.wantTraceID != "" && !strings.Contains(string(b), `"trace_id":"`+tt.wantTraceID+`"`) {
+				t.Errorf("JSON %s does not carry the trace ID", b)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/piyushkumar96/app-error/sqlmap"
+)
+
+// MySQL server error numbers with a dedicated mapping
+const (
+	ErDupEntry               = 1062
+	ErDupKey                 = 1022
+	ErDupUnique              = 1169
+	ErRowIsReferenced        = 1451
+	ErNoReferencedRow        = 1452
+	ErBadNull                = 1048
+	ErNoDefaultForField      = 1364
+	ErDataTooLong            = 1406
+	ErWarnDataOutOfRange     = 1264
+	ErTruncatedWrongValue    = 1366
+	ErCheckConstraintFailed  = 3819
+	ErLockDeadlock           = 1213
+	ErLockWaitTimeout        = 1205
+	ErQueryInterrupted       = 1317
+	ErQueryTimeout           = 3024
+	ErConCount               = 1040
+	ErTooManyUserConnections = 1203
+	ErServerShutdown         = 1053
+)
+
+// Dialect classifies the errors of go-sql-driver/mysql by their error number
+var Dialect sqlmap.Dialect = sqlmap.DialectFunc(Classify)
+
+// mapper is a sqlmap.Mapper with the MySQL dialect
+var mapper = sqlmap.New(sqlmap.WithDialect(Dialect))
+
+// FromMySQLError converts a go-sql-driver/mysql error into an AppError with the custom error of its
+// kind, e.g. 1062 duplicate entry into a 409 sqlmap.ErrConflict and 1213 deadlock or 1205 lock wait
+// timeout into a retryable 503 sqlmap.ErrTransient; nil stays nil and AppErrors are returned unchanged
+func FromMySQLError(ctx context.Context, err error) error {
+	return mapper.MapSkip(ctx, err, 1)
+}
+
+// Number returns the server error number of a MySQL error in the chain of err
+func Number(err error) (uint16, bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number, true
+	}
+	return 0, false
+}
+
+// Classify returns the kind of a MySQL error by its error number, treating invalid connections
+// as unavailability; ok is false for errors of other drivers
+func Classify(err error) (sqlmap.Kind, bool) {
+	number, ok := Number(err)
+	if !ok {
+		if errors.Is(err, mysql.ErrInvalidConn) {
+			return sqlmap.KindUnavailable, true
+		}
+		return sqlmap.KindUnknown, false
+	}
+
+	switch number {
+	case ErDupEntry, ErDupKey, ErDupUnique, ErRowIsReferenced, ErNoReferencedRow:
+		return sqlmap.KindConflict, true
+	case ErBadNull, ErNoDefaultForField, ErDataTooLong, ErWarnDataOutOfRange, ErTruncatedWrongValue,
+		ErCheckConstraintFailed:
+		return sqlmap.KindInvalidData, true
+	case ErLockDeadlock, ErLockWaitTimeout:
+		return sqlmap.KindTransient, true
+	case ErQueryInterrupted, ErQueryTimeout:
+		return sqlmap.KindTimeout, true
+	case ErConCount, ErTooManyUserConnections, ErServerShutdown:
+		return sqlmap.KindUnavailable, true
+	}
+	return sqlmap.KindUnknown, true
+}
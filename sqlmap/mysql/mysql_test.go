@@ -0,0 +1,93 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+
+	ae "github.com/piyushkumar96/app-error"
+	"github.com/piyushkumar96/app-error/sqlmap"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		number uint16
+		want   sqlmap.Kind
+	}{
+		{ErDupEntry, sqlmap.KindConflict},
+		{ErRowIsReferenced, sqlmap.KindConflict},
+		{ErNoReferencedRow, sqlmap.KindConflict},
+		{ErBadNull, sqlmap.KindInvalidData},
+		{ErDataTooLong, sqlmap.KindInvalidData},
+		{ErCheckConstraintFailed, sqlmap.KindInvalidData},
+		{ErLockDeadlock, sqlmap.KindTransient},
+		{ErLockWaitTimeout, sqlmap.KindTransient},
+		{ErQueryInterrupted, sqlmap.KindTimeout},
+		{ErQueryTimeout, sqlmap.KindTimeout},
+		{ErConCount, sqlmap.KindUnavailable},
+		{ErServerShutdown, sqlmap.KindUnavailable},
+		{1064, sqlmap.KindUnknown}, // Syntax error
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(int(tt.number)), func(t *testing.T) {
+			kind, ok := Classify(&mysql.MySQLError{Number: tt.number})
+			if kind != tt.want || !ok {
+				t.Errorf("Classify(%d) = %v, %v, want %v, true", tt.number, kind, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromMySQLError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{"duplicate entry", &mysql.MySQLError{Number: ErDupEntry}, "ERR_DB_CONFLICT", http.StatusConflict, false},
+		{"wrapped deadlock", fmt.Errorf("update stock: %w", &mysql.MySQLError{Number: ErLockDeadlock}), "ERR_DB_TRANSIENT", http.StatusServiceUnavailable, true},
+		{"lock wait timeout", &mysql.MySQLError{Number: ErLockWaitTimeout}, "ERR_DB_TRANSIENT", http.StatusServiceUnavailable, true},
+		{"invalid connection", mysql.ErrInvalidConn, "ERR_DB_UNAVAILABLE", http.StatusServiceUnavailable, true},
+		{"other driver", errors.New("boom"), ae.InternalError.Code, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromMySQLError(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("FromMySQLError = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromMySQLError = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   uint16
+		wantOK bool
+	}{
+		{"MySQL error", &mysql.MySQLError{Number: ErDupEntry}, ErDupEntry, true},
+		{"wrapped", fmt.Errorf("insert: %w", &mysql.MySQLError{Number: ErBadNull}), ErBadNull, true},
+		{"other error", errors.New("boom"), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := Number(tt.err); got != tt.want || ok != tt.wantOK {
+				t.Errorf("Number = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+
+	"github.com/piyushkumar96/app-error/sqlmap"
+)
+
+// SQLSTATE codes with a dedicated mapping; whole classes are matched by their first two characters
+const (
+	UniqueViolation      = "23505"
+	ForeignKeyViolation  = "23503"
+	NotNullViolation     = "23502"
+	CheckViolation       = "23514"
+	ExclusionViolation   = "23P01"
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+	LockNotAvailable     = "55P03"
+	QueryCanceled        = "57014"
+	AdminShutdown        = "57P01"
+	CrashShutdown        = "57P02"
+	CannotConnectNow     = "57P03"
+	TooManyConnections   = "53300"
+)
+
+// Dialect classifies the errors of pgx and lib/pq by their SQLSTATE
+var Dialect sqlmap.Dialect = sqlmap.DialectFunc(Classify)
+
+// mapper is a sqlmap.Mapper with the Postgres dialect
+var mapper = sqlmap.New(sqlmap.WithDialect(Dialect))
+
+// FromPgError converts a pgx or lib/pq error into an AppError with the custom error of its kind,
+// e.g. 23505 unique violation into a 409 sqlmap.ErrConflict and 40001 serialization failure into
+// a retryable 503 sqlmap.ErrTransient; nil stays nil and AppErrors are returned unchanged
+func FromPgError(ctx context.Context, err error) error {
+	return mapper.MapSkip(ctx, err, 1)
+}
+
+// SQLState returns the SQLSTATE of a pgx or lib/pq error in the chain of err
+func SQLState(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code), true
+	}
+	return "", false
+}
+
+// Classify returns the kind of a Postgres error by its SQLSTATE, treating pgx connection errors as
+// unavailability and pgx timeouts as timeouts; ok is false for errors of other drivers
+func Classify(err error) (sqlmap.Kind, bool) {
+	state, ok := SQLState(err)
+	if !ok {
+		var connectErr *pgconn.ConnectError
+		switch {
+		case pgconn.Timeout(err):
+			return sqlmap.KindTimeout, true
+		case errors.As(err, &connectErr):
+			return sqlmap.KindUnavailable, true
+		}
+		return sqlmap.KindUnknown, false
+	}
+	return classifyState(state), true
+}
+
+// classifyState maps a SQLSTATE to a kind
+func classifyState(state string) sqlmap.Kind {
+	switch state {
+	case UniqueViolation, ForeignKeyViolation, ExclusionViolation:
+		return sqlmap.KindConflict
+	case NotNullViolation, CheckViolation:
+		return sqlmap.KindInvalidData
+	case SerializationFailure, DeadlockDetected, LockNotAvailable:
+		return sqlmap.KindTransient
+	case QueryCanceled:
+		return sqlmap.KindTimeout
+	case AdminShutdown, CrashShutdown, CannotConnectNow, TooManyConnections:
+		return sqlmap.KindUnavailable
+	}
+
+	switch {
+	case strings.HasPrefix(state, "22"): // Data exception
+		return sqlmap.KindInvalidData
+	case strings.HasPrefix(state, "23"): // Integrity constraint violation
+		return sqlmap.KindConflict
+	case strings.HasPrefix(state, "40"): // Transaction rollback
+		return sqlmap.KindTransient
+	case strings.HasPrefix(state, "08"), strings.HasPrefix(state, "53"): // Connection exception, insufficient resources
+		return sqlmap.KindUnavailable
+	}
+	return sqlmap.KindUnknown
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+
+	ae "github.com/piyushkumar96/app-error"
+	"github.com/piyushkumar96/app-error/sqlmap"
+)
+
+func TestClassifyState(t *testing.T) {
+	tests := []struct {
+		state string
+		want  sqlmap.Kind
+	}{
+		{UniqueViolation, sqlmap.KindConflict},
+		{ForeignKeyViolation, sqlmap.KindConflict},
+		{ExclusionViolation, sqlmap.KindConflict},
+		{NotNullViolation, sqlmap.KindInvalidData},
+		{CheckViolation, sqlmap.KindInvalidData},
+		{SerializationFailure, sqlmap.KindTransient},
+		{DeadlockDetected, sqlmap.KindTransient},
+		{LockNotAvailable, sqlmap.KindTransient},
+		{QueryCanceled, sqlmap.KindTimeout},
+		{AdminShutdown, sqlmap.KindUnavailable},
+		{TooManyConnections, sqlmap.KindUnavailable},
+		{"22P02", sqlmap.KindInvalidData}, // Invalid text representation
+		{"23000", sqlmap.KindConflict},    // Integrity constraint violation
+		{"40002", sqlmap.KindTransient},   // Transaction integrity constraint violation
+		{"08006", sqlmap.KindUnavailable}, // Connection failure
+		{"53200", sqlmap.KindUnavailable}, // Out of memory
+		{"42601", sqlmap.KindUnknown},     // Syntax error
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			if got := classifyState(tt.state); got != tt.want {
+				t.Errorf("classifyState(%s) = %v, want %v", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromPgError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantState     string
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{"pgx unique violation", &pgconn.PgError{Code: UniqueViolation}, UniqueViolation, "ERR_DB_CONFLICT", http.StatusConflict, false},
+		{"lib/pq serialization failure", &pq.Error{Code: SerializationFailure}, SerializationFailure, "ERR_DB_TRANSIENT", http.StatusServiceUnavailable, true},
+		{"wrapped query canceled", fmt.Errorf("list orders: %w", &pgconn.PgError{Code: QueryCanceled}), QueryCanceled, "ERR_DB_TIMEOUT", http.StatusGatewayTimeout, true},
+		{"pgx connect error", &pgconn.ConnectError{}, "", "ERR_DB_UNAVAILABLE", http.StatusServiceUnavailable, true},
+		{"other driver", errors.New("boom"), "", ae.InternalError.Code, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if state, ok := SQLState(tt.err); state != tt.wantState || ok != (tt.wantState != "") {
+				t.Errorf("SQLState = %q, %v, want %q", state, ok, tt.wantState)
+			}
+
+			err := FromPgError(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("FromPgError = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("FromPgError = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+		})
+	}
+
+	if err := FromPgError(context.Background(), nil); err != nil {
+		t.Errorf("FromPgError(nil) = %v, want nil", err)
+	}
+}
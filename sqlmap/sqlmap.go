@@ -0,0 +1,179 @@
+package sqlmap
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Kind classifies a database failure
+type Kind int
+
+// Kinds of database failures
+const (
+	KindUnknown     Kind = iota // Unexpected failure, 500
+	KindNotFound                // No matching row, 404
+	KindConflict                // Unique or foreign key violation, 409
+	KindInvalidData             // Not-null, check or data type violation, 400
+	KindTransient               // Serialization failure, deadlock or lock timeout, retryable 503
+	KindTimeout                 // Statement or context deadline exceeded, retryable 504
+	KindCanceled                // Statement canceled because the client went away, 499
+	KindUnavailable             // Connection refused, lost or pool exhausted, retryable 503
+)
+
+// Custom errors the kinds map to by default
+var (
+	ErrNotFound = ae.GetCustomErr("ERR_DB_NOT_FOUND", "record not found", false,
+		ae.WithCategory(ae.CategoryNotFound))
+	ErrConflict = ae.GetCustomErr("ERR_DB_CONFLICT", "record conflicts with existing data", false,
+		ae.WithCategory(ae.CategoryConflict))
+	ErrInvalidData = ae.GetCustomErr("ERR_DB_INVALID_DATA", "record violates a data constraint", false,
+		ae.WithCategory(ae.CategoryValidation))
+	ErrTransient = ae.GetCustomErr("ERR_DB_TRANSIENT", "database transaction conflict, please retry", true,
+		ae.WithCategory(ae.CategoryUnavailable))
+	ErrTimeout = ae.GetCustomErr("ERR_DB_TIMEOUT", "database query timed out", true,
+		ae.WithCategory(ae.CategoryTimeout))
+	ErrCanceled = ae.GetCustomErr("ERR_DB_CANCELED", "database query canceled", false,
+		ae.WithDefaultHTTPCode(ae.StatusClientClosedRequest))
+	ErrUnavailable = ae.GetCustomErr("ERR_DB_UNAVAILABLE", "database is unavailable", true,
+		ae.WithCategory(ae.CategoryUnavailable))
+)
+
+// Dialect classifies the errors of a specific driver, e.g. by SQLSTATE or error number; ok is
+// false when the error is not one of the driver's
+type Dialect interface {
+	Classify(err error) (kind Kind, ok bool)
+}
+
+// DialectFunc adapts a function to the Dialect interface
+type DialectFunc func(err error) (Kind, bool)
+
+// Classify implements Dialect
+func (f DialectFunc) Classify(err error) (Kind, bool) {
+	return f(err)
+}
+
+// Mapper converts database errors into AppErrors
+type Mapper struct {
+	mu         sync.RWMutex
+	dialects   []Dialect
+	customErrs map[Kind]*ae.CustomErr
+}
+
+// Option configures a Mapper
+type Option func(*Mapper)
+
+// WithDialect adds a driver dialect, consulted in the order added
+func WithDialect(dialect Dialect) Option {
+	return func(m *Mapper) {
+		m.dialects = append(m.dialects, dialect)
+	}
+}
+
+// WithCustomErr maps a kind to a custom error of the service's own catalog
+func WithCustomErr(kind Kind, customErr *ae.CustomErr) Option {
+	return func(m *Mapper) {
+		m.customErrs[kind] = customErr
+	}
+}
+
+// New creates a Mapper using the package's custom errors unless overridden
+func New(opts ...Option) *Mapper {
+	m := &Mapper{
+		customErrs: map[Kind]*ae.CustomErr{
+			KindUnknown:     ae.InternalError,
+			KindNotFound:    ErrNotFound,
+			KindConflict:    ErrConflict,
+			KindInvalidData: ErrInvalidData,
+			KindTransient:   ErrTransient,
+			KindTimeout:     ErrTimeout,
+			KindCanceled:    ErrCanceled,
+			KindUnavailable: ErrUnavailable,
+		},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// RegisterDialect adds a driver dialect to the Mapper
+func (m *Mapper) RegisterDialect(dialect Dialect) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dialects = append(m.dialects, dialect)
+}
+
+// Classify returns the kind of a database error: sql.ErrNoRows is not found, context errors are
+// timeouts or cancellations, the registered dialects classify driver errors and broken or
+// refused connections are unavailability
+func (m *Mapper) Classify(err error) Kind {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return KindNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	}
+
+	m.mu.RLock()
+	dialects := m.dialects
+	m.mu.RUnlock()
+	for _, dialect := range dialects {
+		if kind, ok := dialect.Classify(err); ok {
+			return kind
+		}
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return KindTimeout
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone), errors.As(err, &netErr):
+		return KindUnavailable
+	}
+	return KindUnknown
+}
+
+// Map converts a database error into an AppError with the custom error of its kind, keeping err
+// as the underlying error; nil stays nil and AppErrors are returned unchanged
+func (m *Mapper) Map(ctx context.Context, err error) error {
+	return m.MapSkip(ctx, err, 1)
+}
+
+// MapSkip is Map skipping n additional frames when capturing the stack, so helpers wrapping the
+// Mapper can start it at their caller
+func (m *Mapper) MapSkip(ctx context.Context, err error, skip int) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *ae.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return ae.New(ctx, err, ae.WithCustomErr(m.customErrs[m.Classify(err)]), ae.WithStackSkip(skip+1))
+}
+
+// defaultMapper backs the package level functions
+var defaultMapper = New()
+
+// RegisterDialect adds a driver dialect to the default Mapper
+func RegisterDialect(dialect Dialect) {
+	defaultMapper.RegisterDialect(dialect)
+}
+
+// Classify returns the kind of a database error using the default Mapper
+func Classify(err error) Kind {
+	return defaultMapper.Classify(err)
+}
+
+// Map converts a database error into an AppError using the default Mapper
+func Map(ctx context.Context, err error) error {
+	return defaultMapper.MapSkip(ctx, err, 1)
+}
@@ -0,0 +1,117 @@
+package sqlmap
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// driverErr is an error of a fictional driver carrying a numeric code
+type driverErr struct{ code int }
+
+func (e *driverErr) Error() string { return fmt.Sprintf("driver error %d", e.code) }
+
+// testDialect classifies driverErr codes 1 and 2
+var testDialect = DialectFunc(func(err error) (Kind, bool) {
+	var de *driverErr
+	if !errors.As(err, &de) {
+		return KindUnknown, false
+	}
+	switch de.code {
+	case 1:
+		return KindConflict, true
+	case 2:
+		return KindTransient, true
+	}
+	return KindUnknown, true
+})
+
+// timeoutErr is a net.Error timing out
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+func TestMap(t *testing.T) {
+	mapper := New(WithDialect(testDialect))
+
+	tests := []struct {
+		name          string
+		err           error
+		wantKind      Kind
+		wantCode      string
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{"no rows", sql.ErrNoRows, KindNotFound, "ERR_DB_NOT_FOUND", http.StatusNotFound, false},
+		{"wrapped no rows", fmt.Errorf("get order: %w", sql.ErrNoRows), KindNotFound, "ERR_DB_NOT_FOUND", http.StatusNotFound, false},
+		{"deadline", context.DeadlineExceeded, KindTimeout, "ERR_DB_TIMEOUT", http.StatusGatewayTimeout, true},
+		{"canceled", context.Canceled, KindCanceled, "ERR_DB_CANCELED", ae.StatusClientClosedRequest, false},
+		{"dialect conflict", &driverErr{1}, KindConflict, "ERR_DB_CONFLICT", http.StatusConflict, false},
+		{"dialect transient", fmt.Errorf("tx: %w", &driverErr{2}), KindTransient, "ERR_DB_TRANSIENT", http.StatusServiceUnavailable, true},
+		{"dialect unknown", &driverErr{3}, KindUnknown, ae.InternalError.Code, http.StatusInternalServerError, false},
+		{"net timeout", &net.OpError{Op: "read", Err: timeoutErr{}}, KindTimeout, "ERR_DB_TIMEOUT", http.StatusGatewayTimeout, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, KindUnavailable, "ERR_DB_UNAVAILABLE", http.StatusServiceUnavailable, true},
+		{"bad connection", driver.ErrBadConn, KindUnavailable, "ERR_DB_UNAVAILABLE", http.StatusServiceUnavailable, true},
+		{"connection done", sql.ErrConnDone, KindUnavailable, "ERR_DB_UNAVAILABLE", http.StatusServiceUnavailable, true},
+		{"unknown", errors.New("syntax error"), KindUnknown, ae.InternalError.Code, http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapper.Classify(tt.err); got != tt.wantKind {
+				t.Errorf("Classify = %v, want %v", got, tt.wantKind)
+			}
+
+			err := mapper.Map(context.Background(), tt.err)
+			var appErr *ae.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("Map = %T %v, want an AppError", err, err)
+			}
+			if appErr.GetErrCode() != tt.wantCode || appErr.GetHTTPCode() != tt.wantStatus || appErr.IsRetryable() != tt.wantRetryable {
+				t.Errorf("Map = %s %d retryable=%v, want %s %d retryable=%v",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable(), tt.wantCode, tt.wantStatus, tt.wantRetryable)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Map lost the underlying error %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestMapPassThrough(t *testing.T) {
+	existing := ae.GetAppErr(context.Background(), errors.New("no rows"), ErrNotFound, 0)
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"AppError", existing, existing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Map(context.Background(), tt.err); got != tt.want {
+				t.Errorf("Map = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCustomErr(t *testing.T) {
+	missing := ae.GetCustomErr("ERR_ORDERS_404", "order not found", false, ae.WithDefaultHTTPCode(http.StatusNotFound))
+	mapper := New(WithCustomErr(KindNotFound, missing))
+
+	var appErr *ae.AppError
+	if err := mapper.Map(context.Background(), sql.ErrNoRows); !errors.As(err, &appErr) || appErr.GetErrCode() != "ERR_ORDERS_404" {
+		t.Errorf("Map = %v, want ERR_ORDERS_404", err)
+	}
+}
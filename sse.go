@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// FormatSSE renders the AppError as a Server-Sent Events "error" event carrying the JSON body
+// as data and, when a retry delay is set, the reconnection delay as the retry field
+func (e *AppError) FormatSSE() []byte {
+	data, err := json.Marshal(e.envelope(context.Background(), true))
+	if err != nil {
+		data = []byte(`{}`)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("event: " + c.SSEErrorEvent + "\n")
+	if e.retryAfter > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(e.retryAfter.Milliseconds(), 10) + "\n")
+	}
+	buf.WriteString("data: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	return buf.Bytes()
+}
+
+// WriteSSE writes the AppError as a Server-Sent Events "error" event mid-stream, flushing when
+// the writer supports it. Response writers are flushed through http.ResponseController, so writers
+// wrapped by middleware that expose Unwrap are flushed too
+func (e *AppError) WriteSSE(w io.Writer) error {
+	if _, err := w.Write(e.FormatSSE()); err != nil {
+		return err
+	}
+	switch fw := w.(type) {
+	case http.ResponseWriter:
+		if err := http.NewResponseController(fw).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	case http.Flusher:
+		fw.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatSSE(t *testing.T) {
+	customErr := GetCustomErr("ERR_SSE_1", "stream failed", true)
+
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+		wantRetry  string
+	}{
+		{"without retry", 0, ""},
+		{"with retry", 1500 * time.Millisecond, "retry: 1500\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := GetAppErr(context.Background(), errors.New("upstream closed"), customErr, 503)
+			if tt.retryAfter > 0 {
+				appErr.SetRetryAfter(tt.retryAfter)
+			}
+			frame := string(appErr.FormatSSE())
+
+			if !strings.HasPrefix(frame, "event: error\n"+tt.wantRetry+"data: ") {
+				t.Errorf("FormatSSE = %q, want event, %q and data fields in order", frame, tt.wantRetry)
+			}
+			if !strings.HasSuffix(frame, "\n\n") {
+				t.Errorf("FormatSSE = %q, want a blank line terminating the event", frame)
+			}
+			data := strings.TrimSuffix(frame[strings.Index(frame, "data: ")+len("data: "):], "\n\n")
+			if strings.Contains(data, "\n") || !json.Valid([]byte(data)) || !strings.Contains(data, "ERR_SSE_1") {
+				t.Errorf("data = %q, want single line JSON carrying the error code", data)
+			}
+		})
+	}
+}
+
+func TestWriteSSEFlushes(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("boom"), GetCustomErr("ERR_SSE_2", "failed", false), 500)
+	rec := httptest.NewRecorder()
+
+	if err := appErr.WriteSSE(rec); err != nil {
+		t.Fatalf("WriteSSE: %v", err)
+	}
+	if !rec.Flushed {
+		t.Error("WriteSSE did not flush the response writer")
+	}
+	if rec.Body.String() != string(appErr.FormatSSE()) {
+		t.Errorf("body = %q, want the formatted event", rec.Body.String())
+	}
+}
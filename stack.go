@@ -0,0 +1,94 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// defaultStackDepth is the default maximum number of frames captured for an AppError
+const defaultStackDepth = 32
+
+// Frame is a single resolved stack frame
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String formats the frame as "function (file:line)"
+func (f Frame) String() string {
+	return fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+// callers captures up to Config.StackDepth program counters of the calling goroutine into buf,
+// only allocating when the depth exceeds its capacity; skip 0 starts at the function calling
+// callers, skip 1 at its caller and so on
+func callers(buf []uintptr, skip int) []uintptr {
+	depth := currentConfig().StackDepth
+	if depth <= 0 {
+		return nil
+	}
+	if depth > cap(buf) {
+		buf = make([]uintptr, depth)
+	}
+	pcs := buf[:depth]
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n:n]
+}
+
+// caller captures the program counter of a single frame, using the same skip semantics as callers
+func caller(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// resolveFrames resolves program counters into frames
+func resolveFrames(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := make([]Frame, 0, len(pcs))
+	iter := runtime.CallersFrames(pcs)
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// GetStackTrace retrieves the frames captured where the innermost AppError of the chain was
+// created, innermost first
+func (e *AppError) GetStackTrace() []Frame {
+	if len(e.stack) == 0 && len(e.replayStack) > 0 {
+		return append([]Frame(nil), e.replayStack...)
+	}
+	return resolveFrames(e.stack)
+}
+
+// GetWrapSites retrieves the frames where this error chain was wrapped into new AppErrors, oldest
+// first; the stack itself is captured only once, where the innermost AppError was created
+func (e *AppError) GetWrapSites() []Frame {
+	sites := resolveFrames(e.wrapSites)
+	if len(e.replayWrapSites) > 0 {
+		sites = append(append([]Frame(nil), e.replayWrapSites...), sites...)
+	}
+	return sites
+}
+
+// topFrame returns the frame where the AppError was created
+func (e *AppError) topFrame() (Frame, bool) {
+	if len(e.stack) == 0 {
+		if len(e.replayStack) > 0 {
+			return e.replayStack[0], true
+		}
+		return Frame{}, false
+	}
+	return resolveFrames(e.stack[:1])[0], true
+}
@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stackOrigin creates the innermost AppError of the chains built by the stack tests
+func stackOrigin() *AppError {
+	return GetAppErr(context.Background(), errors.New("connection refused"), GetCustomErr("ERR_STK_1", "db down", true), http.StatusServiceUnavailable)
+}
+
+// functionName returns the last element of a qualified function name
+func functionName(frame Frame) string {
+	return frame.Function[strings.LastIndex(frame.Function, ".")+1:]
+}
+
+func TestStackCapturedOncePerChain(t *testing.T) {
+	origin := stackOrigin()
+	outer := GetCustomErr("ERR_STK_2", "order unavailable", true)
+
+	tests := []struct {
+		name      string
+		build     func() *AppError
+		wantSites int
+	}{
+		{"origin", func() *AppError { return origin }, 0},
+		{"GetAppErr around an AppError", func() *AppError {
+			return GetAppErr(context.Background(), origin, outer, 0)
+		}, 1},
+		{"Wrap", func() *AppError { return Wrap(context.Background(), origin, outer, 0) }, 1},
+		{"Wrap twice", func() *AppError {
+			return Wrap(context.Background(), Wrap(context.Background(), origin, outer, 0), nil, 0)
+		}, 2},
+		{"wrapped by fmt.Errorf", func() *AppError {
+			return GetAppErr(context.Background(), fmt.Errorf("loading: %w", origin), outer, 0)
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := tt.build()
+			stack := appErr.GetStackTrace()
+			if len(stack) == 0 || functionName(stack[0]) != "stackOrigin" {
+				t.Fatalf("stack starts at %v, want stackOrigin", stack)
+			}
+			if !reflect.DeepEqual(stack, origin.GetStackTrace()) {
+				t.Error("stack was captured again instead of shared")
+			}
+
+			sites := appErr.GetWrapSites()
+			if len(sites) != tt.wantSites {
+				t.Fatalf("%d wrap sites, want %d: %v", len(sites), tt.wantSites, sites)
+			}
+			for _, site := range sites {
+				if !strings.Contains(site.Function, "TestStackCapturedOncePerChain") {
+					t.Errorf("wrap site %s, want the test function", site)
+				}
+			}
+			if verbose := fmt.Sprintf("%+v", appErr); strings.Contains(verbose, "wrapped at:") != (tt.wantSites > 0) {
+				t.Errorf("%%+v report lists wrap sites = %v, want %v", !(tt.wantSites > 0), tt.wantSites > 0)
+			}
+		})
+	}
+}
+
+func TestStackDepth(t *testing.T) {
+	tests := []struct {
+		name      string
+		depth     int
+		wantMax   int
+		wantFirst string
+	}{
+		{"disabled", 0, 0, ""},
+		{"single frame", 1, 1, "stackOrigin"},
+		{"bounded", 2, 2, "stackOrigin"},
+		{"default", defaultStackDepth, defaultStackDepth, "stackOrigin"},
+		{"beyond the default buffer", 2 * defaultStackDepth, 2 * defaultStackDepth, "stackOrigin"},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.StackDepth = tt.depth
+			SetConfig(cfg)
+
+			frames := stackOrigin().GetStackTrace()
+			if len(frames) > tt.wantMax || (tt.wantMax > 0 && len(frames) == 0) {
+				t.Fatalf("captured %d frames, want 1 to %d", len(frames), tt.wantMax)
+			}
+			if tt.wantFirst != "" && functionName(frames[0]) != tt.wantFirst {
+				t.Errorf("stack starts at %s, want %s", frames[0].Function, tt.wantFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsSampleSize is the number of recent occurrences kept per code to compute intervals
+const statsSampleSize = 64
+
+// defaultMaxStatsKeys is the default bound on the distinct codes and tenants tracked
+const defaultMaxStatsKeys = 1000
+
+// StatsOtherKey is the code, or tenant, under which occurrences beyond Config.MaxStatsKeys are counted
+const StatsOtherKey = "other"
+
+// CodeStats holds the collected statistics of a single error code
+type CodeStats struct {
+	Code           string        `json:"code"`
+	Count          uint64        `json:"count"`
+	FirstSeen      time.Time     `json:"first_seen"`
+	LastSeen       time.Time     `json:"last_seen"`
+	MedianInterval time.Duration `json:"median_interval"` // p50 of the time between recent occurrences
+}
+
+// StatsSnapshot is a point-in-time copy of the collected error statistics
+type StatsSnapshot struct {
+	Since      time.Time                    `json:"since"`
+	Total      uint64                       `json:"total"`
+	ByCode     map[string]CodeStats         `json:"by_code"`
+	ByStatus   map[int]uint64               `json:"by_status"`
+	ByCategory map[Category]uint64          `json:"by_category"` // Uncategorized errors are counted under ""
+	ByTenant   map[string]map[string]uint64 `json:"by_tenant"`   // Counts per tenant, then per code
+}
+
+// codeCounter accumulates the statistics of a single code
+type codeCounter struct {
+	count     uint64
+	firstSeen time.Time
+	lastSeen  time.Time
+	recent    [statsSampleSize]time.Time // Ring of the most recent occurrences
+	next      int
+}
+
+// statsCollector accumulates statistics for created AppErrors
+type statsCollector struct {
+	mu         sync.Mutex
+	since      time.Time
+	total      uint64
+	byCode     map[string]*codeCounter
+	byStatus   map[int]uint64
+	byCategory map[Category]uint64
+	byTenant   map[string]map[string]uint64
+}
+
+// stats is the package level collector fed by GetAppErr
+var stats = newStatsCollector()
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		since:      time.Now(),
+		byCode:     map[string]*codeCounter{},
+		byStatus:   map[int]uint64{},
+		byCategory: map[Category]uint64{},
+		byTenant:   map[string]map[string]uint64{},
+	}
+}
+
+// boundedKey returns key, or StatsOtherKey when key is new and the map already tracks maxKeys keys
+func boundedKey[V any](m map[string]V, key string, maxKeys int) string {
+	if _, ok := m[key]; ok || maxKeys <= 0 || len(m) < maxKeys {
+		return key
+	}
+	return StatsOtherKey
+}
+
+// record counts one occurrence of the AppError by code, HTTP status, category and tenant
+func (s *statsCollector) record(appErr *AppError) {
+	now := time.Now()
+	code, tenantID, maxKeys := appErr.CustomErr.Code, appErr.GetTenant(), currentConfig().MaxStatsKeys
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.total++
+	s.byStatus[appErr.httpCode]++
+	s.byCategory[appErr.GetCategory()]++
+	if code == "" {
+		return
+	}
+	code = boundedKey(s.byCode, code, maxKeys)
+	if tenantID != "" {
+		tenantID = boundedKey(s.byTenant, tenantID, maxKeys)
+		if s.byTenant[tenantID] == nil {
+			s.byTenant[tenantID] = map[string]uint64{}
+		}
+		s.byTenant[tenantID][boundedKey(s.byTenant[tenantID], code, maxKeys)]++
+	}
+
+	counter, ok := s.byCode[code]
+	if !ok {
+		counter = &codeCounter{firstSeen: now}
+		s.byCode[code] = counter
+	}
+	counter.count++
+	counter.lastSeen = now
+	counter.recent[counter.next] = now
+	counter.next = (counter.next + 1) % statsSampleSize
+}
+
+// snapshot copies the collected statistics
+func (s *statsCollector) snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := StatsSnapshot{
+		Since:      s.since,
+		Total:      s.total,
+		ByCode:     make(map[string]CodeStats, len(s.byCode)),
+		ByStatus:   make(map[int]uint64, len(s.byStatus)),
+		ByCategory: make(map[Category]uint64, len(s.byCategory)),
+		ByTenant:   make(map[string]map[string]uint64, len(s.byTenant)),
+	}
+	for code, counter := range s.byCode {
+		snap.ByCode[code] = CodeStats{
+			Code:           code,
+			Count:          counter.count,
+			FirstSeen:      counter.firstSeen,
+			LastSeen:       counter.lastSeen,
+			MedianInterval: counter.medianInterval(),
+		}
+	}
+	for status, count := range s.byStatus {
+		snap.ByStatus[status] = count
+	}
+	for category, count := range s.byCategory {
+		snap.ByCategory[category] = count
+	}
+	for tenantID, codes := range s.byTenant {
+		snap.ByTenant[tenantID] = make(map[string]uint64, len(codes))
+		for code, count := range codes {
+			snap.ByTenant[tenantID][code] = count
+		}
+	}
+	return snap
+}
+
+// reset discards everything collected so far
+func (s *statsCollector) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.since = time.Now()
+	s.total = 0
+	s.byCode = map[string]*codeCounter{}
+	s.byStatus = map[int]uint64{}
+	s.byCategory = map[Category]uint64{}
+	s.byTenant = map[string]map[string]uint64{}
+}
+
+// medianInterval returns the median gap between the recorded recent occurrences
+func (c *codeCounter) medianInterval() time.Duration {
+	times := make([]time.Time, 0, statsSampleSize)
+	for _, t := range c.recent {
+		if !t.IsZero() {
+			times = append(times, t)
+		}
+	}
+	if len(times) < 2 {
+		return 0
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	gaps := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		gaps = append(gaps, times[i].Sub(times[i-1]))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// Stats returns a snapshot of the error statistics collected since start or the last reset
+func Stats() StatsSnapshot {
+	return stats.snapshot()
+}
+
+// ResetStats discards the collected error statistics
+func ResetStats() {
+	stats.reset()
+}
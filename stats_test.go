@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatsRecord(t *testing.T) {
+	newErr := func(tenantID, code string, status int, opts ...CustomErrOption) *AppError {
+		ctx := context.Background()
+		if tenantID != "" {
+			ctx = WithTenant(ctx, tenantID)
+		}
+		return GetAppErr(ctx, errors.New("failed"), GetCustomErr(code, "failed", false, opts...), status)
+	}
+
+	tests := []struct {
+		name       string
+		maxKeys    int
+		errs       []*AppError
+		wantCodes  map[string]uint64
+		wantStatus map[int]uint64
+		wantCat    map[Category]uint64
+		wantTenant map[string]map[string]uint64
+	}{
+		{
+			name:       "by code, status and category",
+			errs:       []*AppError{newErr("", "ERR_ST_1", 404, WithCategory(CategoryNotFound)), newErr("", "ERR_ST_1", 404, WithCategory(CategoryNotFound)), newErr("", "ERR_ST_2", 500)},
+			wantCodes:  map[string]uint64{"ERR_ST_1": 2, "ERR_ST_2": 1},
+			wantStatus: map[int]uint64{404: 2, 500: 1},
+			wantCat:    map[Category]uint64{CategoryNotFound: 2, "": 1},
+			wantTenant: map[string]map[string]uint64{},
+		},
+		{
+			name:       "by tenant",
+			errs:       []*AppError{newErr("acme", "ERR_ST_1", 409), newErr("globex", "ERR_ST_1", 409)},
+			wantCodes:  map[string]uint64{"ERR_ST_1": 2},
+			wantStatus: map[int]uint64{409: 2},
+			wantCat:    map[Category]uint64{"": 2},
+			wantTenant: map[string]map[string]uint64{"acme": {"ERR_ST_1": 1}, "globex": {"ERR_ST_1": 1}},
+		},
+		{
+			name:       "bounded keys",
+			maxKeys:    1,
+			errs:       []*AppError{newErr("acme", "ERR_ST_1", 400), newErr("acme", "ERR_ST_2", 400), newErr("globex", "ERR_ST_3", 400)},
+			wantCodes:  map[string]uint64{"ERR_ST_1": 1, StatsOtherKey: 2},
+			wantStatus: map[int]uint64{400: 3},
+			wantCat:    map[Category]uint64{"": 3},
+			wantTenant: map[string]map[string]uint64{"acme": {"ERR_ST_1": 1, StatsOtherKey: 1}, StatsOtherKey: {StatsOtherKey: 1}},
+		},
+	}
+	defer SetConfig(DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if tt.maxKeys > 0 {
+				cfg.MaxStatsKeys = tt.maxKeys
+			}
+			SetConfig(cfg)
+
+			collector := newStatsCollector()
+			for _, appErr := range tt.errs {
+				collector.record(appErr)
+			}
+			snap := collector.snapshot()
+
+			codes := map[string]uint64{}
+			for code, codeStats := range snap.ByCode {
+				codes[code] = codeStats.Count
+			}
+			if snap.Total != uint64(len(tt.errs)) {
+				t.Errorf("Total = %d, want %d", snap.Total, len(tt.errs))
+			}
+			checks := []struct {
+				name      string
+				got, want interface{}
+			}{
+				{"by code", codes, tt.wantCodes},
+				{"by status", snap.ByStatus, tt.wantStatus},
+				{"by category", snap.ByCategory, tt.wantCat},
+				{"by tenant", snap.ByTenant, tt.wantTenant},
+			}
+			for _, check := range checks {
+				if !reflect.DeepEqual(check.got, check.want) {
+					t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsDisabledByDefault(t *testing.T) {
+	ResetStats()
+	GetAppErr(context.Background(), errors.New("failed"), GetCustomErr("ERR_ST_9", "failed", false), http.StatusBadRequest)
+	if total := Stats().Total; total != 0 {
+		t.Errorf("Total = %d with StatsEnabled off, want 0", total)
+	}
+
+	cfg := DefaultConfig()
+	cfg.StatsEnabled = true
+	SetConfig(cfg)
+	defer SetConfig(DefaultConfig())
+	GetAppErr(context.Background(), errors.New("failed"), GetCustomErr("ERR_ST_9", "failed", false), http.StatusBadRequest)
+	if total := Stats().ByCode["ERR_ST_9"].Count; total != 1 {
+		t.Errorf("ERR_ST_9 count = %d with StatsEnabled on, want 1", total)
+	}
+	ResetStats()
+}
+
+func TestMedianInterval(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name string
+		gaps []time.Duration
+		want time.Duration
+	}{
+		{"no occurrence", nil, 0},
+		{"single occurrence", []time.Duration{0}, 0},
+		{"even gaps", []time.Duration{0, time.Second, time.Second}, time.Second},
+		{"outlier", []time.Duration{0, time.Second, time.Second, time.Hour}, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var counter codeCounter
+			at := start
+			for i, gap := range tt.gaps {
+				at = at.Add(gap)
+				counter.recent[i] = at
+			}
+			if got := counter.medianInterval(); got != tt.want {
+				t.Errorf("medianInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package statsdae
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Emitter sends DogStatsD metrics for created AppErrors
+type Emitter struct {
+	client       statsd.ClientInterface
+	prefix       string   // Prefix of the metric names
+	tags         []string // Tags added to every metric, including the service
+	rate         float64  // Sample rate of the metrics
+	distribution bool     // Emit the retryable vs terminal distribution
+	tenant       bool     // Tag the metrics with the tenant
+}
+
+// Option configures an Emitter
+type Option func(*Emitter)
+
+// WithPrefix sets the prefix of the metric names, "app_errors." by default
+func WithPrefix(prefix string) Option {
+	return func(e *Emitter) {
+		e.prefix = prefix
+	}
+}
+
+// WithTags adds tags, e.g. "env:prod", to every metric
+func WithTags(tags ...string) Option {
+	return func(e *Emitter) {
+		e.tags = append(e.tags, tags...)
+	}
+}
+
+// WithSampleRate sets the sample rate of the metrics, 1 by default
+func WithSampleRate(rate float64) Option {
+	return func(e *Emitter) {
+		e.rate = rate
+	}
+}
+
+// WithRetryableDistribution also emits a "retryable" distribution with the value 1 for retryable
+// and 0 for terminal errors, so dashboards can chart the share of retryable failures
+func WithRetryableDistribution() Option {
+	return func(e *Emitter) {
+		e.distribution = true
+	}
+}
+
+// WithTenantTag tags the metrics with "tenant:<id>" for errors carrying a tenant set with
+// ae.WithTenant. Every tenant adds its own tag value, so only enable it with a bounded set of tenants
+func WithTenantTag() Option {
+	return func(e *Emitter) {
+		e.tenant = true
+	}
+}
+
+// New creates an Emitter sending metrics through client, tagged with the service name
+func New(client statsd.ClientInterface, service string, opts ...Option) *Emitter {
+	e := &Emitter{
+		client: client,
+		prefix: "app_errors.",
+		rate:   1,
+	}
+	if service != "" {
+		e.tags = append(e.tags, "service:"+service)
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Install creates an Emitter and registers it as an ae.Hook, so every created AppError is counted;
+// call it once at init
+func Install(client statsd.ClientInterface, service string, opts ...Option) *Emitter {
+	e := New(client, service, opts...)
+	ae.RegisterHook(e.Emit)
+	return e
+}
+
+// Emit counts an AppError as "<prefix>count" tagged by code, category, HTTP code, retryability and,
+// when enabled, tenant, and adds it to the retryable distribution when enabled; it is an ae.Hook
+func (e *Emitter) Emit(_ context.Context, appErr *ae.AppError) {
+	retryable := appErr.IsRetryable()
+	tags := append(append(make([]string, 0, len(e.tags)+4), e.tags...),
+		"code:"+appErr.GetErrCode(),
+		"http_code:"+strconv.Itoa(appErr.GetHTTPCode()),
+		"retryable:"+strconv.FormatBool(retryable),
+	)
+	if category := appErr.GetCategory(); category != "" {
+		tags = append(tags, "category:"+string(category))
+	}
+	if tenantID := appErr.GetTenant(); e.tenant && tenantID != "" {
+		tags = append(tags, "tenant:"+tenantID)
+	}
+
+	_ = e.client.Incr(e.prefix+"count", tags, e.rate)
+	if e.distribution {
+		value := 0.0
+		if retryable {
+			value = 1
+		}
+		_ = e.client.Distribution(e.prefix+"retryable", value, tags, e.rate)
+	}
+}
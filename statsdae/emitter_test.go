@@ -0,0 +1,97 @@
+package statsdae
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// metric is a metric sent to recordingClient
+type metric struct {
+	name  string
+	value float64
+	tags  []string
+	rate  float64
+}
+
+// recordingClient records the counters and distributions it is sent
+type recordingClient struct {
+	statsd.NoOpClient
+	metrics []metric
+}
+
+func (c *recordingClient) Incr(name string, tags []string, rate float64) error {
+	c.metrics = append(c.metrics, metric{name, 1, tags, rate})
+	return nil
+}
+
+func (c *recordingClient) Distribution(name string, value float64, tags []string, rate float64) error {
+	c.metrics = append(c.metrics, metric{name, value, tags, rate})
+	return nil
+}
+
+func TestEmit(t *testing.T) {
+	busy := ae.GetCustomErr("ERR_SDE_1", "try again", true, ae.WithCategory(ae.CategoryUnavailable))
+	invalid := ae.GetCustomErr("ERR_SDE_2", "invalid", false)
+	tenantCtx := ae.WithTenant(context.Background(), "acme")
+
+	tests := []struct {
+		name    string
+		service string
+		opts    []Option
+		ctx     context.Context
+		err     *ae.AppError
+		want    []metric
+	}{
+		{"defaults", "orders", nil, context.Background(), nil, []metric{
+			{"app_errors.count", 1, []string{"service:orders", "code:ERR_SDE_1", "http_code:503", "retryable:true", "category:unavailable"}, 1},
+		}},
+		{"without service", "", nil, context.Background(),
+			ae.GetAppErr(context.Background(), errors.New("bad"), invalid, 400), []metric{
+				{"app_errors.count", 1, []string{"code:ERR_SDE_2", "http_code:400", "retryable:false"}, 1},
+			}},
+		{"prefix, tags and rate", "orders", []Option{WithPrefix("svc.errors."), WithTags("env:prod"), WithSampleRate(0.5)}, context.Background(), nil, []metric{
+			{"svc.errors.count", 1, []string{"service:orders", "env:prod", "code:ERR_SDE_1", "http_code:503", "retryable:true", "category:unavailable"}, 0.5},
+		}},
+		{"retryable distribution", "orders", []Option{WithRetryableDistribution()}, context.Background(),
+			ae.GetAppErr(context.Background(), errors.New("bad"), invalid, 400), []metric{
+				{"app_errors.count", 1, []string{"service:orders", "code:ERR_SDE_2", "http_code:400", "retryable:false"}, 1},
+				{"app_errors.retryable", 0, []string{"service:orders", "code:ERR_SDE_2", "http_code:400", "retryable:false"}, 1},
+			}},
+		{"tenant tag", "orders", []Option{WithTenantTag()}, tenantCtx, nil, []metric{
+			{"app_errors.count", 1, []string{"service:orders", "code:ERR_SDE_1", "http_code:503", "retryable:true", "category:unavailable", "tenant:acme"}, 1},
+		}},
+		{"tenant without the option", "orders", nil, tenantCtx, nil, []metric{
+			{"app_errors.count", 1, []string{"service:orders", "code:ERR_SDE_1", "http_code:503", "retryable:true", "category:unavailable"}, 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := tt.err
+			if appErr == nil {
+				appErr = ae.GetAppErr(tt.ctx, errors.New("busy"), busy, 503)
+			}
+			client := &recordingClient{}
+			New(client, tt.service, tt.opts...).Emit(tt.ctx, appErr)
+			if !reflect.DeepEqual(client.metrics, tt.want) {
+				t.Errorf("metrics = %+v, want %+v", client.metrics, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstall(t *testing.T) {
+	defer ae.ClearHooks()
+	client := &recordingClient{}
+	Install(client, "orders")
+
+	ae.GetAppErr(context.Background(), errors.New("busy"), ae.GetCustomErr("ERR_SDE_3", "busy", true), 503)
+	if len(client.metrics) != 1 || client.metrics[0].name != "app_errors.count" {
+		t.Errorf("metrics = %+v, want one count", client.metrics)
+	}
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CustomErrForStatus returns a generic custom error for an HTTP status, for errors that only carry
+// a status (framework errors, downstream responses): the code is derived from the status text,
+// e.g. "ERR_NOT_FOUND", the message is the lower-case status text, the category follows the status,
+// and 408, 429, 502, 503 and 504 are retryable. The codes are the package's own and exempt from Config.CodePolicy
+func CustomErrForStatus(status int) *CustomErr {
+	text := http.StatusText(status)
+	if text == "" {
+		return &CustomErr{
+			Code:     "ERR_HTTP_" + strconv.Itoa(status),
+			Message:  "http status " + strconv.Itoa(status),
+			Category: categoryForStatus(status),
+		}
+	}
+
+	code := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, text)
+	return &CustomErr{
+		Code:      "ERR_" + code,
+		Message:   strings.ToLower(text),
+		Retryable: isRetryableStatus(status),
+		Category:  categoryForStatus(status),
+	}
+}
+
+// isRetryableStatus reports whether a request failing with the status may succeed when retried
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,306 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStoreQueueFull is returned by AsyncStore.Save when the queue cannot take another event
+var ErrStoreQueueFull = errors.New("error store queue is full")
+
+// ErrStoreClosed is returned by AsyncStore.Save after the store has been closed
+var ErrStoreClosed = errors.New("error store is closed")
+
+// Store persists error events, e.g. to retain them for compliance; traceMeta may be nil
+type Store interface {
+	Save(ctx context.Context, appErr *AppError, traceMeta *TraceMeta) error
+}
+
+// StoreFunc adapts a function to the Store interface
+type StoreFunc func(ctx context.Context, appErr *AppError, traceMeta *TraceMeta) error
+
+// Save calls f(ctx, appErr, traceMeta)
+func (f StoreFunc) Save(ctx context.Context, appErr *AppError, traceMeta *TraceMeta) error {
+	return f(ctx, appErr, traceMeta)
+}
+
+// BatchStore persists several error events at once; AsyncStore flushes into it
+type BatchStore interface {
+	SaveBatch(ctx context.Context, records []*Recording) error
+}
+
+// SQLOption configures a SQLStore
+type SQLOption func(*SQLStore)
+
+// WithSQLPlaceholder sets how the n-th (1-based) bind parameter is written, "?" by default
+func WithSQLPlaceholder(placeholder func(n int) string) SQLOption {
+	return func(s *SQLStore) {
+		s.placeholder = placeholder
+	}
+}
+
+// DollarPlaceholder writes bind parameters as $1, $2, ... as expected by PostgreSQL drivers
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// SQLStore is an example Store writing one row per error event through database/sql; the full
+// recording is kept as JSON next to the columns most useful for querying
+type SQLStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+// sqlStoreColumns are the columns written by SQLStore, in bind order
+var sqlStoreColumns = []string{"id", "recorded_at", "code", "http_code", "message", "retryable", "record"}
+
+// NewSQLStore creates a SQLStore inserting into table; see Schema for the expected layout
+func NewSQLStore(db *sql.DB, table string, opts ...SQLOption) *SQLStore {
+	s := &SQLStore{
+		db:          db,
+		table:       table,
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Schema returns a portable CREATE TABLE statement for the store's table
+func (s *SQLStore) Schema() string {
+	return "CREATE TABLE IF NOT EXISTS " + s.table + ` (
+	id VARCHAR(64) PRIMARY KEY,
+	recorded_at TIMESTAMP NOT NULL,
+	code VARCHAR(128) NOT NULL,
+	http_code INTEGER NOT NULL,
+	message TEXT NOT NULL,
+	retryable BOOLEAN NOT NULL,
+	record TEXT NOT NULL
+)`
+}
+
+// Save inserts a single error event
+func (s *SQLStore) Save(ctx context.Context, appErr *AppError, traceMeta *TraceMeta) error {
+	return s.SaveBatch(ctx, []*Recording{newRecording(appErr, traceMeta)})
+}
+
+// SaveBatch inserts every record within a single transaction
+func (s *SQLStore) SaveBatch(ctx context.Context, records []*Recording) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, s.insertStatement())
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range records {
+		record, err := json.Marshal(r)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, r.ID, r.RecordedAt.UTC(), r.Code, r.HTTPCode, r.Message, r.Retryable, string(record)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("saving error %s: %w", r.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// insertStatement builds the INSERT statement for a single row
+func (s *SQLStore) insertStatement() string {
+	placeholders := make([]string, len(sqlStoreColumns))
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(i + 1)
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		s.table, strings.Join(sqlStoreColumns, ", "), strings.Join(placeholders, ", "))
+}
+
+// AsyncOption configures an AsyncStore
+type AsyncOption func(*AsyncStore)
+
+// WithBatchSize sets how many events are flushed together, 100 by default
+func WithBatchSize(n int) AsyncOption {
+	return func(s *AsyncStore) {
+		if n > 0 {
+			s.batchSize = n
+		}
+	}
+}
+
+// WithFlushInterval sets how long queued events may wait before being flushed, 1s by default
+func WithFlushInterval(d time.Duration) AsyncOption {
+	return func(s *AsyncStore) {
+		if d > 0 {
+			s.flushInterval = d
+		}
+	}
+}
+
+// WithQueueSize sets how many events may be queued before Save starts dropping them, 1000 by default
+func WithQueueSize(n int) AsyncOption {
+	return func(s *AsyncStore) {
+		if n > 0 {
+			s.queueSize = n
+		}
+	}
+}
+
+// AsyncStore is a Store that snapshots events synchronously and persists them in batches from a
+// background goroutine, so saving never blocks the request path
+type AsyncStore struct {
+	target        BatchStore
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+
+	queue   chan *Recording
+	done    chan struct{}
+	closeMu sync.RWMutex
+	closed  bool
+	dropped atomic.Int64
+}
+
+// NewAsyncStore creates an AsyncStore flushing into target and starts its background goroutine;
+// call Close to flush the remaining events on shutdown
+func NewAsyncStore(target BatchStore, opts ...AsyncOption) *AsyncStore {
+	s := &AsyncStore{
+		target:        target,
+		batchSize:     100,
+		flushInterval: time.Second,
+		queueSize:     1000,
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.queue = make(chan *Recording, s.queueSize)
+
+	go s.run()
+	return s
+}
+
+// Save queues the event, returning ErrStoreQueueFull when the queue is full
+func (s *AsyncStore) Save(_ context.Context, appErr *AppError, traceMeta *TraceMeta) error {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+	select {
+	case s.queue <- newRecording(appErr, traceMeta):
+		return nil
+	default:
+		s.dropped.Add(1)
+		return ErrStoreQueueFull
+	}
+}
+
+// Dropped returns how many events were dropped because the queue was full
+func (s *AsyncStore) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close stops accepting events and waits until the queued ones are flushed or ctx is done
+func (s *AsyncStore) Close(ctx context.Context) error {
+	s.closeMu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.closeMu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run batches queued events until the queue is closed
+func (s *AsyncStore) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Recording, 0, s.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.target.SaveBatch(context.Background(), batch); err != nil {
+			getLogger().Error("persisting error events failed", "count", len(batch), "error", err)
+		}
+		batch = make([]*Recording, 0, s.batchSize)
+	}
+
+	for {
+		select {
+		case r, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// store is the optional package level store, nil when persistence is disabled
+var store struct {
+	sync.RWMutex
+	s Store
+}
+
+// SetStore installs the package level store used by PersistError and WriteHTTP; nil disables it
+func SetStore(s Store) {
+	store.Lock()
+	store.s = s
+	store.Unlock()
+}
+
+// GetStore returns the package level store, nil when persistence is disabled
+func GetStore() Store {
+	store.RLock()
+	defer store.RUnlock()
+	return store.s
+}
+
+// PersistError saves err with the package level store together with the trace stored in ctx;
+// it is a no-op when err is not an AppError or no store is installed
+func PersistError(ctx context.Context, err error) error {
+	s := GetStore()
+	appErr, ok := asAppError(err)
+	if s == nil || !ok {
+		return nil
+	}
+	traceMeta, _ := traceSnapshot(ctx)
+	return s.Save(ctx, appErr, traceMeta)
+}
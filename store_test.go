@@ -0,0 +1,150 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchCollector is a BatchStore recording the size of every batch it receives
+type batchCollector struct {
+	mu      sync.Mutex
+	sizes   []int
+	release chan struct{} // When set, SaveBatch waits for it before returning
+}
+
+func (b *batchCollector) SaveBatch(_ context.Context, records []*Recording) error {
+	if b.release != nil {
+		<-b.release
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sizes = append(b.sizes, len(records))
+	return nil
+}
+
+func TestAsyncStoreBatches(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("boom"), GetCustomErr("ERR_STR_1", "failed", false), 500)
+
+	tests := []struct {
+		name      string
+		batchSize int
+		events    int
+		want      []int
+	}{
+		{"no events", 3, 0, nil},
+		{"partial batch flushed on close", 3, 2, []int{2}},
+		{"full batches", 3, 6, []int{3, 3}},
+		{"full and partial batches", 3, 7, []int{3, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &batchCollector{}
+			s := NewAsyncStore(target, WithBatchSize(tt.batchSize), WithFlushInterval(time.Hour))
+			for i := 0; i < tt.events; i++ {
+				if err := s.Save(context.Background(), appErr, nil); err != nil {
+					t.Fatalf("Save: %v", err)
+				}
+			}
+			if err := s.Close(context.Background()); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if !reflect.DeepEqual(target.sizes, tt.want) {
+				t.Errorf("batches = %v, want %v", target.sizes, tt.want)
+			}
+			if err := s.Save(context.Background(), appErr, nil); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("Save after Close = %v, want %v", err, ErrStoreClosed)
+			}
+		})
+	}
+}
+
+func TestAsyncStoreQueueFull(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("boom"), GetCustomErr("ERR_STR_2", "failed", false), 500)
+	target := &batchCollector{release: make(chan struct{})}
+	s := NewAsyncStore(target, WithBatchSize(1), WithQueueSize(1), WithFlushInterval(time.Hour))
+
+	// The first event blocks the background goroutine in SaveBatch, the second fills the queue
+	_ = s.Save(context.Background(), appErr, nil)
+	deadline := time.Now().Add(time.Second)
+	for len(s.queue) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := s.Save(context.Background(), appErr, nil); err != nil {
+		t.Fatalf("Save into the empty queue = %v", err)
+	}
+	if err := s.Save(context.Background(), appErr, nil); !errors.Is(err, ErrStoreQueueFull) {
+		t.Errorf("Save into the full queue = %v, want %v", err, ErrStoreQueueFull)
+	}
+	if s.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", s.Dropped())
+	}
+
+	close(target.release)
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestSQLStoreInsertStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []SQLOption
+		want string
+	}{
+		{"question marks", nil,
+			"INSERT INTO app_errors (id, recorded_at, code, http_code, message, retryable, record) VALUES (?, ?, ?, ?, ?, ?, ?)"},
+		{"dollar placeholders", []SQLOption{WithSQLPlaceholder(DollarPlaceholder)},
+			"INSERT INTO app_errors (id, recorded_at, code, http_code, message, retryable, record) VALUES ($1, $2, $3, $4, $5, $6, $7)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewSQLStore(nil, "app_errors", tt.opts...).insertStatement(); got != tt.want {
+				t.Errorf("insertStatement = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPersistError(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("boom"), GetCustomErr("ERR_STR_3", "failed", false), 500)
+	ctx := ContextWithTrace(context.Background())
+	AddTraceLog(ctx, "step failed")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		err       error
+		wantSaved bool
+		wantTrace bool
+	}{
+		{"plain error", ctx, errors.New("boom"), false, false},
+		{"untraced", context.Background(), appErr, true, false},
+		{"traced", ctx, appErr, true, true},
+	}
+	defer SetStore(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved bool
+			var traceMeta *TraceMeta
+			SetStore(StoreFunc(func(_ context.Context, _ *AppError, tm *TraceMeta) error {
+				saved, traceMeta = true, tm
+				return nil
+			}))
+			if err := PersistError(tt.ctx, tt.err); err != nil {
+				t.Fatalf("PersistError: %v", err)
+			}
+			if saved != tt.wantSaved || (traceMeta != nil) != tt.wantTrace {
+				t.Errorf("saved %v with trace %v, want %v, %v", saved, traceMeta != nil, tt.wantSaved, tt.wantTrace)
+			}
+		})
+	}
+
+	SetStore(nil)
+	if err := PersistError(ctx, appErr); err != nil {
+		t.Errorf("PersistError without a store = %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Syslog defaults following RFC 5424
+const (
+	syslogFacilityUser   = 1     // user-level messages
+	syslogEnterpriseID   = 32473 // IANA example enterprise number, replace with your own
+	syslogSeverityErr    = 3
+	syslogSeverityWarn   = 4
+	syslogNilValue       = "-"
+	syslogMaxHeaderField = 48
+	syslogMaxName        = 32
+)
+
+// SyslogFormatter renders AppErrors as RFC 5424 messages with structured data elements for the
+// code, HTTP status, retry hints and trace identifiers
+type SyslogFormatter struct {
+	Hostname     string // HOSTNAME field, "-" when empty
+	AppName      string // APP-NAME field, "-" when empty
+	ProcID       string // PROCID field, "-" when empty
+	Facility     int    // Syslog facility, user-level (1) by default
+	EnterpriseID int    // Private enterprise number used in SD-IDs, 32473 by default
+}
+
+// NewSyslogFormatter creates a formatter for the given application using the local hostname and
+// process ID
+func NewSyslogFormatter(appName string) *SyslogFormatter {
+	hostname, _ := os.Hostname()
+	return &SyslogFormatter{
+		Hostname:     hostname,
+		AppName:      appName,
+		ProcID:       strconv.Itoa(os.Getpid()),
+		Facility:     syslogFacilityUser,
+		EnterpriseID: syslogEnterpriseID,
+	}
+}
+
+// Format renders the AppError as a single RFC 5424 message; MSGID is the primary code and the
+// identifiers come from the TraceMeta stored in ctx
+func (f *SyslogFormatter) Format(ctx context.Context, appErr *AppError) string {
+	severity := syslogSeverityErr
+	if appErr.httpCode >= 400 && appErr.httpCode < http.StatusInternalServerError {
+		severity = syslogSeverityWarn
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s ",
+		f.Facility*8+severity,
+		time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(f.Hostname, 255),
+		syslogHeaderField(f.AppName, syslogMaxHeaderField),
+		syslogHeaderField(f.ProcID, 128),
+		syslogHeaderField(primaryCode(appErr), syslogMaxName))
+
+	f.writeElement(&b, "error",
+		"code", primaryCode(appErr),
+		"codes", strings.Join(appErr.ErrorCodes, ","),
+		"id", appErr.GetID())
+	f.writeElement(&b, "http", "status", strconv.Itoa(appErr.httpCode))
+
+	retryable := appErr.CustomErr != nil && appErr.CustomErr.Retryable
+	if appErr.retryAfter > 0 {
+		f.writeElement(&b, "retry", "retryable", strconv.FormatBool(retryable), "after", formatRetryAfter(appErr.retryAfter))
+	} else {
+		f.writeElement(&b, "retry", "retryable", strconv.FormatBool(retryable))
+	}
+
+	if traceMeta, ok := traceSnapshot(ctx); ok && len(traceMeta.IdentifierMappings) > 0 {
+		keys := make([]string, 0, len(traceMeta.IdentifierMappings))
+		for key := range traceMeta.IdentifierMappings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		params := make([]string, 0, 2*len(keys))
+		for _, key := range keys {
+			params = append(params, key, fmt.Sprint(traceMeta.IdentifierMappings[key]))
+		}
+		f.writeElement(&b, "ids", params...)
+	}
+
+	msg := appErr.GetMsg()
+	if cause := appErr.Error(); cause != "" {
+		msg += ": " + cause
+	}
+	b.WriteString(" " + singleLine(scrub(msg)))
+	return b.String()
+}
+
+// Write writes the rendered message followed by a newline
+func (f *SyslogFormatter) Write(w io.Writer, ctx context.Context, appErr *AppError) error {
+	_, err := io.WriteString(w, f.Format(ctx, appErr)+"\n")
+	return err
+}
+
+// writeElement writes an SD-ELEMENT "[name@enterprise key="value" ...]"
+func (f *SyslogFormatter) writeElement(b *strings.Builder, name string, params ...string) {
+	enterpriseID := f.EnterpriseID
+	if enterpriseID == 0 {
+		enterpriseID = syslogEnterpriseID
+	}
+
+	fmt.Fprintf(b, "[%s@%d", name, enterpriseID)
+	for i := 0; i+1 < len(params); i += 2 {
+		fmt.Fprintf(b, ` %s="%s"`, syslogName(params[i]), syslogEscape(params[i+1]))
+	}
+	b.WriteString("]")
+}
+
+// syslogHeaderField returns a header field limited to printable US-ASCII, or "-" when empty
+func syslogHeaderField(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	if s == "" {
+		return syslogNilValue
+	}
+	return s
+}
+
+// syslogName returns a valid SD-NAME: printable US-ASCII without '=', ' ', ']' and '"'
+func syslogName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > syslogMaxName {
+		s = s[:syslogMaxName]
+	}
+	return s
+}
+
+// syslogEscape escapes '"', '\' and ']' in a PARAM-VALUE
+func syslogEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(scrub(s))
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogFormat(t *testing.T) {
+	f := &SyslogFormatter{Hostname: "host 1", AppName: "orders", ProcID: "42", Facility: syslogFacilityUser}
+	traced := ContextWithTrace(context.Background())
+	AddIdentifier(traced, "user_id", 7)
+	AddIdentifier(traced, "order]id", `a"b`)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  *AppError
+		want string // Message after the timestamp, with {id} standing for the error ID
+	}{
+		{"server error", context.Background(),
+			GetAppErr(context.Background(), errors.New("db down"), GetCustomErr("ERR_SYS_1", "failed", false), 500),
+			`host1 orders 42 ERR_SYS_1 [error@32473 code="ERR_SYS_1" codes="ERR_SYS_1" id="{id}"][http@32473 status="500"]` +
+				`[retry@32473 retryable="false"] failed: db down`},
+		{"client error with retry", context.Background(),
+			GetAppErr(context.Background(), errors.New("slow down"), GetCustomErr("ERR_SYS_2", "throttled", true), 429).SetRetryAfter(1500 * time.Millisecond),
+			`host1 orders 42 ERR_SYS_2 [error@32473 code="ERR_SYS_2" codes="ERR_SYS_2" id="{id}"][http@32473 status="429"]` +
+				`[retry@32473 retryable="true" after="2"] throttled: slow down`},
+		{"identifiers", traced,
+			GetAppErr(context.Background(), errors.New("multi\nline"), GetCustomErr("ERR_SYS_3", "failed", false), 500),
+			`host1 orders 42 ERR_SYS_3 [error@32473 code="ERR_SYS_3" codes="ERR_SYS_3" id="{id}"][http@32473 status="500"]` +
+				`[retry@32473 retryable="false"][ids@32473 order_id="a\"b" user_id="7"] failed: multi line`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.Format(tt.ctx, tt.err)
+			wantPri := "<11>1 "
+			if tt.err.GetHTTPCode() < 500 {
+				wantPri = "<12>1 "
+			}
+			if !strings.HasPrefix(got, wantPri) {
+				t.Errorf("Format = %q, want priority %q", got, wantPri)
+			}
+			fields := strings.SplitN(got, " ", 3)
+			if _, err := time.Parse(time.RFC3339Nano, fields[1]); err != nil {
+				t.Errorf("timestamp %q: %v", fields[1], err)
+			}
+			if want := strings.ReplaceAll(tt.want, "{id}", tt.err.GetID()); fields[2] != want {
+				t.Errorf("Format = %q, want %q", fields[2], want)
+			}
+		})
+	}
+}
+
+func TestSyslogHeaderField(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"", 10, "-"},
+		{"  ", 10, "-"},
+		{"app name", 10, "appname"},
+		{"ünïcode", 10, "ncode"},
+		{"abcdefghij", 4, "abcd"},
+	}
+	for _, tt := range tests {
+		if got := syslogHeaderField(tt.in, tt.max); got != tt.want {
+			t.Errorf("syslogHeaderField(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
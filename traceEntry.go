@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// TraceEntry is a single step or error recorded in a TraceMeta
+type TraceEntry struct {
+	Time     time.Time  `json:"time"`
+	Message  string     `json:"message"`
+	Function string     `json:"function,omitempty"`
+	File     string     `json:"file,omitempty"`
+	Line     int        `json:"line,omitempty"`
+	Severity slog.Level `json:"severity"`
+}
+
+// String returns the message of the entry
+func (e TraceEntry) String() string {
+	return e.Message
+}
+
+// UnmarshalJSON also accepts the plain strings traces were recorded as before entries existed
+func (e *TraceEntry) UnmarshalJSON(b []byte) error {
+	var msg string
+	if err := json.Unmarshal(b, &msg); err == nil {
+		*e = TraceEntry{Message: msg}
+		return nil
+	}
+
+	type entry TraceEntry
+	return json.Unmarshal(b, (*entry)(e))
+}
+
+// newTraceEntry returns an entry stamped with the current time and the location of pc, if any
+func newTraceEntry(severity slog.Level, msg string, pc uintptr) TraceEntry {
+	entry := TraceEntry{Time: time.Now(), Message: msg, Severity: severity}
+	if pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		entry.Function, entry.File, entry.Line = frame.Function, frame.File, frame.Line
+	}
+	return entry
+}
+
+// traceMessages returns the messages of the entries
+func traceMessages(entries []TraceEntry) []string {
+	if entries == nil {
+		return nil
+	}
+	msgs := make([]string, len(entries))
+	for i, entry := range entries {
+		msgs[i] = entry.Message
+	}
+	return msgs
+}
+
+// stripTraceCallers removes the caller locations from entries, like stack traces in compliance mode
+func stripTraceCallers(entries []TraceEntry) {
+	for i := range entries {
+		entries[i].Function, entries[i].File, entries[i].Line = "", "", 0
+	}
+}
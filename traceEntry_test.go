@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceEntryUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    TraceEntry
+		wantErr bool
+	}{
+		{"plain string", `"db timeout"`, TraceEntry{Message: "db timeout"}, false},
+		{"entry", `{"message":"db timeout","function":"pkg.F","file":"f.go","line":7,"severity":"ERROR"}`,
+			TraceEntry{Message: "db timeout", Function: "pkg.F", File: "f.go", Line: 7, Severity: slog.LevelError}, false},
+		{"invalid", `42`, TraceEntry{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TraceEntry
+			err := got.UnmarshalJSON([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("UnmarshalJSON = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceEntryFields(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(ctx context.Context)
+		want string
+	}{
+		{"AddTraceLog", func(ctx context.Context) { AddTraceLog(ctx, "db timeout") }, "db timeout"},
+		{"AddTraceLogf", func(ctx context.Context) { AddTraceLogf(ctx, "db timeout after %dms", 50) }, "db timeout after 50ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ContextWithTrace(context.Background())
+			before := time.Now()
+			tt.add(ctx)
+
+			traceMeta, _ := TraceFromContext(ctx)
+			entries := traceMeta.Snapshot().Error
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			entry := entries[0]
+			if entry.Message != tt.want || entry.String() != tt.want {
+				t.Errorf("Message = %q, want %q", entry.Message, tt.want)
+			}
+			if entry.Severity != slog.LevelError {
+				t.Errorf("Severity = %v, want %v", entry.Severity, slog.LevelError)
+			}
+			if entry.Time.Before(before) {
+				t.Errorf("Time = %v, want at or after %v", entry.Time, before)
+			}
+			if filepath.Base(entry.File) != "traceEntry_test.go" || entry.Line == 0 || !strings.Contains(entry.Function, "TestTraceEntryFields") {
+				t.Errorf("caller = %s:%d %s, want this test", entry.File, entry.Line, entry.Function)
+			}
+			if lines := traceMeta.ErrorLines(); len(lines) != 1 || lines[0] != tt.want {
+				t.Errorf("ErrorLines = %v, want [%s]", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripTraceCallers(t *testing.T) {
+	entries := []TraceEntry{newTraceEntry(slog.LevelInfo, "step", caller(0))}
+	if entries[0].File == "" {
+		t.Fatal("newTraceEntry did not record the caller")
+	}
+	stripTraceCallers(entries)
+	if e := entries[0]; e.Function != "" || e.File != "" || e.Line != 0 || e.Message != "step" {
+		t.Errorf("stripTraceCallers left %+v", e)
+	}
+}
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// TranslationTable maps downstream error codes to custom errors of this service's catalog; a key
+// ending in "*" matches every code with that prefix
+type TranslationTable map[string]*CustomErr
+
+// TranslatorOption configures a Translator
+type TranslatorOption func(*Translator)
+
+// WithFallbackTranslation translates downstream codes missing from the table into customErr
+func WithFallbackTranslation(customErr *CustomErr) TranslatorOption {
+	return func(t *Translator) {
+		t.fallback = customErr
+	}
+}
+
+// prefixTranslation is a table entry matching a code prefix
+type prefixTranslation struct {
+	prefix    string
+	customErr *CustomErr
+}
+
+// Translator rewrites AppErrors received from downstream services into this service's own codes,
+// so internal codes of other services don't leak through public APIs verbatim
+type Translator struct {
+	exact    map[string]*CustomErr
+	prefixes []prefixTranslation // Longest prefix first
+	fallback *CustomErr
+}
+
+// NewTranslator creates a Translator from a declarative mapping table
+func NewTranslator(table TranslationTable, opts ...TranslatorOption) *Translator {
+	t := &Translator{exact: map[string]*CustomErr{}}
+	for code, customErr := range table {
+		if prefix, ok := strings.CutSuffix(code, "*"); ok {
+			t.prefixes = append(t.prefixes, prefixTranslation{prefix: prefix, customErr: customErr})
+			continue
+		}
+		t.exact[code] = customErr
+	}
+	sort.Slice(t.prefixes, func(i, j int) bool { return len(t.prefixes[i].prefix) > len(t.prefixes[j].prefix) })
+
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// lookup finds the custom error a downstream code translates to
+func (t *Translator) lookup(code string) (*CustomErr, bool) {
+	if customErr, ok := t.exact[code]; ok {
+		return customErr, true
+	}
+	for _, p := range t.prefixes {
+		if strings.HasPrefix(code, p.prefix) {
+			return p.customErr, true
+		}
+	}
+	if t.fallback != nil {
+		return t.fallback, true
+	}
+	return nil, false
+}
+
+// Translate rewrites a downstream AppError into a new AppError carrying only the translated code;
+// the HTTP code and retry hint are kept, downstream codes and data are dropped from the output
+// and the downstream error stays reachable as the underlying error. Errors without a matching
+// translation are returned unchanged.
+func (t *Translator) Translate(ctx context.Context, err error) error {
+	downstream, ok := asAppError(err)
+	if !ok {
+		return err
+	}
+	if translated, ok := t.translate(ctx, downstream, 2); ok {
+		return translated
+	}
+	return err
+}
+
+// translate implements Translate, recording the wrap site skip frames above translate
+func (t *Translator) translate(ctx context.Context, downstream *AppError, skip int) (*AppError, bool) {
+	if downstream.CustomErr == nil {
+		return nil, false
+	}
+	customErr, ok := t.lookup(downstream.CustomErr.Code)
+	if !ok {
+		return nil, false
+	}
+
+	AddTraceLog(ctx, "translated downstream code "+downstream.CustomErr.Code+" to "+customErr.Code)
+	return New(ctx, downstream, WithCustomErr(customErr), WithHTTPCode(downstream.httpCode),
+		WithRetryAfter(downstream.retryAfter), WithStackSkip(skip)), true
+}
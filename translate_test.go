@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTranslator(t *testing.T) {
+	notFound := GetCustomErr("ERR_ORD_1001", "order not found", false)
+	paymentFailed := GetCustomErr("ERR_ORD_1002", "payment failed", true)
+	cardDeclined := GetCustomErr("ERR_ORD_1003", "card declined", false)
+	upstream := GetCustomErr("ERR_ORD_1099", "upstream failure", true)
+
+	downstream := func(code string) error {
+		return GetAppErr(context.Background(), errors.New("downstream"), GetCustomErr(code, "downstream", true), http.StatusConflict).
+			SetRetryAfter(time.Second)
+	}
+	tests := []struct {
+		name     string
+		fallback *CustomErr
+		err      error
+		want     string // Expected primary code, empty when the error must come back unchanged
+	}{
+		{"exact", nil, downstream("ERR_INV_404"), notFound.Code},
+		{"prefix", nil, downstream("ERR_PAY_77"), paymentFailed.Code},
+		{"longest prefix", nil, downstream("ERR_PAY_CARD_1"), cardDeclined.Code},
+		{"no match", nil, downstream("ERR_OTHER_1"), ""},
+		{"fallback", upstream, downstream("ERR_OTHER_1"), upstream.Code},
+		{"plain error", upstream, errors.New("boom"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []TranslatorOption
+			if tt.fallback != nil {
+				opts = append(opts, WithFallbackTranslation(tt.fallback))
+			}
+			translator := NewTranslator(TranslationTable{
+				"ERR_INV_404":   notFound,
+				"ERR_PAY_*":     paymentFailed,
+				"ERR_PAY_CARD*": cardDeclined,
+			}, opts...)
+
+			got := translator.Translate(context.Background(), tt.err)
+			if tt.want == "" {
+				if got != tt.err {
+					t.Errorf("Translate = %v, want the error unchanged", got)
+				}
+				return
+			}
+			appErr, ok := asAppError(got)
+			if !ok {
+				t.Fatalf("Translate returned %T, want an AppError", got)
+			}
+			if appErr.GetErrCode() != tt.want || appErr.GetHTTPCode() != http.StatusConflict || appErr.GetRetryAfter() != time.Second {
+				t.Errorf("translated to %s %d retry %v, want %s 409 1s",
+					appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.GetRetryAfter(), tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Error("downstream error is no longer reachable")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrValidation is the underlying error of every ValidationError
+var ErrValidation = errors.New("validation failed")
+
+// FieldViolation describes why a single field failed validation
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Tag     string `json:"tag,omitempty"`   // Rule that failed, e.g. "email" or "min"
+	Param   string `json:"param,omitempty"` // Parameter of the rule, e.g. "8" for min=8
+}
+
+// ValidationError is an AppError accumulating per-field violations, serialized under data.fields.
+// Its HTTP code is 422 once it holds violations and 400 while it holds none, unless set explicitly
+type ValidationError struct {
+	*AppError
+	fields      []FieldViolation
+	fixedStatus bool // The HTTP code was set explicitly and is no longer derived from the fields
+}
+
+// NewValidationError creates an empty ValidationError with the ValidationFailed custom error
+func NewValidationError(ctx context.Context) *ValidationError {
+	v := &ValidationError{
+		AppError: newAppErr(ctx, ErrValidation, ValidationFailed, http.StatusBadRequest, 2),
+	}
+	v.sync()
+	return v
+}
+
+// AddField records that field failed validation with the given message and returns the
+// ValidationError, e.g. v.AddField("email", "invalid format")
+func (v *ValidationError) AddField(field, msg string) *ValidationError {
+	return v.AddViolation(FieldViolation{Field: field, Message: msg})
+}
+
+// AddViolation records a field violation, including the failed rule, and returns the ValidationError
+func (v *ValidationError) AddViolation(violation FieldViolation) *ValidationError {
+	v.fields = append(v.fields, violation)
+	v.sync()
+	return v
+}
+
+// Fields returns the recorded violations in the order they were added
+func (v *ValidationError) Fields() []FieldViolation {
+	return append([]FieldViolation(nil), v.fields...)
+}
+
+// HasFields reports whether any violation was recorded
+func (v *ValidationError) HasFields() bool {
+	return len(v.fields) > 0
+}
+
+// Err returns the ValidationError when it holds violations and nil otherwise, so validation code
+// can end with return v.Err()
+func (v *ValidationError) Err() error {
+	if !v.HasFields() {
+		return nil
+	}
+	return v
+}
+
+// SetHTTPCode sets the HTTP code explicitly, so it is no longer derived from the fields, and
+// returns the ValidationError
+func (v *ValidationError) SetHTTPCode(httpCode int) *ValidationError {
+	v.fixedStatus = true
+	v.AppError.SetHTTPCode(httpCode)
+	return v
+}
+
+// Error returns the validation failure followed by the violated fields
+func (v *ValidationError) Error() string {
+	if len(v.fields) == 0 {
+		return v.AppError.Error()
+	}
+	violations := make([]string, len(v.fields))
+	for i, f := range v.fields {
+		violations[i] = f.Field + ": " + f.Message
+	}
+	return v.AppError.Error() + ": " + strings.Join(violations, "; ")
+}
+
+// Format implements fmt.Formatter like AppError.Format, printing the violated fields as well
+func (v *ValidationError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, v.AppError.verboseString())
+			return
+		}
+		io.WriteString(s, v.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", v.Error())
+	default:
+		io.WriteString(s, v.Error())
+	}
+}
+
+// Unwrap returns the embedded AppError, so errors.As and the package helpers such as WriteError
+// find it
+func (v *ValidationError) Unwrap() error {
+	return v.AppError
+}
+
+// sync stores the violations as data.fields and derives the HTTP code from them
+func (v *ValidationError) sync() {
+	fields := append([]FieldViolation{}, v.fields...)
+	v.AppError.SetData(map[string]interface{}{"fields": fields})
+	if v.fixedStatus {
+		return
+	}
+	if len(v.fields) > 0 {
+		v.AppError.SetHTTPCode(http.StatusUnprocessableEntity)
+	} else {
+		v.AppError.SetHTTPCode(http.StatusBadRequest)
+	}
+}
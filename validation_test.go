@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      func(v *ValidationError)
+		wantStatus int
+		wantErr    string
+		wantFields []FieldViolation
+	}{
+		{"empty", func(*ValidationError) {}, http.StatusBadRequest, "", []FieldViolation{}},
+		{"fields", func(v *ValidationError) {
+			v.AddField("email", "invalid format").AddViolation(FieldViolation{Field: "password", Message: "too short", Tag: "min", Param: "8"})
+		}, http.StatusUnprocessableEntity, "validation failed: email: invalid format; password: too short",
+			[]FieldViolation{{Field: "email", Message: "invalid format"}, {Field: "password", Message: "too short", Tag: "min", Param: "8"}}},
+		{"explicit status kept", func(v *ValidationError) {
+			v.SetHTTPCode(http.StatusBadRequest).AddField("email", "required")
+		}, http.StatusBadRequest, "validation failed: email: required", []FieldViolation{{Field: "email", Message: "required"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidationError(context.Background())
+			tt.build(v)
+
+			if got := v.GetHTTPCode(); got != tt.wantStatus {
+				t.Errorf("GetHTTPCode = %d, want %d", got, tt.wantStatus)
+			}
+			if err := v.Err(); (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+				t.Errorf("Err = %v, want %q", err, tt.wantErr)
+			}
+			if got := fmt.Sprintf("%v", v); tt.wantErr != "" && got != tt.wantErr {
+				t.Errorf("%%v = %q, want %q", got, tt.wantErr)
+			}
+			if v.HasFields() != (len(tt.wantFields) > 0) {
+				t.Errorf("HasFields = %v, want %v", v.HasFields(), len(tt.wantFields) > 0)
+			}
+			if len(tt.wantFields) > 0 && !reflect.DeepEqual(v.Fields(), tt.wantFields) {
+				t.Errorf("Fields = %+v, want %+v", v.Fields(), tt.wantFields)
+			}
+
+			// The violations are written under data.fields
+			rec := httptest.NewRecorder()
+			WriteError(rec, nil, v)
+			var body struct {
+				Code string `json:"code"`
+				Data struct {
+					Fields []FieldViolation `json:"fields"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+			}
+			if rec.Code != tt.wantStatus || body.Code != ValidationFailed.Code || !reflect.DeepEqual(body.Data.Fields, tt.wantFields) {
+				t.Errorf("response %d %s, want %d with fields %+v", rec.Code, rec.Body.String(), tt.wantStatus, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidationErrorChain(t *testing.T) {
+	v := NewValidationError(context.Background()).AddField("email", "required")
+	wrapped := fmt.Errorf("create user: %w", v)
+
+	var appErr *AppError
+	if !errors.As(wrapped, &appErr) || appErr != v.AppError {
+		t.Error("errors.As did not find the embedded AppError")
+	}
+	if !errors.Is(wrapped, ErrValidation) {
+		t.Error("errors.Is(ErrValidation) = false")
+	}
+	fields := v.Fields()
+	fields[0].Field = "changed"
+	if v.Fields()[0].Field != "email" {
+		t.Error("Fields must return a copy")
+	}
+}
@@ -0,0 +1,152 @@
+package validatorae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Option configures how FromValidator maps field errors
+type Option func(*options)
+
+// options holds the settings collected from the Options passed to FromValidator
+type options struct {
+	translator ut.Translator
+	message    func(fe validator.FieldError) string
+	namespace  bool
+}
+
+// WithTranslator translates the messages with a translator registered with the validator, e.g.
+// through en_translations.RegisterDefaultTranslations
+func WithTranslator(translator ut.Translator) Option {
+	return func(o *options) {
+		o.translator = translator
+	}
+}
+
+// WithMessageFunc builds the messages with fn instead of the built-in English messages
+func WithMessageFunc(fn func(fe validator.FieldError) string) Option {
+	return func(o *options) {
+		o.message = fn
+	}
+}
+
+// WithNamespace names fields by their path below the validated struct, e.g. "address.city",
+// instead of by the field name alone
+func WithNamespace() Option {
+	return func(o *options) {
+		o.namespace = true
+	}
+}
+
+// FromValidator converts the error returned by validator.Struct into an *ae.ValidationError with
+// one violation per failed field, carrying the field, tag, param and message. Errors that are not
+// validation failures, such as validator.InvalidValidationError, become 500 InternalError
+// AppErrors, since they point at a programming error. It returns nil for a nil error
+func FromValidator(ctx context.Context, err error, opts ...Option) error {
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return ae.Wrap(ctx, err, ae.InternalError, http.StatusInternalServerError)
+	}
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	v := ae.NewValidationError(ctx)
+	for _, fe := range fieldErrs {
+		v.AddViolation(ae.FieldViolation{
+			Field:   o.field(fe),
+			Message: o.messageFor(fe),
+			Tag:     fe.Tag(),
+			Param:   fe.Param(),
+		})
+	}
+	return v
+}
+
+// field returns the name of the failed field
+func (o *options) field(fe validator.FieldError) string {
+	if !o.namespace {
+		return fe.Field()
+	}
+	// Drop the name of the validated struct itself
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+// messageFor returns the message of a field error
+func (o *options) messageFor(fe validator.FieldError) string {
+	switch {
+	case o.message != nil:
+		return o.message(fe)
+	case o.translator != nil:
+		return fe.Translate(o.translator)
+	default:
+		return Message(fe)
+	}
+}
+
+// Message returns the built-in English message for a field error, e.g. "must be at least 8
+// characters long" for min=8 on a string
+func Message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "uri", "http_url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "len":
+		return "must have a length of " + param
+	case "min", "gte":
+		return "must be at least " + param + sizeUnit(fe)
+	case "max", "lte":
+		return "must be at most " + param + sizeUnit(fe)
+	case "gt":
+		return "must be greater than " + param + sizeUnit(fe)
+	case "lt":
+		return "must be less than " + param + sizeUnit(fe)
+	case "eqfield":
+		return "must be equal to " + param
+	case "numeric", "number":
+		return "must be numeric"
+	}
+	if param != "" {
+		return fmt.Sprintf("failed on the '%s=%s' rule", fe.Tag(), param)
+	}
+	return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+}
+
+// sizeUnit returns the unit of a size constraint: characters for strings, items for collections
+func sizeUnit(fe validator.FieldError) string {
+	if fe.Type() == nil {
+		return ""
+	}
+	switch fe.Kind().String() {
+	case "string":
+		return " characters long"
+	case "slice", "array", "map":
+		return " items"
+	}
+	return ""
+}
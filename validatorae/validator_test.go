@@ -0,0 +1,102 @@
+package validatorae
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+type address struct {
+	City string `validate:"required"`
+}
+
+type signup struct {
+	Email    string   `validate:"required,email"`
+	Password string   `validate:"min=8"`
+	Role     string   `validate:"oneof=admin member"`
+	Tags     []string `validate:"max=2"`
+	Age      int      `validate:"gte=18"`
+	Code     string   `validate:"alpha"`
+	Address  address
+}
+
+// valid is a signup passing every rule
+func valid() signup {
+	return signup{Email: "ada@example.com", Password: "correct horse", Role: "admin", Age: 36, Code: "abc", Address: address{City: "London"}}
+}
+
+func TestFromValidator(t *testing.T) {
+	validate := validator.New()
+
+	tests := []struct {
+		name   string
+		modify func(s *signup)
+		opts   []Option
+		want   []ae.FieldViolation
+	}{
+		{"valid", func(*signup) {}, nil, nil},
+		{"built-in messages", func(s *signup) {
+			s.Email, s.Password, s.Role, s.Tags, s.Age, s.Code = "", "short", "root", []string{"a", "b", "c"}, 16, "a1"
+		}, nil, []ae.FieldViolation{
+			{Field: "Email", Message: "is required", Tag: "required"},
+			{Field: "Password", Message: "must be at least 8 characters long", Tag: "min", Param: "8"},
+			{Field: "Role", Message: "must be one of: admin, member", Tag: "oneof", Param: "admin member"},
+			{Field: "Tags", Message: "must be at most 2 items", Tag: "max", Param: "2"},
+			{Field: "Age", Message: "must be at least 18", Tag: "gte", Param: "18"},
+			{Field: "Code", Message: "failed on the 'alpha' rule", Tag: "alpha"},
+		}},
+		{"email format", func(s *signup) { s.Email = "ada" }, nil, []ae.FieldViolation{
+			{Field: "Email", Message: "must be a valid email address", Tag: "email"},
+		}},
+		{"nested field", func(s *signup) { s.Address.City = "" }, nil, []ae.FieldViolation{
+			{Field: "City", Message: "is required", Tag: "required"},
+		}},
+		{"namespace", func(s *signup) { s.Address.City = "" }, []Option{WithNamespace()}, []ae.FieldViolation{
+			{Field: "Address.City", Message: "is required", Tag: "required"},
+		}},
+		{"message func", func(s *signup) { s.Age = 3 }, []Option{WithMessageFunc(func(fe validator.FieldError) string {
+			return fe.Field() + " invalid"
+		})}, []ae.FieldViolation{
+			{Field: "Age", Message: "Age invalid", Tag: "gte", Param: "18"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.modify(&s)
+			err := FromValidator(context.Background(), validate.Struct(s), tt.opts...)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("FromValidator = %v, want nil", err)
+				}
+				return
+			}
+
+			var v *ae.ValidationError
+			if !errors.As(err, &v) {
+				t.Fatalf("FromValidator = %T %v, want a *ae.ValidationError", err, err)
+			}
+			if !reflect.DeepEqual(v.Fields(), tt.want) {
+				t.Errorf("Fields = %+v, want %+v", v.Fields(), tt.want)
+			}
+			if v.GetHTTPCode() != http.StatusUnprocessableEntity {
+				t.Errorf("GetHTTPCode = %d, want 422", v.GetHTTPCode())
+			}
+		})
+	}
+}
+
+func TestFromValidatorInvalidInput(t *testing.T) {
+	err := FromValidator(context.Background(), validator.New().Struct(42))
+
+	var appErr *ae.AppError
+	if !errors.As(err, &appErr) || appErr.GetErrCode() != ae.InternalError.Code || appErr.GetHTTPCode() != http.StatusInternalServerError {
+		t.Errorf("FromValidator = %v, want a 500 InternalError", err)
+	}
+}
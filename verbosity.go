@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"sync"
+)
+
+// VerbosityProvider decides whether a request gets debug error output; implement it on top of a
+// feature flag provider (e.g. OpenFeature) to target single users, tenants or headers
+type VerbosityProvider interface {
+	DebugOutput(r *http.Request) bool
+}
+
+// VerbosityProviderFunc adapts a function to the VerbosityProvider interface
+type VerbosityProviderFunc func(r *http.Request) bool
+
+// DebugOutput implements VerbosityProvider
+func (f VerbosityProviderFunc) DebugOutput(r *http.Request) bool {
+	return f(r)
+}
+
+// HeaderVerbosity enables debug output for requests carrying the given header with the given
+// secret value
+func HeaderVerbosity(header, secret string) VerbosityProvider {
+	return VerbosityProviderFunc(func(r *http.Request) bool {
+		value := r.Header.Get(header)
+		return secret != "" && subtle.ConstantTimeCompare([]byte(value), []byte(secret)) == 1
+	})
+}
+
+// verbosity holds the installed provider
+var verbosity struct {
+	sync.RWMutex
+	provider VerbosityProvider
+}
+
+// SetVerbosityProvider installs the provider consulted for every written error; nil disables it
+func SetVerbosityProvider(provider VerbosityProvider) {
+	verbosity.Lock()
+	verbosity.provider = provider
+	verbosity.Unlock()
+}
+
+// WithDebugOutput returns a copy of ctx switching the request into debug error output
+func WithDebugOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugOutputCtxKey, true)
+}
+
+// IsDebugOutput reports whether ctx was switched into debug error output
+func IsDebugOutput(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	debug, _ := ctx.Value(debugOutputCtxKey).(bool)
+	return debug
+}
+
+// VerbosityMiddleware evaluates the installed provider once per request and switches matching
+// requests into debug error output
+func VerbosityMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if debugRequested(r) {
+			r = r.WithContext(WithDebugOutput(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// debugRequested reports whether the request asked for debug error output
+func debugRequested(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	if IsDebugOutput(r.Context()) {
+		return true
+	}
+
+	verbosity.RLock()
+	provider := verbosity.provider
+	verbosity.RUnlock()
+	return provider != nil && provider.DebugOutput(r)
+}
+
+// debugInfo holds the full diagnostics added to responses in debug output
+type debugInfo struct {
+	Error      string            `json:"error"`
+	ID         string            `json:"id"`
+	HTTPCode   int               `json:"http_code"`
+	Data       interface{}       `json:"data,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Debug      interface{}       `json:"debug_data,omitempty"`
+	Trace      []TraceEntry      `json:"trace,omitempty"`
+	TraceError []TraceEntry      `json:"trace_errors,omitempty"`
+	Stack      []Frame           `json:"stack,omitempty"`
+	WrapSites  []Frame           `json:"wrap_sites,omitempty"`
+}
+
+// debugInfo collects the diagnostics of the AppError and the trace in ctx; stacks are left out in
+// compliance mode
+func (e *AppError) debugInfo(ctx context.Context) *debugInfo {
+	info := &debugInfo{
+		Error:    scrub(e.Error()),
+		ID:       e.GetID(),
+		HTTPCode: e.httpCode,
+		Data:     encodeData(ctx, e.data, false),
+		Labels:   e.labels,
+		Debug:    encodeData(ctx, e.debug, false),
+	}
+	if !currentConfig().ComplianceMode {
+		info.Stack = e.GetStackTrace()
+		info.WrapSites = e.GetWrapSites()
+	}
+	if traceMeta, ok := traceSnapshot(ctx); ok {
+		info.Trace = traceMeta.Trace
+		info.TraceError = traceMeta.Error
+		if currentConfig().ComplianceMode {
+			stripTraceCallers(info.Trace)
+			stripTraceCallers(info.TraceError)
+		}
+	}
+	return info
+}
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerbosityMiddleware(t *testing.T) {
+	appErr := GetAppErr(context.Background(), errors.New("db down"), GetCustomErr("ERR_VRB_1", "failed", false), 500)
+
+	tests := []struct {
+		name      string
+		provider  VerbosityProvider
+		header    string
+		wantDebug bool
+	}{
+		{"no provider", nil, "s3cret", false},
+		{"matching header", HeaderVerbosity("X-Debug", "s3cret"), "s3cret", true},
+		{"wrong secret", HeaderVerbosity("X-Debug", "s3cret"), "guess", false},
+		{"missing header", HeaderVerbosity("X-Debug", "s3cret"), "", false},
+		{"empty secret", HeaderVerbosity("X-Debug", ""), "", false},
+		{"flag provider", VerbosityProviderFunc(func(r *http.Request) bool { return r.URL.Query().Get("user") == "42" }), "", true},
+	}
+	defer SetVerbosityProvider(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetVerbosityProvider(tt.provider)
+			var gotDebug bool
+			handler := VerbosityMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotDebug = IsDebugOutput(r.Context())
+				appErr.WriteHTTP(w, r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/?user=42", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Debug", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if _, hasDebug := body["debug"]; gotDebug != tt.wantDebug || hasDebug != tt.wantDebug {
+				t.Errorf("debug output = %v, body has debug = %v, want %v", gotDebug, hasDebug, tt.wantDebug)
+			}
+		})
+	}
+}
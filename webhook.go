@@ -0,0 +1,295 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// ErrWebhookQueueFull is returned by WebhookNotifier.Report when the event had to be dropped
+var ErrWebhookQueueFull = errors.New("webhook queue is full")
+
+// WebhookOption configures a WebhookNotifier
+type WebhookOption func(*WebhookNotifier)
+
+// WithWebhookSecret signs every delivery with HMAC-SHA256 over "<timestamp>.<body>"; the hex digest
+// is sent as "sha256=<digest>" in the X-AppError-Signature header
+func WithWebhookSecret(secret string) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.secret = []byte(secret)
+	}
+}
+
+// WithWebhookClient sets the HTTP client used for deliveries, a client with a 10s timeout by default
+func WithWebhookClient(client *http.Client) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.client = client
+	}
+}
+
+// WithWebhookFilter sets which AppErrors are delivered, MinSeverity(SeverityError) by default
+func WithWebhookFilter(filter ErrorFilter) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.filter = filter
+	}
+}
+
+// WithWebhookSerializer sets the serializer rendering each delivered AppError, SerializerPublic by
+// default so the payload carries only what a client would see. SerializerRecording opts into full
+// recordings with the internal message, stack and trace of the request context
+func WithWebhookSerializer(name string) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.serializer = name
+	}
+}
+
+// WithWebhookBatching sets how many events are sent per request and how long events may wait
+// before being sent, 50 events and 5s by default
+func WithWebhookBatching(size int, interval time.Duration) WebhookOption {
+	return func(n *WebhookNotifier) {
+		if size > 0 {
+			n.batchSize = size
+		}
+		if interval > 0 {
+			n.flushInterval = interval
+		}
+	}
+}
+
+// WithWebhookRateLimit allows at most limit deliveries per period; events wait in the queue while
+// the limit is reached. Unlimited by default
+func WithWebhookRateLimit(limit int, per time.Duration) WebhookOption {
+	return func(n *WebhookNotifier) {
+		n.rateLimit = limit
+		n.ratePeriod = per
+	}
+}
+
+// WithWebhookQueueSize sets how many events may wait for delivery before new ones are dropped,
+// 1000 by default
+func WithWebhookQueueSize(size int) WebhookOption {
+	return func(n *WebhookNotifier) {
+		if size > 0 {
+			n.queueSize = size
+		}
+	}
+}
+
+// webhookPayload is the JSON body of a delivery
+type webhookPayload struct {
+	SentAt time.Time         `json:"sent_at"`
+	Errors []json.RawMessage `json:"errors"`
+}
+
+// WebhookNotifier is a Reporter that POSTs matching AppErrors to a webhook in signed, rate limited
+// batches, for lightweight alerting without a full APM stack
+type WebhookNotifier struct {
+	url           string
+	secret        []byte
+	client        *http.Client
+	filter        ErrorFilter
+	serializer    string
+	batchSize     int
+	flushInterval time.Duration
+	rateLimit     int
+	ratePeriod    time.Duration
+	queueSize     int
+
+	mu      sync.Mutex
+	queue   []json.RawMessage
+	sent    []time.Time // Delivery times within the current rate period
+	dropped int64
+	wake    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewWebhookNotifier creates a notifier delivering to url and starts its background goroutine;
+// call Close to deliver the remaining events on shutdown
+func NewWebhookNotifier(url string, opts ...WebhookOption) *WebhookNotifier {
+	n := &WebhookNotifier{
+		url:           url,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		filter:        MinSeverity(SeverityError),
+		serializer:    SerializerPublic,
+		batchSize:     50,
+		flushInterval: 5 * time.Second,
+		queueSize:     1000,
+		wake:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	go n.run()
+	return n
+}
+
+// Report serializes the AppError and queues it for delivery when it matches the filter
+func (n *WebhookNotifier) Report(ctx context.Context, appErr *AppError) error {
+	if appErr == nil || !n.filter(appErr) {
+		return nil
+	}
+	r, err := n.serialize(ctx, appErr)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	if len(n.queue) >= n.queueSize {
+		n.dropped++
+		n.mu.Unlock()
+		return ErrWebhookQueueFull
+	}
+	n.queue = append(n.queue, r)
+	full := len(n.queue) >= n.batchSize
+	n.mu.Unlock()
+
+	if full {
+		select {
+		case n.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// serialize renders the AppError with the configured serializer; recordings keep the trace of ctx
+func (n *WebhookNotifier) serialize(ctx context.Context, appErr *AppError) (json.RawMessage, error) {
+	if n.serializer == SerializerRecording {
+		traceMeta, _ := TraceFromContext(ctx)
+		return json.Marshal(newRecording(appErr, traceMeta))
+	}
+	return appErr.SerializeAs(n.serializer)
+}
+
+// Dropped returns how many events were dropped because the queue was full
+func (n *WebhookNotifier) Dropped() int64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.dropped
+}
+
+// Close stops the notifier and delivers the queued events, ignoring the rate limit, until done or
+// ctx is cancelled
+func (n *WebhookNotifier) Close(ctx context.Context) error {
+	n.once.Do(func() { close(n.stop) })
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run delivers queued events whenever a batch fills up or the flush interval elapses
+func (n *WebhookNotifier) run() {
+	defer close(n.done)
+
+	ticker := time.NewTicker(n.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.wake:
+			n.flush(false)
+		case <-ticker.C:
+			n.flush(false)
+		case <-n.stop:
+			n.flush(true)
+			return
+		}
+	}
+}
+
+// flush delivers queued events in batches while the rate limit allows, or all of them when final
+func (n *WebhookNotifier) flush(final bool) {
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 || (!final && !n.allowLocked(time.Now())) {
+			n.mu.Unlock()
+			return
+		}
+		size := n.batchSize
+		if size > len(n.queue) {
+			size = len(n.queue)
+		}
+		batch := n.queue[:size:size]
+		n.queue = n.queue[size:]
+		n.sent = append(n.sent, time.Now())
+		n.mu.Unlock()
+
+		if err := n.deliver(batch); err != nil {
+			getLogger().Error("webhook delivery failed", "host", sinkHost(n.url), "count", len(batch), "error", err)
+		}
+	}
+}
+
+// allowLocked reports whether another delivery fits the rate limit; callers must hold the lock
+func (n *WebhookNotifier) allowLocked(now time.Time) bool {
+	if n.rateLimit <= 0 || n.ratePeriod <= 0 {
+		return true
+	}
+	kept := n.sent[:0]
+	for _, at := range n.sent {
+		if now.Sub(at) < n.ratePeriod {
+			kept = append(kept, at)
+		}
+	}
+	n.sent = kept
+	return len(n.sent) < n.rateLimit
+}
+
+// deliver POSTs a single batch
+func (n *WebhookNotifier) deliver(batch []json.RawMessage) error {
+	body, err := json.Marshal(webhookPayload{SentAt: time.Now().UTC(), Errors: batch})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook endpoint for %s", sinkHost(n.url))
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(c.HeaderContentType, c.ContentTypeJSON)
+	req.Header.Set(c.HeaderWebhookTimestamp, timestamp)
+	if len(n.secret) > 0 {
+		req.Header.Set(c.HeaderWebhookSignature, "sha256="+SignWebhook(n.secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return sinkError(n.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>", letting receivers verify the
+// X-AppError-Signature header
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,120 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	c "github.com/piyushkumar96/app-error/constants"
+)
+
+// webhookSink records the bodies delivered to a test webhook and checks their signature
+type webhookSink struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (s *webhookSink) handler(t *testing.T, secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := "sha256=" + SignWebhook([]byte(secret), r.Header.Get(c.HeaderWebhookTimestamp), body)
+		if got := r.Header.Get(c.HeaderWebhookSignature); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		s.mu.Unlock()
+	})
+}
+
+func TestWebhookNotifierPayload(t *testing.T) {
+	critical := GetAppErr(context.Background(), errors.New("disk full"),
+		GetCustomErr("ERR_HOOK_1", "storage unavailable", false, WithSeverity(SeverityCritical)),
+		http.StatusServiceUnavailable).SetInternalMsg("volume /data at 100%")
+	warning := GetAppErr(context.Background(), errors.New("slow query"),
+		GetCustomErr("ERR_HOOK_2", "degraded", false, WithSeverity(SeverityWarn)), http.StatusOK)
+
+	tests := []struct {
+		name       string
+		opts       []WebhookOption
+		wantErrors int
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "public by default",
+			wantErrors: 1,
+			want:       []string{`"code":"ERR_HOOK_1"`, "storage unavailable"},
+			notWant:    []string{"ERR_HOOK_2", "disk full", "volume /data", `"stack"`},
+		},
+		{
+			name:       "recordings on opt-in",
+			opts:       []WebhookOption{WithWebhookSerializer(SerializerRecording)},
+			wantErrors: 1,
+			want:       []string{"ERR_HOOK_1", "disk full", "volume /data", critical.GetID()},
+			notWant:    []string{"ERR_HOOK_2"},
+		},
+		{
+			name:       "severity filter",
+			opts:       []WebhookOption{WithWebhookFilter(MinSeverity(SeverityWarn))},
+			wantErrors: 2,
+			want:       []string{"ERR_HOOK_1", "ERR_HOOK_2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &webhookSink{}
+			srv := httptest.NewServer(sink.handler(t, "s3cr3t"))
+			defer srv.Close()
+
+			opts := append([]WebhookOption{WithWebhookSecret("s3cr3t")}, tt.opts...)
+			notifier := NewWebhookNotifier(srv.URL, opts...)
+			for _, appErr := range []*AppError{critical, warning} {
+				if err := notifier.Report(context.Background(), appErr); err != nil {
+					t.Fatalf("Report: %v", err)
+				}
+			}
+			if err := notifier.Close(context.Background()); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if len(sink.bodies) != 1 {
+				t.Fatalf("got %d deliveries, want 1", len(sink.bodies))
+			}
+			body := sink.bodies[0]
+			var payload webhookPayload
+			if err := json.Unmarshal([]byte(body), &payload); err != nil {
+				t.Fatalf("decoding payload: %v", err)
+			}
+			if len(payload.Errors) != tt.wantErrors {
+				t.Errorf("payload holds %d errors, want %d", len(payload.Errors), tt.wantErrors)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(body, s) {
+					t.Errorf("payload misses %q: %s", s, body)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(body, s) {
+					t.Errorf("payload contains %q: %s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierUnknownSerializer(t *testing.T) {
+	notifier := NewWebhookNotifier("http://127.0.0.1:0", WithWebhookSerializer("missing"))
+	defer notifier.Close(context.Background())
+
+	err := notifier.Report(context.Background(), GetAppErr(context.Background(), errors.New("boom"), nil, 0))
+	if !errors.Is(err, ErrUnknownSerializer) {
+		t.Errorf("Report = %v, want %v", err, ErrUnknownSerializer)
+	}
+}
@@ -0,0 +1,150 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WireVersion is the version of the wire schema written by Marshal. Within a version fields are only
+// ever added, never renamed, removed or given a new meaning, so errors written by any release of
+// this package decode with any other release supporting the version; unknown fields are ignored
+const WireVersion = 1
+
+var (
+	// ErrInvalidWire is returned when decoding bytes that are not a wire encoded AppError
+	ErrInvalidWire = errors.New("invalid wire encoded error")
+	// ErrUnsupportedWireVersion is returned when decoding a wire version this release does not know
+	ErrUnsupportedWireVersion = errors.New("unsupported wire version")
+)
+
+// WireError is the stable wire schema of an AppError, exchanged between services
+type WireError struct {
+	Version      int                    `json:"v"`
+	ID           string                 `json:"id"`
+	Code         string                 `json:"code"`
+	Message      string                 `json:"message"`
+	ErrorCodes   []string               `json:"error_codes"`
+	HTTPCode     int                    `json:"http_code,omitempty"`
+	Retryable    bool                   `json:"retryable"`
+	Severity     Severity               `json:"severity,omitempty"`
+	Category     Category               `json:"category,omitempty"`
+	Err          string                 `json:"err,omitempty"`            // Text of the underlying error
+	Contexts     []string               `json:"contexts,omitempty"`       // Annotations added by WrapMsg, oldest first
+	Data         json.RawMessage        `json:"data,omitempty"`           // Client-facing data, see ToWire
+	RetryAfterMs int64                  `json:"retry_after_ms,omitempty"` // Retry delay in milliseconds
+	MaxAttempts  int                    `json:"max_attempts,omitempty"`
+	Backoff      BackoffStrategy        `json:"backoff,omitempty"`
+	TraceID      string                 `json:"trace_id,omitempty"`
+	SpanID       string                 `json:"span_id,omitempty"`
+	Fingerprint  string                 `json:"fingerprint,omitempty"`
+	Labels       map[string]string      `json:"labels,omitempty"`
+	Identifiers  map[string]interface{} `json:"identifiers,omitempty"`
+}
+
+// ToWire converts the AppError into its wire schema. Data is encoded client-facing, so values
+// classified internal or restricted never cross service boundaries or queues: the receiver decodes
+// plain JSON values and could not tell them apart anymore. The internal message, stack and debug
+// diagnostics stay in the process that created the error
+func (e *AppError) ToWire() *WireError {
+	w := &WireError{
+		Version:      WireVersion,
+		ID:           e.GetID(),
+		ErrorCodes:   append([]string{}, e.ErrorCodes...),
+		HTTPCode:     e.httpCode,
+		Severity:     e.GetSeverity(),
+		Category:     e.GetCategory(),
+		Contexts:     append([]string(nil), e.contexts...),
+		RetryAfterMs: e.retryAfter.Milliseconds(),
+		MaxAttempts:  e.maxAttempts,
+		Backoff:      e.backoff,
+		TraceID:      e.traceID,
+		SpanID:       e.spanID,
+		Fingerprint:  e.Fingerprint(),
+		Identifiers:  e.GetIdentifiers(),
+	}
+	if e.CustomErr != nil {
+		w.Code = e.CustomErr.Code
+		w.Message = scrub(e.CustomErr.Message)
+		w.Retryable = e.CustomErr.Retryable
+	}
+	if e.ActualErr != nil {
+		w.Err = scrub(e.ActualErr.Error())
+	}
+	if e.data != nil {
+		if raw, err := json.Marshal(encodeData(context.Background(), e.data, true)); err == nil {
+			w.Data = raw
+		}
+	}
+	for key, value := range e.labels {
+		if w.Labels == nil {
+			w.Labels = make(map[string]string, len(e.labels))
+		}
+		w.Labels[key] = value
+	}
+	return w
+}
+
+// AppError rebuilds an AppError from the wire schema; the underlying error only keeps its text and
+// data is decoded into generic JSON values
+func (w *WireError) AppError() *AppError {
+	appErr := &AppError{
+		CustomErr: &CustomErr{
+			Code:      w.Code,
+			Message:   w.Message,
+			Retryable: w.Retryable,
+			Severity:  w.Severity,
+			Category:  w.Category,
+		},
+		ErrorCodes:  append([]string{}, w.ErrorCodes...),
+		httpCode:    w.HTTPCode,
+		id:          w.ID,
+		contexts:    append([]string(nil), w.Contexts...),
+		retryAfter:  time.Duration(w.RetryAfterMs) * time.Millisecond,
+		maxAttempts: w.MaxAttempts,
+		backoff:     w.Backoff,
+		traceID:     w.TraceID,
+		spanID:      w.SpanID,
+		fingerprint: w.Fingerprint,
+		identifiers: w.Identifiers,
+	}
+	for key, value := range w.Labels {
+		appErr.SetLabel(key, value)
+	}
+	if w.Err != "" {
+		appErr.ActualErr = errors.New(w.Err)
+	}
+	if len(w.Data) > 0 {
+		var data interface{}
+		if err := json.Unmarshal(w.Data, &data); err == nil {
+			appErr.data = data
+		}
+	}
+	return appErr
+}
+
+// Marshal encodes the AppError with the versioned wire schema, for passing errors between services
+// through queues, RPC metadata or storage
+func Marshal(appErr *AppError) ([]byte, error) {
+	if appErr == nil {
+		return nil, fmt.Errorf("%w: nil AppError", ErrInvalidWire)
+	}
+	return json.Marshal(appErr.ToWire())
+}
+
+// Unmarshal decodes an AppError encoded by Marshal of any release writing a supported wire version
+func Unmarshal(data []byte) (*AppError, error) {
+	var w WireError
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWire, err)
+	}
+	switch {
+	case w.Version < 1:
+		return nil, fmt.Errorf("%w: missing or invalid version %d", ErrInvalidWire, w.Version)
+	case w.Version > WireVersion:
+		return nil, fmt.Errorf("%w: %d, supported up to %d", ErrUnsupportedWireVersion, w.Version, WireVersion)
+	}
+	return w.AppError(), nil
+}
@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// readGolden reads a fixture from testdata
+func readGolden(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	return raw
+}
+
+// canonicalJSON re-encodes raw so documents can be compared regardless of layout and key order
+func canonicalJSON(t *testing.T, raw []byte) string {
+	t.Helper()
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("decoding %s: %v", raw, err)
+	}
+	out, _ := json.Marshal(doc)
+	return string(out)
+}
+
+func TestUnmarshalGoldenV1(t *testing.T) {
+	appErr, err := Unmarshal(readGolden(t, "wire_v1.golden"))
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"id", appErr.GetID(), "5f43099202dd73d0"},
+		{"code", appErr.GetErrCode(), "ERR_ORDER_1001"},
+		{"message", appErr.GetMsg(), "order service unavailable"},
+		{"error codes", appErr.GetErrCodes(), []string{"ERR_DB_UNAVAILABLE", "ERR_ORDER_1001"}},
+		{"http code", appErr.GetHTTPCode(), 503},
+		{"retryable", appErr.IsRetryable(), true},
+		{"category", appErr.GetCategory(), CategoryUnavailable},
+		{"error", appErr.Error(), "loading order: dial tcp 10.0.0.7:5432: connection refused"},
+		{"data", appErr.GetData(), map[string]interface{}{"order_id": float64(42)}},
+		{"retry after", appErr.GetRetryAfter(), 1500 * time.Millisecond},
+		{"max attempts", appErr.GetMaxAttempts(), 4},
+		{"backoff", appErr.GetBackoff(), BackoffExponential},
+		{"trace id", appErr.GetTraceID(), "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"span id", appErr.GetSpanID(), "00f067aa0ba902b7"},
+		{"fingerprint", appErr.Fingerprint(), "9933ded2af09d327"},
+		{"tenant", appErr.GetTenant(), "acme"},
+		{"identifiers", appErr.GetIdentifiers(), map[string]interface{}{"request_id": "req-1"}},
+	}
+	for _, check := range checks {
+		if !reflect.DeepEqual(check.got, check.want) {
+			t.Errorf("%s = %#v, want %#v", check.name, check.got, check.want)
+		}
+	}
+}
+
+func TestMarshalGoldenV1RoundTrip(t *testing.T) {
+	golden := readGolden(t, "wire_v1.golden")
+	appErr, err := Unmarshal(golden)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	encoded, err := Marshal(appErr)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if got, want := canonicalJSON(t, encoded), canonicalJSON(t, golden); got != want {
+		t.Errorf("Marshal(Unmarshal(golden)) =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnmarshalIgnoresUnknownFields(t *testing.T) {
+	appErr, err := Unmarshal(readGolden(t, "wire_v1_unknown_fields.golden"))
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if appErr.GetErrCode() != "ERR_ORDER_1002" || appErr.GetHTTPCode() != 404 || appErr.IsRetryable() {
+		t.Errorf("decoded %s %d retryable=%v", appErr.GetErrCode(), appErr.GetHTTPCode(), appErr.IsRetryable())
+	}
+	if appErr.GetData() != nil {
+		t.Errorf("unknown fields leaked into data: %#v", appErr.GetData())
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	customErr := GetCustomErr("ERR_WIRE_1", "wire failed", true, WithCategory(CategoryTimeout))
+	original := New(WithTenant(context.Background(), "acme"), errors.New("boom"),
+		WithCustomErr(customErr),
+		WithData(map[string]interface{}{"id": 7, "secret": Classified(ClassInternal, "s3cr3t")}),
+		WithRetryAfter(2*time.Second), WithRetryMaxAttempts(3), WithInternalMsg("operator detail"))
+	original.WrapMsg("calling wire")
+
+	encoded, err := Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if bytes.Contains(encoded, []byte("s3cr3t")) || bytes.Contains(encoded, []byte("operator detail")) {
+		t.Errorf("internal details crossed the wire: %s", encoded)
+	}
+
+	decoded, err := Unmarshal(encoded)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.GetID() != original.GetID() || decoded.Fingerprint() != original.Fingerprint() {
+		t.Errorf("identity changed: %s/%s, want %s/%s",
+			decoded.GetID(), decoded.Fingerprint(), original.GetID(), original.Fingerprint())
+	}
+	if decoded.Error() != original.Error() {
+		t.Errorf("Error() = %q, want %q", decoded.Error(), original.Error())
+	}
+	if !reflect.DeepEqual(decoded.GetErrCodes(), original.GetErrCodes()) || decoded.GetHTTPCode() != original.GetHTTPCode() {
+		t.Errorf("codes %v/%d, want %v/%d",
+			decoded.GetErrCodes(), decoded.GetHTTPCode(), original.GetErrCodes(), original.GetHTTPCode())
+	}
+	if decoded.GetRetryAfter() != 2*time.Second || decoded.GetMaxAttempts() != 3 || decoded.GetTenant() != "acme" {
+		t.Errorf("retry %v/%d tenant %q", decoded.GetRetryAfter(), decoded.GetMaxAttempts(), decoded.GetTenant())
+	}
+}
+
+func TestUnmarshalRejectsVersions(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    error
+	}{
+		{`{"code":"ERR_A"}`, ErrInvalidWire},
+		{`{"v":0,"code":"ERR_A"}`, ErrInvalidWire},
+		{`{"v":-1,"code":"ERR_A"}`, ErrInvalidWire},
+		{`{"v":2,"code":"ERR_A"}`, ErrUnsupportedWireVersion},
+		{`not json`, ErrInvalidWire},
+	}
+	for _, tt := range tests {
+		if _, err := Unmarshal([]byte(tt.payload)); !errors.Is(err, tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.payload, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package zapfields
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// FromError expands err into zap fields (code, error_id, http_code, severity, error, message,
+// retryable, error_codes, data and stack for an AppError in the chain), named like the attributes
+// of ae.SlogAttrs so every logging stack shows the same keys
+func FromError(err error) []zap.Field {
+	attrs := ae.SlogAttrs(err)
+	fields := make([]zap.Field, len(attrs))
+	for i, a := range attrs {
+		fields[i] = field(a)
+	}
+	return fields
+}
+
+// Error returns a field logging err as a nested object under the "error" key, e.g.
+// logger.Error("order failed", zapfields.Error(err))
+func Error(err error) zap.Field {
+	return zap.Object("error", Marshaler(err))
+}
+
+// Marshaler returns a zapcore.ObjectMarshaler encoding err with the fields of FromError
+func Marshaler(err error) zapcore.ObjectMarshaler {
+	return errorMarshaler{err: err}
+}
+
+// errorMarshaler encodes an error as a zap object
+type errorMarshaler struct {
+	err error
+}
+
+// MarshalLogObject adds the fields of the error to enc
+func (m errorMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range FromError(m.err) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// groupMarshaler encodes a slog group as a zap object
+type groupMarshaler []slog.Attr
+
+// MarshalLogObject adds the attributes of the group to enc
+func (g groupMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		field(a).AddTo(enc)
+	}
+	return nil
+}
+
+// field converts a slog attribute into the zap field of the same kind
+func field(a slog.Attr) zap.Field {
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return zap.String(a.Key, value.String())
+	case slog.KindInt64:
+		return zap.Int64(a.Key, value.Int64())
+	case slog.KindUint64:
+		return zap.Uint64(a.Key, value.Uint64())
+	case slog.KindFloat64:
+		return zap.Float64(a.Key, value.Float64())
+	case slog.KindBool:
+		return zap.Bool(a.Key, value.Bool())
+	case slog.KindDuration:
+		return zap.Duration(a.Key, value.Duration())
+	case slog.KindTime:
+		return zap.Time(a.Key, value.Time())
+	case slog.KindGroup:
+		return zap.Object(a.Key, groupMarshaler(value.Group()))
+	default:
+		return zap.Any(a.Key, value.Any())
+	}
+}
@@ -0,0 +1,64 @@
+package zapfields
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+func TestFromError(t *testing.T) {
+	ctx := context.Background()
+	busy := ae.GetCustomErr("ERR_ZAP_1", "try again", true)
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]interface{} // Subset of the encoded fields
+	}{
+		{"nil", nil, map[string]interface{}{}},
+		{"plain error", errors.New("boom"), map[string]interface{}{"error": "boom"}},
+		{"AppError", ae.GetAppErr(ctx, errors.New("busy"), busy, 503),
+			map[string]interface{}{"code": "ERR_ZAP_1", "http_code": int64(503), "retryable": true, "message": "try again", "severity": "error"}},
+		{"wrapped AppError", fmt.Errorf("handler: %w", ae.GetAppErr(ctx, errors.New("busy"), busy, 503)),
+			map[string]interface{}{"code": "ERR_ZAP_1", "http_code": int64(503)}},
+		{"trace IDs", ae.GetAppErr(ctx, errors.New("busy"), busy, 503).SetTraceIDs("4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"),
+			map[string]interface{}{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"}},
+		{"data", ae.GetAppErr(ctx, errors.New("busy"), busy, 503, map[string]interface{}{"password": "hunter2"}),
+			map[string]interface{}{"data": map[string]interface{}{"password": "[REDACTED]"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := zapcore.NewMapObjectEncoder()
+			if err := Marshaler(tt.err).MarshalLogObject(enc); err != nil {
+				t.Fatalf("MarshalLogObject: %v", err)
+			}
+			if len(tt.want) == 0 && len(enc.Fields) != 0 {
+				t.Errorf("fields = %v, want none", enc.Fields)
+			}
+			for key, want := range tt.want {
+				if got := enc.Fields[key]; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", key, got, want)
+				}
+			}
+			if got, want := len(FromError(tt.err)), len(enc.Fields); got != want {
+				t.Errorf("FromError returned %d fields, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	Error(ae.GetAppErr(context.Background(), errors.New("busy"), ae.GetCustomErr("ERR_ZAP_2", "try again", true), 503)).AddTo(enc)
+
+	nested, ok := enc.Fields["error"].(map[string]interface{})
+	if !ok || nested["code"] != "ERR_ZAP_2" {
+		t.Errorf("error field = %#v, want an object with the code", enc.Fields["error"])
+	}
+}
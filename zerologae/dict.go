@@ -0,0 +1,49 @@
+package zerologae
+
+import (
+	"log/slog"
+
+	"github.com/rs/zerolog"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+// Dict returns a zerolog dictionary describing err with the attributes of ae.SlogAttrs (code,
+// error_id, http_code, error_codes, data, stack, ...), e.g.
+// log.Error().Dict("error", zerologae.Dict(err)).Msg("order failed")
+func Dict(err error) *zerolog.Event {
+	dict := zerolog.Dict()
+	for _, a := range ae.SlogAttrs(err) {
+		addAttr(dict, a)
+	}
+	return dict
+}
+
+// addAttr adds a slog attribute to a zerolog event as the value of the same kind
+func addAttr(e *zerolog.Event, a slog.Attr) {
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		e.Str(a.Key, value.String())
+	case slog.KindInt64:
+		e.Int64(a.Key, value.Int64())
+	case slog.KindUint64:
+		e.Uint64(a.Key, value.Uint64())
+	case slog.KindFloat64:
+		e.Float64(a.Key, value.Float64())
+	case slog.KindBool:
+		e.Bool(a.Key, value.Bool())
+	case slog.KindDuration:
+		e.Dur(a.Key, value.Duration())
+	case slog.KindTime:
+		e.Time(a.Key, value.Time())
+	case slog.KindGroup:
+		group := zerolog.Dict()
+		for _, ga := range value.Group() {
+			addAttr(group, ga)
+		}
+		e.Dict(a.Key, group)
+	default:
+		e.Interface(a.Key, value.Any())
+	}
+}
@@ -0,0 +1,93 @@
+package zerologae
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	ae "github.com/piyushkumar96/app-error"
+)
+
+func TestDict(t *testing.T) {
+	ctx := context.Background()
+	busy := ae.GetCustomErr("ERR_ZRL_1", "try again", true)
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]interface{} // Subset of the logged dictionary
+	}{
+		{"nil", nil, map[string]interface{}{}},
+		{"plain error", errors.New("boom"), map[string]interface{}{"error": "boom"}},
+		{"AppError", ae.GetAppErr(ctx, errors.New("busy"), busy, 503),
+			map[string]interface{}{"code": "ERR_ZRL_1", "http_code": float64(503), "retryable": true, "error_codes": []interface{}{"ERR_ZRL_1"}}},
+		{"wrapped AppError", fmt.Errorf("handler: %w", ae.GetAppErr(ctx, errors.New("busy"), busy, 503)),
+			map[string]interface{}{"code": "ERR_ZRL_1"}},
+		{"data", ae.GetAppErr(ctx, errors.New("busy"), busy, 503, map[string]interface{}{"token": "t1"}),
+			map[string]interface{}{"data": map[string]interface{}{"token": "[REDACTED]"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			logger.Error().Dict("error", Dict(tt.err)).Send()
+
+			var record struct {
+				Error map[string]interface{} `json:"error"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decoding %q: %v", buf.String(), err)
+			}
+			if len(tt.want) == 0 && len(record.Error) != 0 {
+				t.Errorf("error = %v, want an empty dictionary", record.Error)
+			}
+			for key, want := range tt.want {
+				if got := record.Error[key]; !reflect.DeepEqual(got, want) {
+					t.Errorf("error.%s = %#v, want %#v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAddAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want interface{}
+	}{
+		{"string", slog.String("v", "s"), "s"},
+		{"int", slog.Int64("v", -3), float64(-3)},
+		{"uint", slog.Uint64("v", 3), float64(3)},
+		{"float", slog.Float64("v", 1.5), 1.5},
+		{"bool", slog.Bool("v", true), true},
+		{"duration", slog.Duration("v", 2*time.Millisecond), float64(2)},
+		{"group", slog.Group("v", slog.String("k", "x")), map[string]interface{}{"k": "x"}},
+		{"any", slog.Any("v", []int{1}), []interface{}{float64(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf)
+			event := logger.Log()
+			addAttr(event, tt.attr)
+			event.Send()
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("decoding %q: %v", buf.String(), err)
+			}
+			if got := record["v"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("v = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}